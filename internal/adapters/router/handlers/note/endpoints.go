@@ -1,9 +1,10 @@
 package note
 
 import (
-	"encoding/json"
+	e "errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -22,23 +23,20 @@ func (h *Handler) CreateNote(w http.ResponseWriter, r *http.Request, _ httproute
 	ctx := r.Context()
 
 	newNote := &model.Note{}
-	if err := json.NewDecoder(r.Body).Decode(&newNote); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &newNote); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
 	err := validate.InputJSONValidate(newNote)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	note, err := h.service.Note.CreateNote(newNote, userID)
+	note, err := h.service.Note.CreateNote(ctx, newNote, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, newNote.Title)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -54,31 +52,76 @@ func (h *Handler) GetNoteByID(w http.ResponseWriter, r *http.Request, ps httprou
 	noteID := ps.ByName("id")
 	ctx := r.Context()
 
-	note, err := h.service.Note.GetNoteByID(noteID, userID)
+	note, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", functions.ETag(note.Version))
+
+	if functions.CheckIfNoneMatch(r.Header.Get("If-None-Match"), note.Version) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	functions.MakeJSONResponse(w, http.StatusOK, note)
 }
 
-// GetAllNotesByUser get all notes by user.
+// GetAllNotesByUser get page of notes by user.
 func (h *Handler) GetAllNotesByUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	userID := r.Header.Get("user_id")
 	ctx := r.Context()
 
-	notes, err := h.service.Note.GetAllNotesByUser(userID)
+	listQuery, err := functions.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if listQuery.Tags, err = functions.ParseTagsExpr(r.URL.Query()); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if listQuery.TagDescendants, err = functions.ParseIncludeDescendants(r.URL.Query()); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	page, err := h.service.Note.GetNotesPage(ctx, userID, listQuery)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	if len(notes) == 0 {
+	if page.Total == 0 {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotesListEmpty, "", "")
-		logger.LogFromContext(ctx).Error(errors.ErrNotesListEmpty.Error())
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, page)
+}
+
+// SearchNotes full-text search of notes by user, found notes are ranked by relevance.
+func (h *Handler) SearchNotes(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	searchQuery, err := functions.ParseNoteSearchQuery(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	notes, err := h.service.Note.SearchNotes(ctx, userID, searchQuery)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(notes) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotesSearchEmpty, "", "")
 		return
 	}
 
@@ -92,49 +135,219 @@ func (h *Handler) UpdateNote(w http.ResponseWriter, r *http.Request, ps httprout
 	ctx := r.Context()
 
 	newNote := &dto.NoteUpdate{}
-	if err := json.NewDecoder(r.Body).Decode(&newNote); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &newNote); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(newNote); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	_, err := h.service.Note.GetNoteByID(noteID, userID)
+	note, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	err = h.service.Note.UpdateNote(newNote, noteID)
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), note.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	newVersion, err := h.service.Note.UpdateNote(ctx, newNote, noteID, userID, version)
+	if err != nil && newNote.Title != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, *newNote.Title)
+		return
+	}
+
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", functions.ETag(newVersion))
+
 	resp := make(map[string]string)
 	resp["Updated note with id"] = noteID
 
 	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
-// DeleteNote delete note by ID.
+// PatchNote patch note by ID with JSON Merge Patch or JSON Patch, patched note is validated before update.
+func (h *Handler) PatchNote(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID := ps.ByName("id")
+	ctx := r.Context()
+
+	note, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), note.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	patched := &dto.NotePatch{Title: note.Title, Info: note.Info}
+	if err := validate.DecodePatch(r.Header.Get("Content-Type"), r.Body, patched); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(patched); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	// only changed fields are updated
+	newNote := &dto.NoteUpdate{}
+	if patched.Title != note.Title {
+		newNote.Title = &patched.Title
+	}
+
+	if patched.Info != note.Info {
+		newNote.Info = &patched.Info
+	}
+
+	newVersion, err := h.service.Note.UpdateNote(ctx, newNote, noteID, userID, version)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, patched.Title)
+		return
+	}
+
+	w.Header().Set("ETag", functions.ETag(newVersion))
+
+	resp := make(map[string]string)
+	resp["Updated note with id"] = noteID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// GetNoteRevisions get revisions of note by ID, the latest revisions are first.
+func (h *Handler) GetNoteRevisions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID := ps.ByName("id")
+	ctx := r.Context()
+
+	_, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	revisions, err := h.service.Note.GetNoteRevisions(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(revisions) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrRevisionsListEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, revisions)
+}
+
+// GetNoteRevision get revision of note by ID and revision number.
+func (h *Handler) GetNoteRevision(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID, rev := ps.ByName("id"), ps.ByName("rev")
+	ctx := r.Context()
+
+	_, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	revision, err := h.service.Note.GetNoteRevision(ctx, noteID, rev, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Revision, rev)
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, revision)
+}
+
+// DiffNoteRevision unified diff of note revision and revision from query, current note without it.
+func (h *Handler) DiffNoteRevision(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID, rev := ps.ByName("id"), ps.ByName("rev")
+	ctx := r.Context()
+
+	to := r.URL.Query().Get(dictionary.RevisionToParam)
+	if to != "" && !functions.CheckID(to) {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, fmt.Errorf("%w '%s': must be a revision number",
+			errors.ErrInvalidQueryParam, dictionary.RevisionToParam), "", "")
+		return
+	}
+
+	_, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	diff, missingRev, err := h.service.Note.DiffNoteRevision(ctx, noteID, rev, to, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Revision, missingRev)
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, diff)
+}
+
+// RestoreNoteRevision set title and info of note by ID from its revision.
+func (h *Handler) RestoreNoteRevision(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID, rev := ps.ByName("id"), ps.ByName("rev")
+	ctx := r.Context()
+
+	_, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	if err := h.service.Note.RestoreNoteRevision(ctx, noteID, rev, userID); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Revision, rev)
+		return
+	}
+
+	resp := make(map[string]string)
+	resp[fmt.Sprintf("Restored note with id '%s' to revision", noteID)] = rev
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// DeleteNote move note by ID to trash.
 func (h *Handler) DeleteNote(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	userID := r.Header.Get("user_id")
 	noteID := ps.ByName("id")
 	ctx := r.Context()
 
-	_, err := h.service.Note.GetNoteByID(noteID, userID)
+	note, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	id, err := h.service.Note.DeleteNote(noteID, userID)
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), note.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	id, err := h.service.Note.DeleteNote(ctx, noteID, userID, version)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -144,116 +357,203 @@ func (h *Handler) DeleteNote(w http.ResponseWriter, r *http.Request, ps httprout
 	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
-// SetTags set tags to note.
-func (h *Handler) SetTags(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// GetTrash get trashed notes by user.
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	notes, err := h.service.Note.GetTrash(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(notes) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrTrashEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, notes)
+}
+
+// RestoreNote restore note by ID from trash.
+func (h *Handler) RestoreNote(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	userID := r.Header.Get("user_id")
 	noteID := ps.ByName("id")
-	tags := r.URL.Query()
 	ctx := r.Context()
 
-	note, err := h.service.Note.GetNoteByID(noteID, userID)
+	id, err := h.service.Note.RestoreNote(ctx, noteID, userID)
 	if err != nil {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.TrashedNote, noteID)
 		return
 	}
 
-	tagsMap := make(map[string]string)
+	resp := make(map[string]int)
+	resp["Restored note with id"] = id
 
-	for _, tagID := range tags["tag"] {
-		tag, err := h.service.Tag.GetTagByID(tagID, userID)
-		if err != nil {
-			functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-			logger.LogFromContext(ctx).Error(err.Error())
-			return
-		}
-		tagsMap[tagID] = tag.TagName
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// PurgeNote delete trashed note by ID permanently.
+func (h *Handler) PurgeNote(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID := ps.ByName("id")
+	ctx := r.Context()
+
+	id, err := h.service.Note.PurgeNote(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.TrashedNote, noteID)
+		return
 	}
 
-	tagID, err := h.service.Note.SetTags(noteID, tagsMap)
+	resp := make(map[string]int)
+	resp["Purged note with id"] = id
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// UpdateNoteTags replace tags of note or add and remove them in one transaction.
+func (h *Handler) UpdateNoteTags(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID := ps.ByName("id")
+	ctx := r.Context()
+
+	update := &dto.NoteTagsUpdate{}
+	if err := validate.DecodeJSON(r.Body, &update); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	err := validate.InputJSONValidate(update)
 	if err != nil {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	resp := make(map[string]map[string]string)
-	resp[fmt.Sprintf("To note '%s' set tags", note.Title)] = tagsMap
+	_, err = h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	resp, tagID, err := h.service.Note.UpdateNoteTags(ctx, noteID, userID, update)
+	if e.Is(err, errors.ErrTagsAddRemove) {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, fmt.Errorf("%w: %s", err, tagID), "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
+		return
+	}
 
 	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
+// SetTags set tags to note.
+func (h *Handler) SetTags(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	h.updateTagsByQuery(w, r, ps, "To note '%s' set tags", func(ids []int64) *dto.NoteTagsUpdate {
+		return &dto.NoteTagsUpdate{Add: ids}
+	})
+}
+
 // RemoveTags remove tags from note.
 func (h *Handler) RemoveTags(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	h.updateTagsByQuery(w, r, ps, "From note '%s' deleted tags", func(ids []int64) *dto.NoteTagsUpdate {
+		return &dto.NoteTagsUpdate{Remove: ids}
+	})
+}
+
+// updateTagsByQuery add or remove tags given by tag query params, response key is respFormat with note title.
+func (h *Handler) updateTagsByQuery(w http.ResponseWriter, r *http.Request, ps httprouter.Params, respFormat string,
+	makeUpdate func(ids []int64) *dto.NoteTagsUpdate,
+) {
 	userID := r.Header.Get("user_id")
 	noteID := ps.ByName("id")
 	tags := r.URL.Query()
 	ctx := r.Context()
 
-	note, err := h.service.Note.GetNoteByID(noteID, userID)
+	note, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
 	tagsMap := make(map[string]string)
+	ids := make([]int64, 0, len(tags["tag"]))
 
 	for _, tagID := range tags["tag"] {
-		tag, err := h.service.Tag.GetTagByID(tagID, userID)
+		tag, err := h.service.Tag.GetTagByID(ctx, tagID, userID)
 		if err != nil {
 			functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-			logger.LogFromContext(ctx).Error(err.Error())
 			return
 		}
 		tagsMap[tagID] = tag.TagName
+
+		id, _ := strconv.ParseInt(tagID, 10, 64)
+		ids = append(ids, id)
 	}
 
-	tagID, err := h.service.Note.RemoveTags(noteID, tagsMap)
+	_, tagID, err := h.service.Note.UpdateNoteTags(ctx, noteID, userID, makeUpdate(ids))
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
 	resp := make(map[string]map[string]string)
-	resp[fmt.Sprintf("From note '%s' deleted tags", note.Title)] = tagsMap
+	resp[fmt.Sprintf(respFormat, note.Title)] = tagsMap
 
 	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
-// GetAllNotesWithTags get all notes with tags by user.
+// GetAllNotesWithTags stream all notes with tags by user.
 func (h *Handler) GetAllNotesWithTags(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	userID := r.Header.Get("user_id")
 	ctx := r.Context()
 
-	notes, err := h.service.Note.GetAllNotesByUser(userID)
+	listQuery, err := functions.ParseListQuery(r.URL.Query())
 	if err != nil {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	if len(notes) == 0 {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotesListEmpty, "", "")
-		logger.LogFromContext(ctx).Error(errors.ErrNotesListEmpty.Error())
+	if listQuery.Tags, err = functions.ParseTagsExpr(r.URL.Query()); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	notesResp, err := h.service.Note.GetAllNotesWithTags(userID, notes)
-	if err != nil {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
+	if listQuery.TagDescendants, err = functions.ParseIncludeDescendants(r.URL.Query()); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	if len(notesResp) == 0 {
-		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotesListWithTagsEmpty, "", "")
-		logger.LogFromContext(ctx).Error(errors.ErrNotesListWithTagsEmpty.Error())
+	includeUntagged, err := functions.ParseIncludeUntagged(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	functions.MakeJSONResponse(w, http.StatusOK, notesResp)
+	notes := functions.NewJSONArrayWriter(w, http.StatusOK)
+
+	err = h.service.Note.GetAllNotesWithTags(ctx, userID, listQuery, includeUntagged,
+		func(note *dto.NoteWithTagsResp) error {
+			return notes.Write(note)
+		},
+	)
+
+	switch {
+	case err != nil && notes.Count() == 0:
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+	case err != nil:
+		// status is already sent, the client gets truncated array
+		logger.LogFromContext(ctx).Error(err.Error())
+	case notes.Count() == 0 && includeUntagged:
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, errors.ErrNotesListEmpty, "", "")
+	case notes.Count() == 0:
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, errors.ErrNotesListWithTagsEmpty, "", "")
+	default:
+		notes.Close() //nolint:errcheck,gosec
+	}
 }
 
 // GetNoteWithAllTags get note by id with all tags by user.
@@ -262,17 +562,14 @@ func (h *Handler) GetNoteWithAllTags(w http.ResponseWriter, r *http.Request, ps
 	noteID := ps.ByName("id")
 	ctx := r.Context()
 
-	note, err := h.service.Note.GetNoteByID(noteID, userID)
-	if err != nil {
+	if _, err := h.service.Note.GetNoteByID(ctx, noteID, userID); err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	noteResp, err := h.service.Note.GetNoteWithAllTags(userID, noteID, note)
+	noteResp, err := h.service.Note.GetNoteWithAllTags(ctx, userID, noteID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
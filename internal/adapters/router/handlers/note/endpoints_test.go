@@ -2,11 +2,14 @@ package note
 
 import (
 	"bytes"
+	"context"
 	e "errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/julienschmidt/httprouter"
@@ -19,9 +22,17 @@ import (
 	"web/internal/domain/services"
 	"web/internal/domain/services/mocks"
 	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
 	l "web/pkg/logger"
+	"web/pkg/tagquery"
 )
 
+// testTime created_at and updated_at of service responses.
+var testTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// testRequestID request id of test requests, error responses have it.
+const testRequestID = "test-request-id"
+
 func TestHandler_CreateNote(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockNoteService, note *model.Note, userID string)
@@ -55,7 +66,7 @@ func TestHandler_CreateNote(t *testing.T) {
 					Title: "test_title",
 					Info:  "test_info",
 				}
-				s.EXPECT().CreateNote(note, userID).Return(outputNote, nil)
+				s.EXPECT().CreateNote(gomock.Any(), note, userID).Return(outputNote, nil)
 			},
 			expectedStatusCode: http.StatusCreated,
 			expectedResponse: `{"Created note 'test_title' with id":"1"}
@@ -69,7 +80,7 @@ func TestHandler_CreateNote(t *testing.T) {
 			inputNote:          &model.Note{},
 			mockBehavior:       func(s *mock_services.MockNoteService, note *model.Note, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -78,7 +89,7 @@ func TestHandler_CreateNote(t *testing.T) {
 			inputNote:          &model.Note{},
 			mockBehavior:       func(s *mock_services.MockNoteService, note *model.Note, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `Key: 'Note.Title' Error:Field validation for 'Title' failed on the 'required' tag
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"title","code":"required","message":"title is required"},{"field":"info","code":"required","message":"info is required"}]}
 `,
 			testName: "test-3-Handler:Validation Err",
 		},
@@ -96,10 +107,10 @@ func TestHandler_CreateNote(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockNoteService, note *model.Note, userID string) {
 				// service response
-				s.EXPECT().CreateNote(note, userID).Return(nil, e.New(errors.ErrDBDuplicate))
+				s.EXPECT().CreateNote(gomock.Any(), note, userID).Return(nil, errors.ErrConflict)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"note 'test_title' is already exists"}
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"note 'test_title' is already exists","code":"conflict","request_id":"test-request-id"}
 `,
 			testName: "test-4-Service:Note is already exists Err",
 		},
@@ -117,10 +128,10 @@ func TestHandler_CreateNote(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockNoteService, note *model.Note, userID string) {
 				// service response
-				s.EXPECT().CreateNote(note, userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().CreateNote(gomock.Any(), note, userID).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-5-Service:Db resp Err",
 		},
@@ -146,6 +157,7 @@ func TestHandler_CreateNote(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, dictionary.NotesURL, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -164,8 +176,10 @@ func TestHandler_GetNoteByID(t *testing.T) {
 		headerName         string
 		headerValue        string
 		inputNote          string
+		ifNoneMatch        string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
+		expectedETag       string
 		expectedResponse   string
 		testName           string
 	}{
@@ -177,13 +191,17 @@ func TestHandler_GetNoteByID(t *testing.T) {
 			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
 				// service response
 				outputNote := &dto.NoteResp{
-					Title: "test_title",
-					Info:  "test_info",
+					Title:     "test_title",
+					Info:      "test_info",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+					Version:   3,
 				}
-				s.EXPECT().GetNoteByID(noteID, userID).Return(outputNote, nil)
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(outputNote, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"title":"test_title","info":"test_info"}
+			expectedETag:       `"3"`,
+			expectedResponse: `{"title":"test_title","info":"test_info","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -194,10 +212,10 @@ func TestHandler_GetNoteByID(t *testing.T) {
 			inputNote:   "1",
 			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No note with id '1'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Note not found",
 		},
@@ -208,13 +226,28 @@ func TestHandler_GetNoteByID(t *testing.T) {
 			inputNote:   "1",
 			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			ifNoneMatch: `"2", W/"3"`,
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 3}, nil)
+			},
+			expectedStatusCode: http.StatusNotModified,
+			expectedETag:       `"3"`,
+			expectedResponse:   "",
+			testName:           "test-4-Handler:Not modified",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -237,11 +270,14 @@ func TestHandler_GetNoteByID(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%s", testCase.inputNote), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-None-Match", testCase.ifNoneMatch)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
 			require.Equal(t, testCase.expectedResponse, w.Body.String())
 		})
 	}
@@ -251,9 +287,25 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockNoteService, userID string)
 
+	pageQuery := func(listQuery dto.ListQuery) *dto.ListQuery {
+		if listQuery.Sort == "" {
+			listQuery.Sort = dictionary.SortID
+		}
+
+		if listQuery.Limit == 0 {
+			listQuery.Limit = dictionary.DefaultPageLimit
+		}
+
+		return &listQuery
+	}
+
+	cursorTitle := "test_title1"
+	cursor, _ := functions.EncodeCursor(&dto.Cursor{Sort: dictionary.SortTitle, ID: 1, Title: &cursorTitle})
+
 	testTable := []struct {
 		headerName         string
 		headerValue        string
+		query              string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -265,17 +317,22 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				outputTag := []dto.NotesResp{
-					{
-						ID:    "1",
-						Title: "test_title1",
-						Info:  "test_info1",
+				outputNotes := &dto.NotesPage{
+					Notes: []dto.NotesResp{
+						{
+							ID:        "1",
+							Title:     "test_title1",
+							Info:      "test_info1",
+							CreatedAt: testTime,
+							UpdatedAt: testTime,
+						},
 					},
+					Total: 1,
 				}
-				s.EXPECT().GetAllNotesByUser(userID).Return(outputTag, nil)
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(dto.ListQuery{})).Return(outputNotes, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `[{"id":"1","title":"test_title1","info":"test_info1"}]
+			expectedResponse: `{"notes":[{"id":"1","title":"test_title1","info":"test_info1","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}],"total":1}
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -285,10 +342,10 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetAllNotesByUser(userID).Return(nil, nil)
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(dto.ListQuery{})).Return(&dto.NotesPage{}, nil)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"no notes"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes","code":"notes_not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Tags not found",
 		},
@@ -298,13 +355,136 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetAllNotesByUser(userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(dto.ListQuery{})).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?created_after=yesterday",
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'created_after': time must be in RFC 3339 format","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-4-Handler:Invalid created_after",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			query:       "?updated_since=2023-01-02T03:04:05Z",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(dto.ListQuery{UpdatedSince: &testTime})).
+					Return(&dto.NotesPage{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes","code":"notes_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Handler:Updated since filter",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			query:       "?sort=title&limit=1&title_prefix=test&cursor=" + cursor,
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				outputNotes := &dto.NotesPage{
+					Notes: []dto.NotesResp{
+						{
+							ID:        "2",
+							Title:     "test_title2",
+							Info:      "test_info2",
+							CreatedAt: testTime,
+							UpdatedAt: testTime,
+						},
+					},
+					NextCursor: "next",
+					Total:      3,
+				}
+				listQuery := dto.ListQuery{
+					TitlePrefix: "test",
+					Sort:        dictionary.SortTitle,
+					Limit:       1,
+					Cursor:      &dto.Cursor{Sort: dictionary.SortTitle, ID: 1, Title: &cursorTitle},
+				}
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(listQuery)).Return(outputNotes, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"notes":[{"id":"2","title":"test_title2","info":"test_info2","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}],"next_cursor":"next","total":3}
+`,
+			testName: "test-6-Handler:Page after cursor",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?limit=1000",
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'limit': limit must be an integer from 1 to 100","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-7-Handler:Invalid limit",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?sort=-updated_at&cursor=" + cursor,
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'cursor': cursor is malformed or was issued for another sort","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-8-Handler:Cursor of another sort",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?sort=info",
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'sort': sort must be one of id, title, created_at, updated_at","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-9-Handler:Invalid sort",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			query:       "?tags=" + url.QueryEscape("work AND NOT archived"),
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				listQuery := dto.ListQuery{
+					Tags: tagquery.And{Operands: []tagquery.Expr{
+						tagquery.Tag{Name: "work"},
+						tagquery.Not{Operand: tagquery.Tag{Name: "archived"}},
+					}},
+				}
+				s.EXPECT().GetNotesPage(gomock.Any(), userID, pageQuery(listQuery)).Return(&dto.NotesPage{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes","code":"notes_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-10-Handler:Tags expression",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?tags=" + url.QueryEscape("work AND (urgent OR"),
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'tags': unexpected end of expression, expected tag, NOT or \"(\" at position 20","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-11-Handler:Malformed tags expression",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -326,7 +506,8 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 			router.GET(dictionary.NotesURL, handler.logMiddleware(handler.GetAllNotesByUser))
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodGet, dictionary.NotesURL, nil)
+			req := httptest.NewRequest(http.MethodGet, dictionary.NotesURL+testCase.query, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -337,100 +518,88 @@ func TestHandler_GetAllNotesByUser(t *testing.T) {
 	}
 }
 
-func TestHandler_UpdateNote(t *testing.T) {
+func TestHandler_SearchNotes(t *testing.T) {
 	// Init mock func obj
-	type mockBehavior func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string)
-	noteTitle := "test_title"
-	noteInfo := "test_info"
+	type mockBehavior func(s *mock_services.MockNoteService, userID string)
 
 	testTable := []struct {
-		inputJson          string
 		headerName         string
 		headerValue        string
-		noteID             string
-		inputNote          *dto.NoteUpdate
+		query              string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
 		testName           string
 	}{
 		{
-			inputJson: `{
-				"title": "test_title",
-				"info": "test_info"
-			}`,
 			headerName: "user_id",
 			// service request
 			headerValue: "1",
-			noteID:      "1",
-			inputNote: &dto.NoteUpdate{
-				Title: &noteTitle,
-				Info:  &noteInfo,
-			},
-			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+			query:       "?q=milk&tags=home,%20shop,",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, nil)
-				s.EXPECT().UpdateNote(note, noteID).Return(nil)
+				outputNotes := []dto.NoteSearchResp{
+					{
+						ID:        "1",
+						Title:     "shopping",
+						Snippet:   "buy <b>milk</b>",
+						Rank:      0.5,
+						CreatedAt: testTime,
+						UpdatedAt: testTime,
+					},
+				}
+				searchQuery := &dto.NoteSearchQuery{
+					Query: "milk",
+					Tags:  []string{"home", "shop"},
+					Limit: dictionary.DefaultPageLimit,
+				}
+				s.EXPECT().SearchNotes(gomock.Any(), userID, searchQuery).Return(outputNotes, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"Updated note with id":"1"}
+			expectedResponse: `[{"id":"1","title":"shopping","snippet":"buy \u003cb\u003emilk\u003c/b\u003e","rank":0.5,"created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}]
 `,
 			testName: "test-1-Handler:OK",
 		},
 		{
-			inputJson: `{
-				"title": "test_title",
-				"info": "test_info
-			}`,
 			headerName: "user_id",
 			// service request
-			headerValue:        "1",
-			noteID:             "1",
-			inputNote:          &dto.NoteUpdate{},
-			mockBehavior:       func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			headerValue: "1",
+			query:       "?q=milk",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				searchQuery := &dto.NoteSearchQuery{Query: "milk", Limit: dictionary.DefaultPageLimit}
+				s.EXPECT().SearchNotes(gomock.Any(), userID, searchQuery).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes match the search","code":"search_no_results","request_id":"test-request-id"}
 `,
-			testName: "test-2-Handler:Bad JSON",
+			testName: "test-2-Service:Notes not found",
 		},
 		{
-			inputJson:  `{}`,
 			headerName: "user_id",
 			// service request
 			headerValue: "1",
-			noteID:      "1",
-			inputNote:   &dto.NoteUpdate{},
-			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+			query:       "?q=milk",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				searchQuery := &dto.NoteSearchQuery{Query: "milk", Limit: dictionary.DefaultPageLimit}
+				s.EXPECT().SearchNotes(gomock.Any(), userID, searchQuery).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No note with id '1'"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
-			testName: "test-3-Service:Note not found",
+			testName: "test-3-Service:Db resp Err",
 		},
 		{
-			inputJson: `{
-				"title": "test_title",
-				"info": "test_info"
-			}`,
 			headerName: "user_id",
 			// service request
-			headerValue: "1",
-			noteID:      "1",
-			inputNote: &dto.NoteUpdate{
-				Title: &noteTitle,
-				Info:  &noteInfo,
-			},
-			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
-				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, nil)
-				s.EXPECT().UpdateNote(note, noteID).Return(e.New("some db Err"))
-			},
+			headerValue:        "1",
+			query:              "?q=%20",
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'q': search query must not be empty","code":"invalid_query_param","request_id":"test-request-id"}
 `,
-			testName: "test-4-Service:Db resp Err",
+			testName: "test-4-Handler:Empty search query",
 		},
 	}
 
@@ -441,7 +610,7 @@ func TestHandler_UpdateNote(t *testing.T) {
 			defer c.Finish()
 			// Init mock service
 			noteSrv := mock_services.NewMockNoteService(c)
-			testCase.mockBehavior(noteSrv, testCase.inputNote, testCase.noteID, testCase.headerValue)
+			testCase.mockBehavior(noteSrv, testCase.headerValue)
 			// Init testing logger with "fatal" level (5)
 			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
 			loggingMiddleware := l.NewLoggerMiddleware(logger)
@@ -450,10 +619,11 @@ func TestHandler_UpdateNote(t *testing.T) {
 			handler := NewHandler(service, loggingMiddleware)
 			// Test server
 			router := httprouter.New()
-			router.PUT(dictionary.NoteURL, handler.logMiddleware(handler.UpdateNote))
+			router.GET(dictionary.NotesSearchURL, handler.logMiddleware(handler.SearchNotes))
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/notes/%s", testCase.noteID), bytes.NewBufferString(testCase.inputJson))
+			req := httptest.NewRequest(http.MethodGet, dictionary.NotesSearchURL+testCase.query, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -464,14 +634,26 @@ func TestHandler_UpdateNote(t *testing.T) {
 	}
 }
 
-func TestHandler_DeleteNote(t *testing.T) {
+func TestHandler_GetAllNotesWithTags(t *testing.T) {
 	// Init mock func obj
-	type mockBehavior func(s *mock_services.MockNoteService, noteID, userID string)
+	type mockBehavior func(s *mock_services.MockNoteService, userID string)
+
+	streamNotes := func(notes ...dto.NoteWithTagsResp) interface{} {
+		return func(_ context.Context, _ string, _ *dto.ListQuery, _ bool, fn func(note *dto.NoteWithTagsResp) error) error {
+			for i := range notes {
+				if err := fn(&notes[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
 
 	testTable := []struct {
 		headerName         string
 		headerValue        string
-		inputNote          string
+		query              string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -481,14 +663,19 @@ func TestHandler_DeleteNote(t *testing.T) {
 			headerName: "user_id",
 			// service request
 			headerValue: "1",
-			inputNote:   "1",
-			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, nil)
-				s.EXPECT().DeleteNote(noteID, userID).Return(1, nil)
+				s.EXPECT().GetAllNotesWithTags(gomock.Any(), userID, &dto.ListQuery{}, false, gomock.Any()).DoAndReturn(streamNotes(
+					dto.NoteWithTagsResp{ID: "1", Title: "test_title1", Info: "test_info1", TagsResp: []dto.TagsResp{
+						{ID: "1", TagName: "test_name1", CreatedAt: testTime, UpdatedAt: testTime},
+					}},
+					dto.NoteWithTagsResp{ID: "2", Title: "test_title2", Info: "test_info2", TagsResp: []dto.TagsResp{
+						{ID: "1", TagName: "test_name1", CreatedAt: testTime, UpdatedAt: testTime},
+					}},
+				))
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"Deleted note with id":1}
+			expectedResponse: `[{"id":"1","title":"test_title1","info":"test_info1","tags":[{"id":"1","tagname":"test_name1","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}]},{"id":"2","title":"test_title2","info":"test_info2","tags":[{"id":"1","tagname":"test_name1","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}]}]
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -496,30 +683,55 @@ func TestHandler_DeleteNote(t *testing.T) {
 			headerName: "user_id",
 			// service request
 			headerValue: "1",
-			inputNote:   "1",
-			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+			query:       "?include_untagged=true",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetAllNotesWithTags(gomock.Any(), userID, &dto.ListQuery{}, true, gomock.Any()).DoAndReturn(streamNotes(
+					dto.NoteWithTagsResp{ID: "1", Title: "test_title1", Info: "test_info1", TagsResp: []dto.TagsResp{}},
+				))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No note with id '1'"}
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","title":"test_title1","info":"test_info1","tags":[]}]
 `,
-			testName: "test-2-Service:Note not found",
+			testName: "test-2-Handler:Include untagged",
 		},
 		{
 			headerName: "user_id",
 			// service request
 			headerValue: "1",
-			inputNote:   "1",
-			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				s.EXPECT().GetAllNotesWithTags(gomock.Any(), userID, &dto.ListQuery{}, false, gomock.Any()).DoAndReturn(streamNotes())
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes with tags","code":"tagged_notes_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Notes not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
 				// service response
-				s.EXPECT().GetNoteByID(noteID, userID).Return(nil, nil)
-				s.EXPECT().DeleteNote(noteID, userID).Return(0, e.New("some db Err"))
+				s.EXPECT().GetAllNotesWithTags(gomock.Any(), userID, &dto.ListQuery{}, false, gomock.Any()).
+					Return(e.New("some db Err"))
 			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Db resp Err",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			query:              "?include_untagged=maybe",
+			mockBehavior:       func(s *mock_services.MockNoteService, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'include_untagged': must be true or false","code":"invalid_query_param","request_id":"test-request-id"}
 `,
-			testName: "test-3-Service:Db resp Err",
+			testName: "test-5-Handler:Invalid include_untagged",
 		},
 	}
 
@@ -530,7 +742,7 @@ func TestHandler_DeleteNote(t *testing.T) {
 			defer c.Finish()
 			// Init mock service
 			noteSrv := mock_services.NewMockNoteService(c)
-			testCase.mockBehavior(noteSrv, testCase.inputNote, testCase.headerValue)
+			testCase.mockBehavior(noteSrv, testCase.headerValue)
 			// Init testing logger with "fatal" level (5)
 			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
 			loggingMiddleware := l.NewLoggerMiddleware(logger)
@@ -539,10 +751,11 @@ func TestHandler_DeleteNote(t *testing.T) {
 			handler := NewHandler(service, loggingMiddleware)
 			// Test server
 			router := httprouter.New()
-			router.DELETE(dictionary.NoteURL, handler.logMiddleware(handler.DeleteNote))
+			router.GET(dictionary.AllTagsByNotes, handler.logMiddleware(handler.GetAllNotesWithTags))
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notes/%s", testCase.inputNote), nil)
+			req := httptest.NewRequest(http.MethodGet, dictionary.AllTagsByNotes+testCase.query, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -552,3 +765,1072 @@ func TestHandler_DeleteNote(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_UpdateNote(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string)
+	noteTitle := "test_title"
+	noteInfo := "test_info"
+
+	testTable := []struct {
+		inputJson          string
+		headerName         string
+		headerValue        string
+		noteID             string
+		inputNote          *dto.NoteUpdate
+		ifMatch            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedETag       string
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson: `{
+				"title": "test_title",
+				"info": "test_info"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			noteID:      "1",
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+				Info:  &noteInfo,
+			},
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 1}, nil)
+				s.EXPECT().UpdateNote(gomock.Any(), note, noteID, userID, 0).Return(2, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"2"`,
+			expectedResponse: `{"Updated note with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJson: `{
+				"title": "test_title",
+				"info": "test_info
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			noteID:             "1",
+			inputNote:          &dto.NoteUpdate{},
+			mockBehavior:       func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
+`,
+			testName: "test-2-Handler:Bad JSON",
+		},
+		{
+			inputJson:  `{}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			noteID:      "1",
+			inputNote:   &dto.NoteUpdate{},
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Note not found",
+		},
+		{
+			inputJson: `{
+				"title": "test_title",
+				"info": "test_info"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			noteID:      "1",
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+				Info:  &noteInfo,
+			},
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 1}, nil)
+				s.EXPECT().UpdateNote(gomock.Any(), note, noteID, userID, 0).Return(0, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Db resp Err",
+		},
+		{
+			inputJson: `{
+				"title": "test_title",
+				"info": "test_info"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			noteID:      "1",
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+				Info:  &noteInfo,
+			},
+			ifMatch: `"1", "2"`,
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 2}, nil)
+				s.EXPECT().UpdateNote(gomock.Any(), note, noteID, userID, 2).Return(3, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"3"`,
+			expectedResponse: `{"Updated note with id":"1"}
+`,
+			testName: "test-5-Handler:If-Match OK",
+		},
+		{
+			inputJson: `{
+				"title": "test_title",
+				"info": "test_info"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			noteID:      "1",
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+				Info:  &noteInfo,
+			},
+			ifMatch: `"1"`,
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 2}, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedResponse: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"version does not match If-Match, it was changed","code":"version_mismatch","request_id":"test-request-id"}
+`,
+			testName: "test-6-Handler:Stale If-Match",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, testCase.noteID, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PUT(dictionary.NoteURL, handler.logMiddleware(handler.UpdateNote))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/notes/%s", testCase.noteID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-Match", testCase.ifMatch)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestHandler_PatchNote(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string)
+	noteTitle := "new_title"
+	emptyInfo := ""
+
+	testTable := []struct {
+		inputJson          string
+		contentType        string
+		noteID             string
+		inputNote          *dto.NoteUpdate
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedETag       string
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson:   `{"title": "new_title", "info": null}`,
+			contentType: "application/merge-patch+json",
+			noteID:      "1",
+			// service request
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+				Info:  &emptyInfo,
+			},
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+				s.EXPECT().UpdateNote(gomock.Any(), note, noteID, userID, 0).Return(2, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"2"`,
+			expectedResponse: `{"Updated note with id":"1"}
+`,
+			testName: "test-1-Handler:Merge patch OK",
+		},
+		{
+			inputJson:   `[{"op": "test", "path": "/info", "value": "test_info"}, {"op": "replace", "path": "/title", "value": "new_title"}]`,
+			contentType: "application/json-patch+json; charset=utf-8",
+			noteID:      "1",
+			// service request
+			inputNote: &dto.NoteUpdate{
+				Title: &noteTitle,
+			},
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+				s.EXPECT().UpdateNote(gomock.Any(), note, noteID, userID, 0).Return(2, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"2"`,
+			expectedResponse: `{"Updated note with id":"1"}
+`,
+			testName: "test-2-Handler:JSON patch OK",
+		},
+		{
+			inputJson:   `{"title": "new_title"}`,
+			contentType: "application/json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 1}, nil)
+			},
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedResponse: `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"patch must be application/merge-patch+json or application/json-patch+json","code":"unsupported_patch_type","request_id":"test-request-id"}
+`,
+			testName: "test-3-Handler:Unsupported media type",
+		},
+		{
+			inputJson:   `[{"op": "remove", "path": "/title"}, {"op": "add", "path": "/color", "value": "red"}]`,
+			contentType: "application/json-patch+json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"color","code":"unknown_field","message":"color is not a known field"}]}
+`,
+			testName: "test-4-Handler:Unknown field",
+		},
+		{
+			inputJson:   `{"title": null}`,
+			contentType: "application/merge-patch+json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"title","code":"required","message":"title is required"}]}
+`,
+			testName: "test-5-Handler:Patched note is invalid",
+		},
+		{
+			inputJson:   `[{"op": "replace", "path": "/tags/0", "value": "work"}]`,
+			contentType: "application/json-patch+json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid patch: operation 0: path /tags does not exist","code":"invalid_patch","request_id":"test-request-id"}
+`,
+			testName: "test-6-Handler:Invalid patch",
+		},
+		{
+			inputJson:   `[{"op": "test", "path": "/info", "value": "old_info"}]`,
+			contentType: "application/json-patch+json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				current := &dto.NoteResp{Title: "test_title", Info: "test_info", Version: 1}
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"patch test operation failed: operation 0: value of /info is not equal to the test value","code":"patch_test_failed","request_id":"test-request-id"}
+`,
+			testName: "test-7-Handler:Test operation failed",
+		},
+		{
+			inputJson:   `{}`,
+			contentType: "application/merge-patch+json",
+			noteID:      "1",
+			mockBehavior: func(s *mock_services.MockNoteService, note *dto.NoteUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-8-Service:Note not found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, testCase.noteID, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PATCH(dictionary.NoteURL, handler.logMiddleware(handler.PatchNote))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/notes/%s", testCase.noteID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			req.Header.Set("Content-Type", testCase.contentType)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestHandler_DeleteNote(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, userID string)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputNote          string
+		ifMatch            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 1}, nil)
+				s.EXPECT().DeleteNote(gomock.Any(), noteID, userID, 0).Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Deleted note with id":1}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Note not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 1}, nil)
+				s.EXPECT().DeleteNote(gomock.Any(), noteID, userID, 0).Return(0, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Db resp Err",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			ifMatch:     `"1"`,
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{Version: 2}, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedResponse: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"version does not match If-Match, it was changed","code":"version_mismatch","request_id":"test-request-id"}
+`,
+			testName: "test-4-Handler:Stale If-Match",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.DELETE(dictionary.NoteURL, handler.logMiddleware(handler.DeleteNote))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notes/%s", testCase.inputNote), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-Match", testCase.ifMatch)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetNoteRevisions(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, userID string)
+
+	testTable := []struct {
+		inputNote          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().GetNoteRevisions(gomock.Any(), noteID, userID).Return([]dto.NoteRevisionsResp{
+					{Rev: 2, Title: "test_title", AuthorID: "1", CreatedAt: testTime},
+					{Rev: 1, Title: "old_title", AuthorID: "1", CreatedAt: testTime},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"rev":2,"title":"test_title","author_id":"1","created_at":"2023-01-02T03:04:05Z"},` +
+				`{"rev":1,"title":"old_title","author_id":"1","created_at":"2023-01-02T03:04:05Z"}]
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().GetNoteRevisions(gomock.Any(), noteID, userID).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"note has no revisions","code":"revisions_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:No revisions",
+		},
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Note not found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.GET(dictionary.RevisionsURL, handler.logMiddleware(handler.GetNoteRevisions))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/notes/%s/revisions", testCase.inputNote), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_DiffNoteRevision(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, rev, to, userID string)
+
+	testTable := []struct {
+		inputRev           string
+		inputTo            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputRev: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, rev, to, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().DiffNoteRevision(gomock.Any(), noteID, rev, to, userID).Return(&dto.NoteRevisionDiffResp{
+					From: "1", To: "current", Diff: "--- rev 1\n+++ current\n@@ -1 +1 @@\n-old_title\n+test_title\n",
+				}, "", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"from":"1","to":"current","diff":"--- rev 1\n+++ current\n@@ -1 +1 @@\n-old_title\n+test_title\n"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputRev: "1",
+			inputTo:  "3",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, rev, to, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().DiffNoteRevision(gomock.Any(), noteID, rev, to, userID).Return(nil, "3", errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note revision with id '3'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Revision not found",
+		},
+		{
+			inputRev:           "1",
+			inputTo:            "last",
+			mockBehavior:       func(s *mock_services.MockNoteService, noteID, rev, to, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'to': must be a revision number","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-3-Handler:Invalid to",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, "1", testCase.inputRev, testCase.inputTo, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.GET(dictionary.RevisionDiffURL, handler.logMiddleware(handler.DiffNoteRevision))
+			// Test Request
+			target := fmt.Sprintf("/notes/1/revisions/%s/diff", testCase.inputRev)
+			if testCase.inputTo != "" {
+				target += "?" + url.Values{dictionary.RevisionToParam: {testCase.inputTo}}.Encode()
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_RestoreNoteRevision(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, rev, userID string)
+
+	testTable := []struct {
+		inputRev           string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputRev: "2",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, rev, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().RestoreNoteRevision(gomock.Any(), noteID, rev, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Restored note with id '1' to revision":"2"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputRev: "2",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, rev, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().RestoreNoteRevision(gomock.Any(), noteID, rev, userID).Return(errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note revision with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Revision not found",
+		},
+		{
+			inputRev: "2",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, rev, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().RestoreNoteRevision(gomock.Any(), noteID, rev, userID).Return(errors.ErrNoteTitleTaken)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"note with the same title already exists","code":"note_title_taken","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Title is taken",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, "1", testCase.inputRev, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.RevisionRestoreURL, handler.logMiddleware(handler.RestoreNoteRevision))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/1/revisions/%s/restore", testCase.inputRev), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetTrash(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, userID string)
+
+	testTable := []struct {
+		headerValue        string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				s.EXPECT().GetTrash(gomock.Any(), userID).Return([]dto.TrashNoteResp{{
+					ID:        "1",
+					Title:     "test_title",
+					Info:      "test_info",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+					DeletedAt: testTime.Add(time.Hour),
+				}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","title":"test_title","info":"test_info","created_at":"2023-01-02T03:04:05Z",` +
+				`"updated_at":"2023-01-02T03:04:05Z","deleted_at":"2023-01-02T04:04:05Z"}]
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				s.EXPECT().GetTrash(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notes in trash","code":"trash_empty","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Trash is empty",
+		},
+		{
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, userID string) {
+				// service response
+				s.EXPECT().GetTrash(gomock.Any(), userID).Return(nil, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.GET(dictionary.TrashURL, handler.logMiddleware(handler.GetTrash))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, dictionary.TrashURL, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_RestoreNote(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, userID string)
+
+	testTable := []struct {
+		inputNote          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().RestoreNote(gomock.Any(), noteID, userID).Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Restored note with id":1}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().RestoreNote(gomock.Any(), noteID, userID).Return(0, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No trashed note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Note is not in trash",
+		},
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().RestoreNote(gomock.Any(), noteID, userID).Return(0, errors.ErrNoteTitleTaken)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"note with the same title already exists","code":"note_title_taken","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Title is taken",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.NoteRestoreURL, handler.logMiddleware(handler.RestoreNote))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notes/%s/restore", testCase.inputNote), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_PurgeNote(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, noteID, userID string)
+
+	testTable := []struct {
+		inputNote          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().PurgeNote(gomock.Any(), noteID, userID).Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Purged note with id":1}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputNote: "1",
+			mockBehavior: func(s *mock_services.MockNoteService, noteID, userID string) {
+				// service response
+				s.EXPECT().PurgeNote(gomock.Any(), noteID, userID).Return(0, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No trashed note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Note is not in trash",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputNote, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.DELETE(dictionary.TrashNoteURL, handler.logMiddleware(handler.PurgeNote))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/trash/%s", testCase.inputNote), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_UpdateNoteTags(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string)
+
+	testTable := []struct {
+		inputJson          string
+		inputUpdate        *dto.NoteTagsUpdate
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson:   `{"set": [1, 2]}`,
+			inputUpdate: &dto.NoteTagsUpdate{Set: &[]int64{1, 2}},
+			mockBehavior: func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().UpdateNoteTags(gomock.Any(), noteID, userID, update).Return(&dto.NoteTagsResp{
+					Added: []int64{2}, Removed: []int64{3}, Unchanged: []int64{1},
+				}, "", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"added":[2],"removed":[3],"unchanged":[1]}
+`,
+			testName: "test-1-Handler:OK set",
+		},
+		{
+			inputJson:   `{"add": [2], "remove": [3]}`,
+			inputUpdate: &dto.NoteTagsUpdate{Add: []int64{2}, Remove: []int64{3}},
+			mockBehavior: func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().UpdateNoteTags(gomock.Any(), noteID, userID, update).Return(&dto.NoteTagsResp{
+					Added: []int64{2}, Removed: []int64{}, Unchanged: []int64{},
+				}, "", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"added":[2],"removed":[],"unchanged":[]}
+`,
+			testName: "test-2-Handler:OK add and remove",
+		},
+		{
+			inputJson:          `{"set": [1], "add": [2]}`,
+			mockBehavior:       func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"add","code":"excluded_with","message":"add can not be set together with set"}]}
+`,
+			testName: "test-3-Handler:Set with add",
+		},
+		{
+			inputJson:          `{}`,
+			mockBehavior:       func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"set","code":"required_without_all","message":"set is required when none of add, remove is set"}]}
+`,
+			testName: "test-4-Handler:Empty update",
+		},
+		{
+			inputJson:   `{"set": [1]}`,
+			inputUpdate: &dto.NoteTagsUpdate{Set: &[]int64{1}},
+			mockBehavior: func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Note not found",
+		},
+		{
+			inputJson:   `{"add": [4]}`,
+			inputUpdate: &dto.NoteTagsUpdate{Add: []int64{4}},
+			mockBehavior: func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().UpdateNoteTags(gomock.Any(), noteID, userID, update).Return(nil, "4", errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No tag with id '4'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-6-Service:Tag not found",
+		},
+		{
+			inputJson:   `{"add": [4], "remove": [4]}`,
+			inputUpdate: &dto.NoteTagsUpdate{Add: []int64{4}, Remove: []int64{4}},
+			mockBehavior: func(s *mock_services.MockNoteService, update *dto.NoteTagsUpdate, noteID, userID string) {
+				// service response
+				s.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, nil)
+				s.EXPECT().UpdateNoteTags(gomock.Any(), noteID, userID, update).Return(nil, "4", errors.ErrTagsAddRemove)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"tag can not be both added and removed: 4","code":"tag_added_and_removed","request_id":"test-request-id"}
+`,
+			testName: "test-7-Service:Tag added and removed",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			noteSrv := mock_services.NewMockNoteService(c)
+			testCase.mockBehavior(noteSrv, testCase.inputUpdate, "1", "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PUT(dictionary.AllTagsByNote, handler.logMiddleware(handler.UpdateNoteTags))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/notes/1/tags", bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
@@ -27,14 +27,36 @@ func NewHandler(service *services.Services, logFn dictionary.LogMiddleware) *Han
 func Register(router *httprouter.Router, service *services.Services, logFn dictionary.LogMiddleware) {
 	h := NewHandler(service, logFn)
 
-	router.POST(dictionary.NotesURL, h.logMiddleware(middleware.CheckToken(h.CreateNote, h.service.Auth)))
-	router.GET(dictionary.NoteURL, h.logMiddleware(middleware.CheckToken(h.GetNoteByID, h.service.Auth)))
-	router.GET(dictionary.NotesURL, h.logMiddleware(middleware.CheckToken(h.GetAllNotesByUser, h.service.Auth)))
-	router.PUT(dictionary.NoteURL, h.logMiddleware(middleware.CheckToken(h.UpdateNote, h.service.Auth)))
-	router.DELETE(dictionary.NoteURL, h.logMiddleware(middleware.CheckToken(h.DeleteNote, h.service.Auth)))
-
-	router.PUT(dictionary.TagsSet, h.logMiddleware(middleware.CheckToken(h.SetTags, h.service.Auth)))
-	router.PUT(dictionary.TagsRemove, h.logMiddleware(middleware.CheckToken(h.RemoveTags, h.service.Auth)))
-	router.GET(dictionary.AllTagsByNotes, h.logMiddleware(middleware.CheckToken(h.GetAllNotesWithTags, h.service.Auth)))
-	router.GET(dictionary.AllTagsByNote, h.logMiddleware(middleware.CheckToken(h.GetNoteWithAllTags, h.service.Auth)))
+	router.POST(dictionary.NotesURL, h.logMiddleware(h.auth(h.CreateNote, dictionary.ScopeNotesWrite)))
+	router.GET(dictionary.NoteURL, middleware.StaticRoutes(
+		h.logMiddleware(h.auth(h.GetNoteByID, dictionary.ScopeNotesRead)),
+		map[string]httprouter.Handle{
+			dictionary.NotesSearchURL: h.logMiddleware(h.auth(h.SearchNotes, dictionary.ScopeNotesRead)),
+		},
+	))
+	router.GET(dictionary.NotesURL, h.logMiddleware(h.auth(h.GetAllNotesByUser, dictionary.ScopeNotesRead)))
+	router.PUT(dictionary.NoteURL, h.logMiddleware(h.auth(h.UpdateNote, dictionary.ScopeNotesWrite)))
+	router.PATCH(dictionary.NoteURL, h.logMiddleware(h.auth(h.PatchNote, dictionary.ScopeNotesWrite)))
+	router.DELETE(dictionary.NoteURL, h.logMiddleware(h.auth(h.DeleteNote, dictionary.ScopeNotesWrite)))
+
+	router.GET(dictionary.RevisionsURL, h.logMiddleware(h.auth(h.GetNoteRevisions, dictionary.ScopeNotesRead)))
+	router.GET(dictionary.RevisionURL, h.logMiddleware(h.auth(h.GetNoteRevision, dictionary.ScopeNotesRead)))
+	router.GET(dictionary.RevisionDiffURL, h.logMiddleware(h.auth(h.DiffNoteRevision, dictionary.ScopeNotesRead)))
+	router.POST(dictionary.RevisionRestoreURL,
+		h.logMiddleware(h.auth(h.RestoreNoteRevision, dictionary.ScopeNotesWrite)))
+
+	router.GET(dictionary.TrashURL, h.logMiddleware(h.auth(h.GetTrash, dictionary.ScopeNotesRead)))
+	router.POST(dictionary.NoteRestoreURL, h.logMiddleware(h.auth(h.RestoreNote, dictionary.ScopeNotesWrite)))
+	router.DELETE(dictionary.TrashNoteURL, h.logMiddleware(h.auth(h.PurgeNote, dictionary.ScopeNotesWrite)))
+
+	router.PUT(dictionary.AllTagsByNote, h.logMiddleware(h.auth(h.UpdateNoteTags, dictionary.ScopeNotesWrite)))
+	router.PUT(dictionary.TagsSet, h.logMiddleware(h.auth(h.SetTags, dictionary.ScopeNotesWrite)))
+	router.PUT(dictionary.TagsRemove, h.logMiddleware(h.auth(h.RemoveTags, dictionary.ScopeNotesWrite)))
+	router.GET(dictionary.AllTagsByNotes, h.logMiddleware(h.auth(h.GetAllNotesWithTags, dictionary.ScopeNotesRead)))
+	router.GET(dictionary.AllTagsByNote, h.logMiddleware(h.auth(h.GetNoteWithAllTags, dictionary.ScopeNotesRead)))
+}
+
+// auth check token, personal access tokens must have the scope.
+func (h *Handler) auth(next httprouter.Handle, scope string) httprouter.Handle {
+	return middleware.CheckToken(next, h.service.Auth, scope)
 }
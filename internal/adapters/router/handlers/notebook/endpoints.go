@@ -0,0 +1,260 @@
+// Package notebook Package notebook
+package notebook
+
+import (
+	e "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/adapters/router/validate"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// CreateNotebook create notebook, notebook without parent is a root one.
+func (h *Handler) CreateNotebook(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	newNotebook := &model.Notebook{}
+	if err := validate.DecodeJSON(r.Body, &newNotebook); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(newNotebook); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	notebook, err := h.service.Notebook.CreateNotebook(ctx, newNotebook, userID)
+	if e.Is(err, errors.ErrNotFound) && newNotebook.ParentID != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook,
+			strconv.FormatInt(*newNotebook.ParentID, 10))
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, newNotebook.Name)
+		return
+	}
+
+	resp := make(map[string]string)
+	resp[fmt.Sprintf("Created notebook '%s' with id", notebook.Name)] = notebook.ID
+
+	functions.MakeJSONResponse(w, http.StatusCreated, resp)
+}
+
+// GetNotebookByID get notebook by ID.
+func (h *Handler) GetNotebookByID(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	notebookID := ps.ByName("id")
+	ctx := r.Context()
+
+	notebook, err := h.service.Notebook.GetNotebookByID(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebookID)
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, notebook)
+}
+
+// GetNotebookTree get all notebooks of user as a tree.
+func (h *Handler) GetNotebookTree(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	tree, err := h.service.Notebook.GetNotebookTree(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(tree) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotebooksListEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, tree)
+}
+
+// GetNotebookNotes get notes of notebook by ID, notes of child notebooks are not included.
+func (h *Handler) GetNotebookNotes(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	notebookID := ps.ByName("id")
+	ctx := r.Context()
+
+	_, err := h.service.Notebook.GetNotebookByID(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebookID)
+		return
+	}
+
+	notes, err := h.service.Notebook.GetNotebookNotes(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(notes) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrNotesListEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, notes)
+}
+
+// RenameNotebook rename notebook by ID.
+func (h *Handler) RenameNotebook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	notebookID := ps.ByName("id")
+	ctx := r.Context()
+
+	notebook := &dto.NotebookUpdate{}
+	if err := validate.DecodeJSON(r.Body, &notebook); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(notebook); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	_, err := h.service.Notebook.GetNotebookByID(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebookID)
+		return
+	}
+
+	err = h.service.Notebook.RenameNotebook(ctx, notebookID, userID, notebook.Name)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebook.Name)
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Updated notebook with id"] = notebookID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// MoveNotebook move notebook by ID with its child notebooks to parent notebook, null parent is root.
+func (h *Handler) MoveNotebook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	notebookID := ps.ByName("id")
+	ctx := r.Context()
+
+	move := &dto.NotebookMove{}
+	if err := validate.DecodeJSON(r.Body, &move); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(move); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	_, err := h.service.Notebook.GetNotebookByID(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebookID)
+		return
+	}
+
+	err = h.service.Notebook.MoveNotebook(ctx, notebookID, userID, move.ParentID)
+	if e.Is(err, errors.ErrNotFound) && move.ParentID != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook,
+			strconv.FormatInt(*move.ParentID, 10))
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Moved notebook with id"] = notebookID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// DeleteNotebook delete notebook by ID. Child notebooks and notes are moved to parent of the notebook,
+// with recursive=true child notebooks are deleted too and notes are moved to trash.
+func (h *Handler) DeleteNotebook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	notebookID := ps.ByName("id")
+	ctx := r.Context()
+
+	recursive, err := functions.ParseRecursive(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	_, err = h.service.Notebook.GetNotebookByID(ctx, notebookID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook, notebookID)
+		return
+	}
+
+	id, err := h.service.Notebook.DeleteNotebook(ctx, notebookID, userID, recursive)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	resp := make(map[string]int)
+	resp["Deleted notebook with id"] = id
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetNoteNotebook move note by ID to notebook, null notebook removes note from its notebook.
+func (h *Handler) SetNoteNotebook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	noteID := ps.ByName("id")
+	ctx := r.Context()
+
+	update := &dto.NoteNotebookUpdate{}
+	if err := validate.DecodeJSON(r.Body, &update); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(update); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	_, err := h.service.Note.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Note, noteID)
+		return
+	}
+
+	err = h.service.Notebook.SetNoteNotebook(ctx, noteID, userID, update.NotebookID)
+	if e.Is(err, errors.ErrNotFound) && update.NotebookID != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Notebook,
+			strconv.FormatInt(*update.NotebookID, 10))
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Updated notebook of note with id"] = noteID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,626 @@
+package notebook
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+
+	"web/internal/adapters/router/middleware"
+	"web/internal/config"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/internal/domain/services"
+	"web/internal/domain/services/mocks"
+	"web/internal/utils/dictionary"
+	l "web/pkg/logger"
+)
+
+// testRequestID request id of test requests, error responses have it.
+const testRequestID = "test-request-id"
+
+func TestHandler_CreateNotebook(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string)
+
+	parentID := int64(2)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputJson          string
+		inputNotebook      *model.Notebook
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			inputJson: `{
+				"name": "work"
+			}`,
+			// service request
+			headerValue:   "1",
+			inputNotebook: &model.Notebook{Name: "work"},
+			mockBehavior: func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string) {
+				// service response
+				s.EXPECT().CreateNotebook(gomock.Any(), notebook, userID).Return(&model.Notebook{ID: "1", Name: "work"}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: `{"Created notebook 'work' with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJson:          `{}`,
+			inputNotebook:      &model.Notebook{},
+			mockBehavior:       func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"name","code":"required","message":"name is required"}]}
+`,
+			testName: "test-2-Handler:Validation Err",
+		},
+		{
+			headerName: "user_id",
+			inputJson: `{
+				"name": "work"
+			}`,
+			// service request
+			headerValue:   "1",
+			inputNotebook: &model.Notebook{Name: "work"},
+			mockBehavior: func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string) {
+				// service response
+				s.EXPECT().CreateNotebook(gomock.Any(), notebook, userID).Return(nil, errors.ErrConflict)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"notebook 'work' is already exists","code":"conflict","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Notebook is already exists Err",
+		},
+		{
+			headerName: "user_id",
+			inputJson: `{
+				"name": "projects",
+				"parent_id": 2
+			}`,
+			// service request
+			headerValue:   "1",
+			inputNotebook: &model.Notebook{Name: "projects", ParentID: &parentID},
+			mockBehavior: func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string) {
+				// service response
+				s.EXPECT().CreateNotebook(gomock.Any(), notebook, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No notebook with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Parent not found",
+		},
+		{
+			headerName: "user_id",
+			inputJson: `{
+				"name": "work"
+			}`,
+			// service request
+			headerValue:   "1",
+			inputNotebook: &model.Notebook{Name: "work"},
+			mockBehavior: func(s *mock_services.MockNotebookService, notebook *model.Notebook, userID string) {
+				// service response
+				s.EXPECT().CreateNotebook(gomock.Any(), notebook, userID).Return(nil, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			notebookSrv := mock_services.NewMockNotebookService(c)
+			testCase.mockBehavior(notebookSrv, testCase.inputNotebook, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Notebook: notebookSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.NotebooksURL, handler.logMiddleware(handler.CreateNotebook))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, dictionary.NotebooksURL, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetNotebookTree(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNotebookService, userID string)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNotebookService, userID string) {
+				// service response
+				tree := []dto.NotebookTreeResp{
+					{ID: "1", Name: "work", NoteCount: 1, Children: []dto.NotebookTreeResp{
+						{ID: "2", Name: "projects", NoteCount: 2, Children: []dto.NotebookTreeResp{}},
+					}},
+				}
+				s.EXPECT().GetNotebookTree(gomock.Any(), userID).Return(tree, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","name":"work","note_count":1,"children":[{"id":"2","name":"projects","note_count":2,"children":[]}]}]
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockNotebookService, userID string) {
+				// service response
+				s.EXPECT().GetNotebookTree(gomock.Any(), userID).Return([]dto.NotebookTreeResp{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no notebooks","code":"notebooks_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Empty tree",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			notebookSrv := mock_services.NewMockNotebookService(c)
+			testCase.mockBehavior(notebookSrv, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Notebook: notebookSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server, tree is a static route of notebook by id route
+			router := httprouter.New()
+			router.GET(dictionary.NotebookURL, middleware.StaticRoutes(
+				handler.logMiddleware(handler.GetNotebookByID),
+				map[string]httprouter.Handle{dictionary.NotebookTreeURL: handler.logMiddleware(handler.GetNotebookTree)},
+			))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, dictionary.NotebookTreeURL, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_MoveNotebook(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64)
+
+	parentID := int64(2)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputNotebook      string
+		inputJson          string
+		inputParentID      *int64
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputJson:     `{"parent_id": 2}`,
+			inputParentID: &parentID,
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().MoveNotebook(gomock.Any(), notebookID, userID, parentID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Moved notebook with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputJson:     `{"parent_id": null}`,
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().MoveNotebook(gomock.Any(), notebookID, userID, parentID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Moved notebook with id":"1"}
+`,
+			testName: "test-2-Handler:Move to root OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputJson:     `{"parent_id": 2}`,
+			inputParentID: &parentID,
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().MoveNotebook(gomock.Any(), notebookID, userID, parentID).Return(errors.ErrNotebookCycle)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"notebook can not be moved into itself or its child notebooks","code":"notebook_cycle","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Cycle Err",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputJson:     `{"parent_id": 2}`,
+			inputParentID: &parentID,
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().MoveNotebook(gomock.Any(), notebookID, userID, parentID).Return(errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No notebook with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Parent not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputJson:     `{"parent_id": 2}`,
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string, parentID *int64) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No notebook with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Notebook not found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			notebookSrv := mock_services.NewMockNotebookService(c)
+			testCase.mockBehavior(notebookSrv, testCase.inputNotebook, testCase.headerValue, testCase.inputParentID)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Notebook: notebookSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.NotebookMoveURL, handler.logMiddleware(handler.MoveNotebook))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/notebooks/%s/move", testCase.inputNotebook),
+				bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_DeleteNotebook(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockNotebookService, notebookID, userID string)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputNotebook      string
+		inputQuery         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().DeleteNotebook(gomock.Any(), notebookID, userID, false).Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Deleted notebook with id":1}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			inputQuery:    "?recursive=true",
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().DeleteNotebook(gomock.Any(), notebookID, userID, true).Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Deleted notebook with id":1}
+`,
+			testName: "test-2-Handler:Recursive OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			inputNotebook:      "1",
+			inputQuery:         "?recursive=yes",
+			mockBehavior:       func(s *mock_services.MockNotebookService, notebookID, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'recursive': must be true or false","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-3-Handler:Bad recursive",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No notebook with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Notebook not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:   "1",
+			inputNotebook: "1",
+			mockBehavior: func(s *mock_services.MockNotebookService, notebookID, userID string) {
+				// service response
+				s.EXPECT().GetNotebookByID(gomock.Any(), notebookID, userID).Return(&dto.NotebookResp{}, nil)
+				s.EXPECT().DeleteNotebook(gomock.Any(), notebookID, userID, false).Return(0, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			notebookSrv := mock_services.NewMockNotebookService(c)
+			testCase.mockBehavior(notebookSrv, testCase.inputNotebook, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Notebook: notebookSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.DELETE(dictionary.NotebookURL, handler.logMiddleware(handler.DeleteNotebook))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete,
+				fmt.Sprintf("/notebooks/%s%s", testCase.inputNotebook, testCase.inputQuery), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_SetNoteNotebook(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(
+		note *mock_services.MockNoteService,
+		notebook *mock_services.MockNotebookService,
+		noteID, userID string,
+		notebookID *int64,
+	)
+
+	notebookID := int64(2)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputNote          string
+		inputJson          string
+		inputNotebookID    *int64
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:     "1",
+			inputNote:       "1",
+			inputJson:       `{"notebook_id": 2}`,
+			inputNotebookID: &notebookID,
+			mockBehavior: func(
+				note *mock_services.MockNoteService,
+				notebook *mock_services.MockNotebookService,
+				noteID, userID string,
+				notebookID *int64,
+			) {
+				// service response
+				note.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{}, nil)
+				notebook.EXPECT().SetNoteNotebook(gomock.Any(), noteID, userID, notebookID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Updated notebook of note with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:     "1",
+			inputNote:       "1",
+			inputJson:       `{"notebook_id": 2}`,
+			inputNotebookID: &notebookID,
+			mockBehavior: func(
+				note *mock_services.MockNoteService,
+				notebook *mock_services.MockNotebookService,
+				noteID, userID string,
+				notebookID *int64,
+			) {
+				// service response
+				note.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No note with id '1'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Note not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:     "1",
+			inputNote:       "1",
+			inputJson:       `{"notebook_id": 2}`,
+			inputNotebookID: &notebookID,
+			mockBehavior: func(
+				note *mock_services.MockNoteService,
+				notebook *mock_services.MockNotebookService,
+				noteID, userID string,
+				notebookID *int64,
+			) {
+				// service response
+				note.EXPECT().GetNoteByID(gomock.Any(), noteID, userID).Return(&dto.NoteResp{}, nil)
+				notebook.EXPECT().SetNoteNotebook(gomock.Any(), noteID, userID, notebookID).Return(errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No notebook with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Notebook not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputNote:   "1",
+			inputJson:   `{"notebook_id": -1}`,
+			mockBehavior: func(
+				note *mock_services.MockNoteService,
+				notebook *mock_services.MockNotebookService,
+				noteID, userID string,
+				notebookID *int64,
+			) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"notebook_id","code":"gt","message":"notebook_id must be greater than 0"}]}
+`,
+			testName: "test-4-Handler:Validation Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock services
+			noteSrv := mock_services.NewMockNoteService(c)
+			notebookSrv := mock_services.NewMockNotebookService(c)
+			testCase.mockBehavior(noteSrv, notebookSrv, testCase.inputNote, testCase.headerValue, testCase.inputNotebookID)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Note: noteSrv, Notebook: notebookSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PUT(dictionary.NoteNotebookURL, handler.logMiddleware(handler.SetNoteNotebook))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/notes/%s/notebook", testCase.inputNote),
+				bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
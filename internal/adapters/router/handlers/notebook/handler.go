@@ -0,0 +1,47 @@
+package notebook
+
+import (
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/adapters/router/middleware"
+	"web/internal/domain/services"
+	"web/internal/utils/dictionary"
+)
+
+// Handler struct.
+type Handler struct {
+	service       services.Services
+	logMiddleware dictionary.LogMiddleware
+}
+
+// NewHandler func builder.
+func NewHandler(service *services.Services, logFn dictionary.LogMiddleware) *Handler {
+	return &Handler{
+		service:       *service,
+		logMiddleware: logFn,
+	}
+}
+
+// Register register notebook handlers.
+func Register(router *httprouter.Router, service *services.Services, logFn dictionary.LogMiddleware) {
+	h := NewHandler(service, logFn)
+
+	router.POST(dictionary.NotebooksURL, h.logMiddleware(h.auth(h.CreateNotebook, dictionary.ScopeNotesWrite)))
+	router.GET(dictionary.NotebookURL, middleware.StaticRoutes(
+		h.logMiddleware(h.auth(h.GetNotebookByID, dictionary.ScopeNotesRead)),
+		map[string]httprouter.Handle{
+			dictionary.NotebookTreeURL: h.logMiddleware(h.auth(h.GetNotebookTree, dictionary.ScopeNotesRead)),
+		},
+	))
+	router.PUT(dictionary.NotebookURL, h.logMiddleware(h.auth(h.RenameNotebook, dictionary.ScopeNotesWrite)))
+	router.DELETE(dictionary.NotebookURL, h.logMiddleware(h.auth(h.DeleteNotebook, dictionary.ScopeNotesWrite)))
+	router.POST(dictionary.NotebookMoveURL, h.logMiddleware(h.auth(h.MoveNotebook, dictionary.ScopeNotesWrite)))
+	router.GET(dictionary.NotebookNotesURL, h.logMiddleware(h.auth(h.GetNotebookNotes, dictionary.ScopeNotesRead)))
+
+	router.PUT(dictionary.NoteNotebookURL, h.logMiddleware(h.auth(h.SetNoteNotebook, dictionary.ScopeNotesWrite)))
+}
+
+// auth check token, personal access tokens must have the scope.
+func (h *Handler) auth(next httprouter.Handle, scope string) httprouter.Handle {
+	return middleware.CheckToken(next, h.service.Auth, scope)
+}
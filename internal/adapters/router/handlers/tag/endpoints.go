@@ -2,7 +2,7 @@
 package tag
 
 import (
-	"encoding/json"
+	e "errors"
 	"fmt"
 	"net/http"
 
@@ -14,7 +14,6 @@ import (
 	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
 	"web/internal/utils/functions"
-	"web/pkg/logger"
 )
 
 // CreateTag create tag.
@@ -23,23 +22,20 @@ func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request, _ httprouter
 	ctx := r.Context()
 
 	newTag := &model.Tag{}
-	if err := json.NewDecoder(r.Body).Decode(&newTag); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &newTag); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 	// Валидация объекта структуры Tag //
 	err := validate.InputJSONValidate(newTag)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	tag, err := h.service.Tag.CreateTag(newTag, userID)
+	tag, err := h.service.Tag.CreateTag(ctx, newTag, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, newTag.TagName)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -55,35 +51,153 @@ func (h *Handler) GetTagByID(w http.ResponseWriter, r *http.Request, ps httprout
 	tagID := ps.ByName("id")
 	ctx := r.Context()
 
-	tag, err := h.service.Tag.GetTagByID(tagID, userID)
+	tag, err := h.service.Tag.GetTagByID(ctx, tagID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-		logger.LogFromContext(ctx).Error(err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", functions.ETag(tag.Version))
+
+	if functions.CheckIfNoneMatch(r.Header.Get("If-None-Match"), tag.Version) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	functions.MakeJSONResponse(w, http.StatusOK, tag)
 }
 
-// GetAllTagsByUser get tag by user.
-func (h *Handler) GetAllTagsByUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+// GetAllTagsByUser get page of tags by user, with tree=true all tags as a tree with note counts.
+func (h *Handler) GetAllTagsByUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	userID := r.Header.Get("user_id")
 	ctx := r.Context()
 
-	tags, err := h.service.Tag.GetAllTagsByUser(userID)
+	tree, err := functions.ParseTree(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if tree {
+		h.getTagTree(w, r, ps)
+		return
+	}
+
+	listQuery, err := functions.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	page, err := h.service.Tag.GetTagsPage(ctx, userID, listQuery)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	if len(tags) == 0 {
+	if page.Total == 0 {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrTagsListEmpty, "", "")
-		logger.LogFromContext(ctx).Error(errors.ErrTagsListEmpty.Error())
 		return
 	}
 
-	functions.MakeJSONResponse(w, http.StatusOK, tags)
+	functions.MakeJSONResponse(w, http.StatusOK, page)
+}
+
+// getTagTree get all tags by user as a tree.
+func (h *Handler) getTagTree(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	tree, err := h.service.Tag.GetTagTree(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(tree) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrTagsListEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, tree)
+}
+
+// GetTagStats get tags by user with note counts and last use time, with duplicates=true only groups of tags
+// with names equal ignoring case.
+func (h *Handler) GetTagStats(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	duplicates, err := functions.ParseDuplicates(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if duplicates {
+		groups, err := h.service.Tag.GetTagDuplicates(ctx, userID)
+		if err != nil {
+			functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+			return
+		}
+
+		if len(groups) == 0 {
+			functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrTagDuplicatesEmpty, "", "")
+			return
+		}
+
+		functions.MakeJSONResponse(w, http.StatusOK, groups)
+
+		return
+	}
+
+	stats, err := h.service.Tag.GetTagStats(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	if len(stats) == 0 {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrTagsListEmpty, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, stats)
+}
+
+// MergeTags merge source tags into target tag, notes of source tags get target tag and source tags are deleted.
+func (h *Handler) MergeTags(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	merge := &dto.TagMerge{}
+	if err := validate.DecodeJSON(r.Body, &merge); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(merge); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	resp, tagID, err := h.service.Tag.MergeTags(ctx, userID, merge)
+	if e.Is(err, errors.ErrTagMergeTarget) {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, fmt.Errorf("%w: %s", err, tagID), "", "")
+		return
+	}
+
+	if err != nil && tagID != "" {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
 // UpdateTag update tag by ID.
@@ -93,26 +207,90 @@ func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request, ps httproute
 	ctx := r.Context()
 
 	tag := &dto.TagUpdate{}
-	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &tag); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	_, err := h.service.Tag.GetTagByID(tagID, userID)
+	if err := validate.InputJSONValidate(tag); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	current, err := h.service.Tag.GetTagByID(ctx, tagID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	err = h.service.Tag.UpdateTag(tag, tagID)
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), current.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	newVersion, err := h.service.Tag.UpdateTag(ctx, tag, tagID, version)
+	if err != nil && tag.TagName != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, *tag.TagName)
+		return
+	}
+
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
+	w.Header().Set("ETag", functions.ETag(newVersion))
+
+	resp := make(map[string]string)
+	resp["Updated tag with id"] = tagID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// PatchTag patch tag by ID with JSON Merge Patch or JSON Patch, patched tag is validated before update.
+func (h *Handler) PatchTag(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	tagID := ps.ByName("id")
+	ctx := r.Context()
+
+	current, err := h.service.Tag.GetTagByID(ctx, tagID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
+		return
+	}
+
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), current.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	patched := &dto.TagPatch{TagName: current.TagName}
+	if err := validate.DecodePatch(r.Header.Get("Content-Type"), r.Body, patched); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(patched); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	// only changed fields are updated
+	tag := &dto.TagUpdate{}
+	if patched.TagName != current.TagName {
+		tag.TagName = &patched.TagName
+	}
+
+	newVersion, err := h.service.Tag.UpdateTag(ctx, tag, tagID, version)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, patched.TagName)
+		return
+	}
+
+	w.Header().Set("ETag", functions.ETag(newVersion))
+
 	resp := make(map[string]string)
 	resp["Updated tag with id"] = tagID
 
@@ -125,17 +303,21 @@ func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request, ps httproute
 	tagID := ps.ByName("id")
 	ctx := r.Context()
 
-	_, err := h.service.Tag.GetTagByID(tagID, userID)
+	tag, err := h.service.Tag.GetTagByID(ctx, tagID, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.Tag, tagID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	id, err := h.service.Tag.DeleteTag(tagID, userID)
+	version, ok := functions.CheckIfMatch(r.Header.Get("If-Match"), tag.Version)
+	if !ok {
+		functions.Abort(ctx, w, http.StatusPreconditionFailed, nil, errors.ErrVersionMismatch, "", "")
+		return
+	}
+
+	id, err := h.service.Tag.DeleteTag(ctx, tagID, userID, version)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/require"
 
+	"web/internal/adapters/router/middleware"
 	"web/internal/config"
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
@@ -22,6 +25,12 @@ import (
 	l "web/pkg/logger"
 )
 
+// testTime created_at and updated_at of service responses.
+var testTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// testRequestID request id of test requests, error responses have it.
+const testRequestID = "test-request-id"
+
 func TestHandler_CreateTag(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockTagService, tag *model.Tag, userID string)
@@ -52,7 +61,7 @@ func TestHandler_CreateTag(t *testing.T) {
 					ID:      "1",
 					TagName: "test_name",
 				}
-				s.EXPECT().CreateTag(tag, userID).Return(outputTag, nil)
+				s.EXPECT().CreateTag(gomock.Any(), tag, userID).Return(outputTag, nil)
 			},
 			expectedStatusCode: http.StatusCreated,
 			expectedResponse: `{"Created tag 'test_name' with id":"1"}
@@ -66,7 +75,7 @@ func TestHandler_CreateTag(t *testing.T) {
 			inputTag:           &model.Tag{},
 			mockBehavior:       func(s *mock_services.MockTagService, tag *model.Tag, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -75,7 +84,7 @@ func TestHandler_CreateTag(t *testing.T) {
 			inputTag:           &model.Tag{},
 			mockBehavior:       func(s *mock_services.MockTagService, tag *model.Tag, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `Key: 'Tag.TagName' Error:Field validation for 'TagName' failed on the 'required' tag
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"tagname","code":"required","message":"tagname is required"}]}
 `,
 			testName: "test-3-Handler:Validation Err",
 		},
@@ -91,10 +100,10 @@ func TestHandler_CreateTag(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockTagService, tag *model.Tag, userID string) {
 				// service response
-				s.EXPECT().CreateTag(tag, userID).Return(nil, e.New(errors.ErrDBDuplicate))
+				s.EXPECT().CreateTag(gomock.Any(), tag, userID).Return(nil, errors.ErrConflict)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"tag 'test_name' is already exists"}
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"tag 'test_name' is already exists","code":"conflict","request_id":"test-request-id"}
 `,
 			testName: "test-4-Service:Tag is already exists Err",
 		},
@@ -110,13 +119,31 @@ func TestHandler_CreateTag(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockTagService, tag *model.Tag, userID string) {
 				// service response
-				s.EXPECT().CreateTag(tag, userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().CreateTag(gomock.Any(), tag, userID).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-5-Service:Db resp Err",
 		},
+		{
+			inputJson:          fmt.Sprintf(`{"tagname": "%s"}`, strings.Repeat("a", 256)),
+			inputTag:           &model.Tag{},
+			mockBehavior:       func(s *mock_services.MockTagService, tag *model.Tag, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"tagname","code":"max","message":"tagname must be at most 255 characters"}]}
+`,
+			testName: "test-6-Handler:Too long name",
+		},
+		{
+			inputJson:          `{"tagname": "work//alpha"}`,
+			inputTag:           &model.Tag{},
+			mockBehavior:       func(s *mock_services.MockTagService, tag *model.Tag, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"tagname","code":"tagpath","message":"tagname must be tag names separated by '/' without empty names and spaces around them"}]}
+`,
+			testName: "test-7-Handler:Empty name in tag path",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -139,6 +166,7 @@ func TestHandler_CreateTag(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, dictionary.TagsURL, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -157,8 +185,10 @@ func TestHandler_GetTagByID(t *testing.T) {
 		headerName         string
 		headerValue        string
 		inputTag           string
+		ifNoneMatch        string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
+		expectedETag       string
 		expectedResponse   string
 		testName           string
 	}{
@@ -170,12 +200,16 @@ func TestHandler_GetTagByID(t *testing.T) {
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
 				outputTag := &dto.TagResp{
-					TagName: "test_name",
+					TagName:   "test_name",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+					Version:   3,
 				}
-				s.EXPECT().GetTagByID(tagID, userID).Return(outputTag, nil)
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(outputTag, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"tagname":"test_name"}
+			expectedETag:       `"3"`,
+			expectedResponse: `{"tagname":"test_name","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -186,10 +220,10 @@ func TestHandler_GetTagByID(t *testing.T) {
 			inputTag:    "1",
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No tag with id '1'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No tag with id '1'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Tag not found",
 		},
@@ -200,13 +234,28 @@ func TestHandler_GetTagByID(t *testing.T) {
 			inputTag:    "1",
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputTag:    "1",
+			ifNoneMatch: `W/"3"`,
+			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 3}, nil)
+			},
+			expectedStatusCode: http.StatusNotModified,
+			expectedETag:       `"3"`,
+			expectedResponse:   "",
+			testName:           "test-4-Handler:Not modified",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -229,11 +278,14 @@ func TestHandler_GetTagByID(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tags/%s", testCase.inputTag), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-None-Match", testCase.ifNoneMatch)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
 			require.Equal(t, testCase.expectedResponse, w.Body.String())
 		})
 	}
@@ -243,9 +295,12 @@ func TestHandler_GetAllTagsByUser(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockTagService, userID string)
 
+	pageQuery := &dto.ListQuery{Sort: dictionary.SortID, Limit: dictionary.DefaultPageLimit}
+
 	testTable := []struct {
 		headerName         string
 		headerValue        string
+		inputQuery         string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -257,20 +312,28 @@ func TestHandler_GetAllTagsByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockTagService, userID string) {
 				// service response
-				outputTag := []dto.TagsResp{
-					{
-						ID:      "1",
-						TagName: "test_name1",
-					},
-					{
-						ID:      "2",
-						TagName: "test_name2",
+				outputTags := &dto.TagsPage{
+					Tags: []dto.TagsResp{
+						{
+							ID:        "1",
+							TagName:   "test_name1",
+							CreatedAt: testTime,
+							UpdatedAt: testTime,
+						},
+						{
+							ID:        "2",
+							TagName:   "test_name2",
+							CreatedAt: testTime,
+							UpdatedAt: testTime,
+						},
 					},
+					NextCursor: "next",
+					Total:      3,
 				}
-				s.EXPECT().GetAllTagsByUser(userID).Return(outputTag, nil)
+				s.EXPECT().GetTagsPage(gomock.Any(), userID, pageQuery).Return(outputTags, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `[{"id":"1","tagname":"test_name1"},{"id":"2","tagname":"test_name2"}]
+			expectedResponse: `{"tags":[{"id":"1","tagname":"test_name1","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"},{"id":"2","tagname":"test_name2","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}],"next_cursor":"next","total":3}
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -280,10 +343,10 @@ func TestHandler_GetAllTagsByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockTagService, userID string) {
 				// service response
-				s.EXPECT().GetAllTagsByUser(userID).Return(nil, nil)
+				s.EXPECT().GetTagsPage(gomock.Any(), userID, pageQuery).Return(&dto.TagsPage{}, nil)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"no tags"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no tags","code":"tags_not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Tags not found",
 		},
@@ -293,13 +356,48 @@ func TestHandler_GetAllTagsByUser(t *testing.T) {
 			headerValue: "1",
 			mockBehavior: func(s *mock_services.MockTagService, userID string) {
 				// service response
-				s.EXPECT().GetAllTagsByUser(userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().GetTagsPage(gomock.Any(), userID, pageQuery).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputQuery:  "?tree=true",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				parentID := "1"
+				tree := []dto.TagTreeResp{
+					{ID: "1", TagName: "work", NoteCount: 1, TotalCount: 2, Children: []dto.TagTreeResp{
+						{ID: "2", TagName: "work/projects", ParentID: &parentID, NoteCount: 1, TotalCount: 1,
+							Children: []dto.TagTreeResp{}},
+					}},
+				}
+				s.EXPECT().GetTagTree(gomock.Any(), userID).Return(tree, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","tagname":"work","note_count":1,"total_count":2,"children":[{"id":"2","tagname":"work/projects","note_count":1,"total_count":1,"children":[]}]}]
+`,
+			testName: "test-4-Handler:Tree OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputQuery:  "?tree=true",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				s.EXPECT().GetTagTree(gomock.Any(), userID).Return([]dto.TagTreeResp{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no tags","code":"tags_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Tree tags not found",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -321,7 +419,8 @@ func TestHandler_GetAllTagsByUser(t *testing.T) {
 			router.GET(dictionary.TagsURL, handler.logMiddleware(handler.GetAllTagsByUser))
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodGet, dictionary.TagsURL, nil)
+			req := httptest.NewRequest(http.MethodGet, dictionary.TagsURL+testCase.inputQuery, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -336,6 +435,7 @@ func TestHandler_UpdateTag(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string)
 	tagName := "test_name"
+	cycleName := "work/projects/work"
 
 	testTable := []struct {
 		inputJson          string
@@ -343,8 +443,10 @@ func TestHandler_UpdateTag(t *testing.T) {
 		headerValue        string
 		tagID              string
 		inputTag           *dto.TagUpdate
+		ifMatch            string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
+		expectedETag       string
 		expectedResponse   string
 		testName           string
 	}{
@@ -361,10 +463,11 @@ func TestHandler_UpdateTag(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, nil)
-				s.EXPECT().UpdateTag(tag, tagID).Return(nil)
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 1}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 0).Return(2, nil)
 			},
 			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"2"`,
 			expectedResponse: `{"Updated tag with id":"1"}
 `,
 			testName: "test-1-Handler:OK",
@@ -380,7 +483,7 @@ func TestHandler_UpdateTag(t *testing.T) {
 			inputTag:           &dto.TagUpdate{},
 			mockBehavior:       func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -393,10 +496,10 @@ func TestHandler_UpdateTag(t *testing.T) {
 			inputTag:    &dto.TagUpdate{},
 			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No tag with id '1'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No tag with id '1'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Tag not found",
 		},
@@ -413,14 +516,79 @@ func TestHandler_UpdateTag(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, nil)
-				s.EXPECT().UpdateTag(tag, tagID).Return(e.New("some db Err"))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 1}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 0).Return(0, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-4-Service:Db resp Err",
 		},
+		{
+			inputJson: `{
+				"tagname": "test_name"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			tagID:       "1",
+			inputTag: &dto.TagUpdate{
+				TagName: &tagName,
+			},
+			ifMatch: `"2"`,
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 2}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 2).Return(3, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"3"`,
+			expectedResponse: `{"Updated tag with id":"1"}
+`,
+			testName: "test-5-Handler:If-Match OK",
+		},
+		{
+			inputJson: `{
+				"tagname": "test_name"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			tagID:       "1",
+			inputTag: &dto.TagUpdate{
+				TagName: &tagName,
+			},
+			ifMatch: `"1"`,
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 2}, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedResponse: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"version does not match If-Match, it was changed","code":"version_mismatch","request_id":"test-request-id"}
+`,
+			testName: "test-6-Handler:Stale If-Match",
+		},
+		{
+			inputJson: `{
+				"tagname": "work/projects/work"
+			}`,
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			tagID:       "1",
+			inputTag: &dto.TagUpdate{
+				TagName: &cycleName,
+			},
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 1}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 0).Return(0, errors.ErrTagCycle)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"tag can not be moved into itself or its child tags","code":"tag_cycle","request_id":"test-request-id"}
+`,
+			testName: "test-7-Service:Tag moved into its child tag",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -443,12 +611,147 @@ func TestHandler_UpdateTag(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/tags/%s", testCase.tagID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-Match", testCase.ifMatch)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
 			require.Equal(t, testCase.expectedResponse, w.Body.String())
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestHandler_PatchTag(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string)
+	tagName := "new_name"
+	takenName := "work"
+
+	testTable := []struct {
+		inputJson          string
+		contentType        string
+		tagID              string
+		ifMatch            string
+		inputTag           *dto.TagUpdate
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedETag       string
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson:   `{"tagname": "new_name"}`,
+			contentType: "application/merge-patch+json",
+			tagID:       "1",
+			ifMatch:     `"2"`,
+			// service request
+			inputTag: &dto.TagUpdate{
+				TagName: &tagName,
+			},
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{TagName: "test_name", Version: 2}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 2).Return(3, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"3"`,
+			expectedResponse: `{"Updated tag with id":"1"}
+`,
+			testName: "test-1-Handler:Merge patch OK",
+		},
+		{
+			inputJson:   `[{"op": "replace", "path": "/tagname", "value": ""}]`,
+			contentType: "application/json-patch+json",
+			tagID:       "1",
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{TagName: "test_name", Version: 1}, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"tagname","code":"required","message":"tagname is required"}]}
+`,
+			testName: "test-2-Handler:Patched tag is invalid",
+		},
+		{
+			inputJson:   `{"tagname": "new_name"}`,
+			contentType: "text/plain",
+			tagID:       "1",
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{TagName: "test_name", Version: 1}, nil)
+			},
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedResponse: `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"patch must be application/merge-patch+json or application/json-patch+json","code":"unsupported_patch_type","request_id":"test-request-id"}
+`,
+			testName: "test-3-Handler:Unsupported media type",
+		},
+		{
+			inputJson:   `{"tagname": "new_name"}`,
+			contentType: "application/merge-patch+json",
+			tagID:       "1",
+			ifMatch:     `"1"`,
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{TagName: "test_name", Version: 2}, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedResponse: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"version does not match If-Match, it was changed","code":"version_mismatch","request_id":"test-request-id"}
+`,
+			testName: "test-4-Handler:Stale If-Match",
+		},
+		{
+			inputJson:   `{"tagname": "work"}`,
+			contentType: "application/merge-patch+json",
+			tagID:       "1",
+			// service request
+			inputTag: &dto.TagUpdate{
+				TagName: &takenName,
+			},
+			mockBehavior: func(s *mock_services.MockTagService, tag *dto.TagUpdate, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{TagName: "test_name", Version: 1}, nil)
+				s.EXPECT().UpdateTag(gomock.Any(), tag, tagID, 0).Return(0, errors.ErrConflict)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"tag 'work' is already exists","code":"conflict","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Tag name taken",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tagSrv := mock_services.NewMockTagService(c)
+			testCase.mockBehavior(tagSrv, testCase.inputTag, testCase.tagID, "1")
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Tag: tagSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PATCH(dictionary.TagURL, handler.logMiddleware(handler.PatchTag))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/tags/%s", testCase.tagID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			req.Header.Set("Content-Type", testCase.contentType)
+			req.Header.Set("If-Match", testCase.ifMatch)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+			require.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
 		})
 	}
 }
@@ -461,6 +764,7 @@ func TestHandler_DeleteTag(t *testing.T) {
 		headerName         string
 		headerValue        string
 		inputTag           string
+		ifMatch            string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -473,8 +777,8 @@ func TestHandler_DeleteTag(t *testing.T) {
 			inputTag:    "1",
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, nil)
-				s.EXPECT().DeleteTag(tagID, userID).Return(1, nil)
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 1}, nil)
+				s.EXPECT().DeleteTag(gomock.Any(), tagID, userID, 0).Return(1, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: `{"Deleted tag with id":1}
@@ -488,10 +792,10 @@ func TestHandler_DeleteTag(t *testing.T) {
 			inputTag:    "1",
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No tag with id '1'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No tag with id '1'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Tag not found",
 		},
@@ -502,14 +806,29 @@ func TestHandler_DeleteTag(t *testing.T) {
 			inputTag:    "1",
 			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
 				// service response
-				s.EXPECT().GetTagByID(tagID, userID).Return(nil, nil)
-				s.EXPECT().DeleteTag(tagID, userID).Return(0, e.New("some db Err"))
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 1}, nil)
+				s.EXPECT().DeleteTag(gomock.Any(), tagID, userID, 0).Return(0, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputTag:    "1",
+			ifMatch:     `"1"`,
+			mockBehavior: func(s *mock_services.MockTagService, tagID, userID string) {
+				// service response
+				s.EXPECT().GetTagByID(gomock.Any(), tagID, userID).Return(&dto.TagResp{Version: 2}, nil)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedResponse: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"version does not match If-Match, it was changed","code":"version_mismatch","request_id":"test-request-id"}
+`,
+			testName: "test-4-Handler:Stale If-Match",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -532,6 +851,275 @@ func TestHandler_DeleteTag(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/tags/%s", testCase.inputTag), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			req.Header.Set("If-Match", testCase.ifMatch)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetTagStats(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTagService, userID string)
+
+	stats := []dto.TagStatsResp{
+		{ID: "1", TagName: "TODO", NoteCount: 0},
+		{ID: "2", TagName: "todo", NoteCount: 2, LastUsedAt: &testTime},
+	}
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputQuery         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				s.EXPECT().GetTagStats(gomock.Any(), userID).Return(stats, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","tagname":"TODO","note_count":0,"last_used_at":null},{"id":"2","tagname":"todo","note_count":2,"last_used_at":"2023-01-02T03:04:05Z"}]
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				s.EXPECT().GetTagStats(gomock.Any(), userID).Return([]dto.TagStatsResp{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no tags","code":"tags_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Tags not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputQuery:  "?duplicates=true",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				duplicates := []dto.TagDuplicatesResp{{TagName: "todo", Tags: stats}}
+				s.EXPECT().GetTagDuplicates(gomock.Any(), userID).Return(duplicates, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"tagname":"todo","tags":[{"id":"1","tagname":"TODO","note_count":0,"last_used_at":null},{"id":"2","tagname":"todo","note_count":2,"last_used_at":"2023-01-02T03:04:05Z"}]}]
+`,
+			testName: "test-3-Handler:Duplicates OK",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			inputQuery:  "?duplicates=true",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				s.EXPECT().GetTagDuplicates(gomock.Any(), userID).Return([]dto.TagDuplicatesResp{}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no duplicate tags","code":"tag_duplicates_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Duplicates not found",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue:        "1",
+			inputQuery:         "?duplicates=yes",
+			mockBehavior:       func(s *mock_services.MockTagService, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'duplicates': must be true or false","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-5-Handler:Invalid duplicates",
+		},
+		{
+			headerName: "user_id",
+			// service request
+			headerValue: "1",
+			mockBehavior: func(s *mock_services.MockTagService, userID string) {
+				// service response
+				s.EXPECT().GetTagStats(gomock.Any(), userID).Return(nil, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-6-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tagSrv := mock_services.NewMockTagService(c)
+			testCase.mockBehavior(tagSrv, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Tag: tagSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server, stats is a static route of tag by id route
+			router := httprouter.New()
+			router.GET(dictionary.TagURL, middleware.StaticRoutes(
+				handler.logMiddleware(handler.GetTagByID),
+				map[string]httprouter.Handle{dictionary.TagsStatsURL: handler.logMiddleware(handler.GetTagStats)},
+			))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, dictionary.TagsStatsURL+testCase.inputQuery, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set(testCase.headerName, testCase.headerValue)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_MergeTags(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string)
+
+	testTable := []struct {
+		headerName         string
+		headerValue        string
+		inputJson          string
+		inputMerge         *dto.TagMerge
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [2, 3], "target_id": 1}`,
+			// service request
+			headerValue: "1",
+			inputMerge:  &dto.TagMerge{SourceIDs: []int64{2, 3}, TargetID: 1},
+			mockBehavior: func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {
+				// service response
+				resp := &dto.TagMergeResp{TargetID: 1, Merged: []int64{2, 3}, MovedLinks: 4}
+				s.EXPECT().MergeTags(gomock.Any(), userID, merge).Return(resp, "", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"target_id":1,"merged":[2,3],"moved_links":4}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [], "target_id": 1}`,
+			// service request
+			headerValue:        "1",
+			mockBehavior:       func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"source_ids","code":"min","message":"source_ids must be at least 1 items"}]}
+`,
+			testName: "test-2-Handler:No source tags",
+		},
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [1, 2], "target_id": 1}`,
+			// service request
+			headerValue: "1",
+			inputMerge:  &dto.TagMerge{SourceIDs: []int64{1, 2}, TargetID: 1},
+			mockBehavior: func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {
+				// service response
+				s.EXPECT().MergeTags(gomock.Any(), userID, merge).Return(nil, "1", errors.ErrTagMergeTarget)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"tag can not be merged into itself: 1","code":"tag_merge_target","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Target is source",
+		},
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [2], "target_id": 1}`,
+			// service request
+			headerValue: "1",
+			inputMerge:  &dto.TagMerge{SourceIDs: []int64{2}, TargetID: 1},
+			mockBehavior: func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {
+				// service response
+				s.EXPECT().MergeTags(gomock.Any(), userID, merge).Return(nil, "2", errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No tag with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Tag not found",
+		},
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [2], "target_id": 1}`,
+			// service request
+			headerValue: "1",
+			inputMerge:  &dto.TagMerge{SourceIDs: []int64{2}, TargetID: 1},
+			mockBehavior: func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {
+				// service response
+				s.EXPECT().MergeTags(gomock.Any(), userID, merge).Return(nil, "", errors.ErrTagCycle)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"tag can not be moved into itself or its child tags","code":"tag_cycle","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:Target is child of source",
+		},
+		{
+			headerName: "user_id",
+			inputJson:  `{"source_ids": [2], "target_id": 1}`,
+			// service request
+			headerValue: "1",
+			inputMerge:  &dto.TagMerge{SourceIDs: []int64{2}, TargetID: 1},
+			mockBehavior: func(s *mock_services.MockTagService, merge *dto.TagMerge, userID string) {
+				// service response
+				s.EXPECT().MergeTags(gomock.Any(), userID, merge).Return(nil, "", e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-6-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tagSrv := mock_services.NewMockTagService(c)
+			testCase.mockBehavior(tagSrv, testCase.inputMerge, testCase.headerValue)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Tag: tagSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.TagsMergeURL, handler.logMiddleware(handler.MergeTags))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, dictionary.TagsMergeURL, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -26,9 +26,21 @@ func NewHandler(service *services.Services, logFn dictionary.LogMiddleware) *Han
 func Register(router *httprouter.Router, service *services.Services, logFn dictionary.LogMiddleware) {
 	h := NewHandler(service, logFn)
 
-	router.POST(dictionary.TagsURL, h.logMiddleware(middleware.CheckToken(h.CreateTag, h.service.Auth)))
-	router.GET(dictionary.TagURL, h.logMiddleware(middleware.CheckToken(h.GetTagByID, h.service.Auth)))
-	router.GET(dictionary.TagsURL, h.logMiddleware(middleware.CheckToken(h.GetAllTagsByUser, h.service.Auth)))
-	router.PUT(dictionary.TagURL, h.logMiddleware(middleware.CheckToken(h.UpdateTag, h.service.Auth)))
-	router.DELETE(dictionary.TagURL, h.logMiddleware(middleware.CheckToken(h.DeleteTag, h.service.Auth)))
+	router.POST(dictionary.TagsURL, h.logMiddleware(h.auth(h.CreateTag, dictionary.ScopeTagsWrite)))
+	router.POST(dictionary.TagsMergeURL, h.logMiddleware(h.auth(h.MergeTags, dictionary.ScopeTagsWrite)))
+	router.GET(dictionary.TagURL, middleware.StaticRoutes(
+		h.logMiddleware(h.auth(h.GetTagByID, dictionary.ScopeTagsRead)),
+		map[string]httprouter.Handle{
+			dictionary.TagsStatsURL: h.logMiddleware(h.auth(h.GetTagStats, dictionary.ScopeTagsRead)),
+		},
+	))
+	router.GET(dictionary.TagsURL, h.logMiddleware(h.auth(h.GetAllTagsByUser, dictionary.ScopeTagsRead)))
+	router.PUT(dictionary.TagURL, h.logMiddleware(h.auth(h.UpdateTag, dictionary.ScopeTagsWrite)))
+	router.PATCH(dictionary.TagURL, h.logMiddleware(h.auth(h.PatchTag, dictionary.ScopeTagsWrite)))
+	router.DELETE(dictionary.TagURL, h.logMiddleware(h.auth(h.DeleteTag, dictionary.ScopeTagsWrite)))
+}
+
+// auth check token, personal access tokens must have the scope.
+func (h *Handler) auth(next httprouter.Handle, scope string) httprouter.Handle {
+	return middleware.CheckToken(next, h.service.Auth, scope)
 }
@@ -0,0 +1,76 @@
+package user
+
+import (
+	e "errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/adapters/router/validate"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// CreateAccessToken create personal access token.
+func (h *Handler) CreateAccessToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	req := &dto.AccessTokenReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	token, err := h.service.AccessToken.CreateAccessToken(ctx, userID, req)
+	if e.Is(err, errors.ErrTokenExpiresAt) {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.AccessToken, req.Name)
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusCreated, token)
+}
+
+// GetAllAccessTokens get all personal access tokens of user.
+func (h *Handler) GetAllAccessTokens(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	tokens, err := h.service.AccessToken.GetAllAccessTokens(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, tokens)
+}
+
+// DeleteAccessToken revoke personal access token by ID.
+func (h *Handler) DeleteAccessToken(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	tokenID := ps.ByName("id")
+	ctx := r.Context()
+
+	id, err := h.service.AccessToken.DeleteAccessToken(ctx, tokenID, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.AccessToken, tokenID)
+		return
+	}
+
+	resp := make(map[string]int)
+	resp["Revoked access token with id"] = id
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
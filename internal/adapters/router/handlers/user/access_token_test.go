@@ -0,0 +1,266 @@
+package user
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+
+	"web/internal/config"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/errors"
+	"web/internal/domain/services"
+	"web/internal/domain/services/mocks"
+	"web/internal/utils/dictionary"
+	l "web/pkg/logger"
+)
+
+func TestHandler_CreateAccessToken(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccessTokenService, req *dto.AccessTokenReq)
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		inputJSON          string
+		inputReq           *dto.AccessTokenReq
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"name": "ci", "scopes": ["notes:read"]}`,
+			// service request
+			inputReq: &dto.AccessTokenReq{Name: "ci", Scopes: []string{"notes:read"}},
+			mockBehavior: func(s *mock_services.MockAccessTokenService, req *dto.AccessTokenReq) {
+				// service response
+				s.EXPECT().CreateAccessToken(gomock.Any(), "1", req).Return(&dto.AccessTokenCreatedResp{
+					AccessTokenResp: dto.AccessTokenResp{
+						ID:        "1",
+						Name:      "ci",
+						Scopes:    []string{"notes:read"},
+						CreatedAt: createdAt,
+					},
+					Token: "nst_token",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse: `{"id":"1","name":"ci","scopes":["notes:read"],"expires_at":null,` +
+				`"created_at":"2023-01-01T00:00:00Z","token":"nst_token"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON:          `{"name": "ci", "scopes": ["users:write"]}`,
+			mockBehavior:       func(s *mock_services.MockAccessTokenService, req *dto.AccessTokenReq) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"scopes[0]","code":"oneof","message":"scopes[0] must be one of: notes:read, notes:write, tags:read, tags:write"}]}
+`,
+			testName: "test-2-Handler:Unknown scope",
+		},
+		{
+			inputJSON: `{"name": "ci", "scopes": ["notes:read"], "expires_at": "2000-01-01T00:00:00Z"}`,
+			// service request
+			inputReq: &dto.AccessTokenReq{
+				Name:      "ci",
+				Scopes:    []string{"notes:read"},
+				ExpiresAt: func() *time.Time { t := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); return &t }(),
+			},
+			mockBehavior: func(s *mock_services.MockAccessTokenService, req *dto.AccessTokenReq) {
+				// service response
+				s.EXPECT().CreateAccessToken(gomock.Any(), "1", req).Return(nil, errors.ErrTokenExpiresAt)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"token expiration time must be in the future","code":"invalid_expires_at","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Expired",
+		},
+		{
+			inputJSON: `{"name": "ci", "scopes": ["notes:read"]}`,
+			// service request
+			inputReq: &dto.AccessTokenReq{Name: "ci", Scopes: []string{"notes:read"}},
+			mockBehavior: func(s *mock_services.MockAccessTokenService, req *dto.AccessTokenReq) {
+				// service response
+				s.EXPECT().CreateAccessToken(gomock.Any(), "1", req).Return(nil, errors.ErrConflict)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"access token 'ci' is already exists","code":"conflict","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Duplicate name",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tokenSrv := mock_services.NewMockAccessTokenService(c)
+			testCase.mockBehavior(tokenSrv, testCase.inputReq)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{AccessToken: tokenSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.AccessTokensURL, handler.LogMiddleware(handler.CreateAccessToken))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/tokens", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetAllAccessTokens(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccessTokenService)
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			mockBehavior: func(s *mock_services.MockAccessTokenService) {
+				// service response
+				s.EXPECT().GetAllAccessTokens(gomock.Any(), "1").Return([]dto.AccessTokenResp{
+					{ID: "1", Name: "ci", Scopes: []string{"notes:read", "tags:write"}, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `[{"id":"1","name":"ci","scopes":["notes:read","tags:write"],"expires_at":null,` +
+				`"created_at":"2023-01-01T00:00:00Z"}]
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			mockBehavior: func(s *mock_services.MockAccessTokenService) {
+				// service response
+				s.EXPECT().GetAllAccessTokens(gomock.Any(), "1").Return(nil, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tokenSrv := mock_services.NewMockAccessTokenService(c)
+			testCase.mockBehavior(tokenSrv)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{AccessToken: tokenSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.GET(dictionary.AccessTokensURL, handler.LogMiddleware(handler.GetAllAccessTokens))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_DeleteAccessToken(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccessTokenService, id string)
+
+	testTable := []struct {
+		tokenID            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			// service request
+			tokenID: "1",
+			mockBehavior: func(s *mock_services.MockAccessTokenService, id string) {
+				// service response
+				s.EXPECT().DeleteAccessToken(gomock.Any(), id, "1").Return(1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Revoked access token with id":1}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			// service request
+			tokenID: "2",
+			mockBehavior: func(s *mock_services.MockAccessTokenService, id string) {
+				// service response
+				s.EXPECT().DeleteAccessToken(gomock.Any(), id, "1").Return(0, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No access token with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Token not found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			tokenSrv := mock_services.NewMockAccessTokenService(c)
+			testCase.mockBehavior(tokenSrv, testCase.tokenID)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{AccessToken: tokenSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.DELETE(dictionary.AccessTokenURL, handler.LogMiddleware(handler.DeleteAccessToken))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/tokens/%s", testCase.tokenID), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	e "errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/adapters/router/validate"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// ForgotPassword send password reset link to verified email.
+// Response is the same whether email is registered or not.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	req := dto.PasswordForgotReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := h.service.Account.ForgotPassword(ctx, req.Email); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Sent password reset link if email is verified"] = req.Email
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// ResetPassword set new password by token from mail.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	req := dto.PasswordResetReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	err := h.service.Account.ResetPassword(ctx, req.Token, req.Password)
+	if !h.accountErrCheck(w, r, err) {
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Password reset"] = "ok"
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// VerifyEmail confirm email by token from mail.
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	req := dto.EmailVerifyReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	err := h.service.Account.VerifyEmail(ctx, req.Token)
+	if !h.accountErrCheck(w, r, err) {
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Email verified"] = "ok"
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// ResendVerification send a new email verification link to user.
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+
+	err := h.service.Account.ResendVerification(r.Context(), userID)
+	if !h.accountErrCheck(w, r, err) {
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Sent email verification link to user with id"] = userID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// accountErrCheck write error response for account service errors. Returns false if response is written.
+func (h *Handler) accountErrCheck(w http.ResponseWriter, r *http.Request, err error) bool {
+	ctx := r.Context()
+
+	switch {
+	case err == nil:
+		return true
+	case e.Is(err, errors.ErrInvalidUserToken), e.Is(err, errors.ErrNoEmail), e.Is(err, errors.ErrEmailVerified):
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+	default:
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, r.Header.Get("user_id"))
+	}
+
+	return false
+}
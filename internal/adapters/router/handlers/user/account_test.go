@@ -0,0 +1,231 @@
+package user
+
+import (
+	"bytes"
+	e "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+
+	"web/internal/config"
+	"web/internal/domain/errors"
+	"web/internal/domain/services"
+	"web/internal/domain/services/mocks"
+	"web/internal/utils/dictionary"
+	l "web/pkg/logger"
+)
+
+func TestHandler_ForgotPassword(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccountService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"email": "test@example.com"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().ForgotPassword(gomock.Any(), "test@example.com").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Sent password reset link if email is verified":"test@example.com"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON:          `{"email": "test"}`,
+			mockBehavior:       func(s *mock_services.MockAccountService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"email","code":"email","message":"email must be a valid email address"}]}
+`,
+			testName: "test-2-Handler:Validation Err",
+		},
+		{
+			inputJSON: `{"email": "test@example.com"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().ForgotPassword(gomock.Any(), "test@example.com").Return(e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:DB Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			account := mock_services.NewMockAccountService(c)
+			testCase.mockBehavior(account)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Account: account}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.PasswordForgot, handler.LogMiddleware(handler.ForgotPassword))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ResetPassword(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccountService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"token": "token", "password": "New_passw0rd"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().ResetPassword(gomock.Any(), "token", "New_passw0rd").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Password reset":"ok"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON:          `{"token": "token"}`,
+			mockBehavior:       func(s *mock_services.MockAccountService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"password","code":"required","message":"password is required"}]}
+`,
+			testName: "test-2-Handler:Validation Err",
+		},
+		{
+			inputJSON: `{"token": "expired", "password": "New_passw0rd"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().ResetPassword(gomock.Any(), "expired", "New_passw0rd").Return(errors.ErrInvalidUserToken)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid or expired token","code":"invalid_user_token","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Invalid token",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			account := mock_services.NewMockAccountService(c)
+			testCase.mockBehavior(account)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Account: account}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.PasswordReset, handler.LogMiddleware(handler.ResetPassword))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_VerifyEmail(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockAccountService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"token": "token"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().VerifyEmail(gomock.Any(), "token").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Email verified":"ok"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON: `{"token": "used"}`,
+			mockBehavior: func(s *mock_services.MockAccountService) {
+				// service response
+				s.EXPECT().VerifyEmail(gomock.Any(), "used").Return(errors.ErrInvalidUserToken)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid or expired token","code":"invalid_user_token","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Invalid token",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			account := mock_services.NewMockAccountService(c)
+			testCase.mockBehavior(account)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Account: account}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.EmailVerify, handler.LogMiddleware(handler.VerifyEmail))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/email/verify", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package user
 
 import (
-	"encoding/json"
+	e "errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
 
 	"web/internal/adapters/router/validate"
 	"web/internal/domain/entities/dto"
@@ -21,23 +24,20 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request, _ httprou
 	ctx := r.Context()
 
 	newUser := &model.User{}
-	if err := json.NewDecoder(r.Body).Decode(&newUser); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &newUser); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 	// Валидация объекта структуры User //
 	err := validate.InputJSONValidate(newUser)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	user, err := h.service.Auth.RegisterUser(newUser)
+	user, err := h.service.Auth.RegisterUser(ctx, newUser)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, newUser.Username)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -47,46 +47,134 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request, _ httprou
 	functions.MakeJSONResponse(w, http.StatusCreated, resp)
 }
 
-// GenerateToken generate token for user auth.
+// GenerateToken generate token for user auth. Users with two-factor auth get a challenge instead.
 func (h *Handler) GenerateToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
 	user := dto.UserAuth{}
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &user); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 	// Валидация объекта структуры UserAuth //
 	err := validate.InputJSONValidate(user)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	clientIP := h.service.LoginGuard.ClientIP(r.RemoteAddr, r.Header.Get("X-Real-IP"))
+
+	if !h.checkLoginGuard(w, r, user.Username, clientIP) {
+		return
+	}
+
+	login, err := h.service.Auth.GenerateToken(ctx, user.Username, user.Password)
+	if e.Is(err, errors.ErrInvalidCredentials) {
+		h.service.LoginGuard.Fail(user.Username, clientIP)
+		functions.Abort(ctx, w, http.StatusUnauthorized, nil, err, "", "")
 		return
 	}
 
-	token, err := h.service.Auth.GenerateToken(user.Username, user.Password)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, user.Username)
-		logger.LogFromContext(ctx).Error(err.Error())
+		return
+	}
+
+	// with two-factor auth login is not finished until the second step
+	if login.TokensResp != nil {
+		h.service.LoginGuard.Reset(user.Username)
+		login.AccessToken = fmt.Sprintf("Bearer %s", login.AccessToken)
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, login)
+}
+
+// checkLoginGuard check login attempt of username from client IP is allowed, blocked attempt is aborted.
+func (h *Handler) checkLoginGuard(w http.ResponseWriter, r *http.Request, userName, clientIP string) bool {
+	ctx := r.Context()
+
+	retryAfter, err := h.service.LoginGuard.Check(userName, clientIP)
+	if err == nil {
+		return true
+	}
+
+	logger.LogFromContext(ctx).Warn("login attempt blocked",
+		zap.String("username", userName),
+		zap.String("client_ip", clientIP),
+		zap.Duration("retry_after", retryAfter),
+		zap.Error(err),
+	)
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	functions.Abort(ctx, w, http.StatusTooManyRequests, nil, err, "", "")
+
+	return false
+}
+
+// RefreshToken exchange refresh token for a new tokens pair.
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	req := dto.RefreshTokenReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	err := validate.InputJSONValidate(req)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	tokens, err := h.service.Auth.RefreshToken(ctx, req.RefreshToken)
+	if e.Is(err, errors.ErrInvalidRefreshToken) {
+		functions.Abort(ctx, w, http.StatusUnauthorized, nil, err, "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	tokens.AccessToken = fmt.Sprintf("Bearer %s", tokens.AccessToken)
+
+	functions.MakeJSONResponse(w, http.StatusOK, tokens)
+}
+
+// Logout revoke current access token and its refresh token.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	tokenID := r.Header.Get("token_id")
+	ctx := r.Context()
+
+	if err := h.service.Auth.Logout(ctx, tokenID); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
 		return
 	}
 
 	resp := make(map[string]string)
-	resp["token"] = fmt.Sprintf("Bearer %s", token)
+	resp["Logged out user with id"] = userID
 
 	functions.MakeJSONResponse(w, http.StatusOK, resp)
 }
 
+// GetJWKS get public keys for tokens verification (JSON Web Key Set).
+func (h *Handler) GetJWKS(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	functions.MakeJSONResponse(w, http.StatusOK, h.service.Auth.JWKS())
+}
+
 // GetUserByID get user by ID.
 func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	userID := ps.ByName("id")
 	ctx := r.Context()
 
-	user, err := h.service.User.GetUserByID(userID)
+	user, err := h.service.User.GetUserByID(ctx, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, userID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
@@ -97,16 +185,20 @@ func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request, ps httprou
 func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
-	users, err := h.service.User.GetAllUsers()
+	listQuery, err := functions.ParseListQuery(r.URL.Query())
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	users, err := h.service.User.GetAllUsers(ctx, listQuery)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
 	if len(users) == 0 {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrUsersListEmpty, "", "")
-		logger.LogFromContext(ctx).Error(errors.ErrUsersListEmpty.Error())
 		return
 	}
 
@@ -119,23 +211,93 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request, ps httprout
 	ctx := r.Context()
 
 	newUser := &dto.UserUpdate{}
-	if err := json.NewDecoder(r.Body).Decode(&newUser); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		logger.LogFromContext(ctx).Error(err.Error())
+	if err := validate.DecodeJSON(r.Body, &newUser); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(newUser); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
 		return
 	}
 
-	_, err := h.service.User.GetUserByID(userID)
+	// only admins can change roles
+	if newUser.Role != nil && r.Header.Get("user_role") != dictionary.RoleAdmin {
+		functions.Abort(ctx, w, http.StatusForbidden, nil, errors.ErrForbidden, "", "")
+		return
+	}
+
+	_, err := h.service.User.GetUserByID(ctx, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, userID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	err = h.service.User.UpdateUser(newUser, userID)
-	if err != nil {
+	err = h.service.User.UpdateUser(ctx, newUser, userID)
+	if err != nil && newUser.Username != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, *newUser.Username)
-		logger.LogFromContext(ctx).Error(err.Error())
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Updated user with id"] = userID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// PatchUser patch user by ID with JSON Merge Patch or JSON Patch, patched user is validated before update.
+// Username is validated only when it is changed.
+func (h *Handler) PatchUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID := ps.ByName("id")
+	ctx := r.Context()
+
+	user, err := h.service.User.GetUserByID(ctx, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, userID)
+		return
+	}
+
+	patched := &dto.UserPatch{Username: user.Username, Role: user.Role}
+	if err := validate.DecodePatch(r.Header.Get("Content-Type"), r.Body, patched); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	// unchanged username is not validated, it may predate username rules
+	var except []string
+	if patched.Username == user.Username {
+		except = append(except, "Username")
+	}
+
+	if err := validate.InputJSONValidate(patched, except...); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	// only changed fields are updated
+	newUser := &dto.UserUpdate{Password: patched.Password}
+	if patched.Username != user.Username {
+		newUser.Username = &patched.Username
+	}
+
+	if patched.Role != user.Role {
+		newUser.Role = &patched.Role
+	}
+
+	// only admins can change roles
+	if newUser.Role != nil && r.Header.Get("user_role") != dictionary.RoleAdmin {
+		functions.Abort(ctx, w, http.StatusForbidden, nil, errors.ErrForbidden, "", "")
+		return
+	}
+
+	err = h.service.User.UpdateUser(ctx, newUser, userID)
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, patched.Username)
 		return
 	}
 
@@ -150,17 +312,15 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request, ps httprout
 	userID := ps.ByName("id")
 	ctx := r.Context()
 
-	_, err := h.service.User.GetUserByID(userID)
+	_, err := h.service.User.GetUserByID(ctx, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, userID)
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
-	id, err := h.service.User.DeleteUser(userID)
+	id, err := h.service.User.DeleteUser(ctx, userID)
 	if err != nil {
 		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
-		logger.LogFromContext(ctx).Error(err.Error())
 		return
 	}
 
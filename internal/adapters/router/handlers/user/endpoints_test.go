@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/julienschmidt/httprouter"
@@ -19,9 +20,16 @@ import (
 	"web/internal/domain/services"
 	"web/internal/domain/services/mocks"
 	"web/internal/utils/dictionary"
+	"web/pkg/jwtkeys"
 	l "web/pkg/logger"
 )
 
+// testTime created_at and updated_at of service responses.
+var testTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// testRequestID request id of test requests, error responses have it.
+const testRequestID = "test-request-id"
+
 func TestHandler_RegisterUser(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockUserAuthService, user *model.User)
@@ -37,21 +45,21 @@ func TestHandler_RegisterUser(t *testing.T) {
 		{
 			inputJson: `{
 				"username": "test_name",
-				"password": "test_password"
+				"password": "Test_passw0rd"
 			}`,
 			// service request
 			inputUser: &model.User{
 				Username: "test_name",
-				Password: "test_password",
+				Password: "Test_passw0rd",
 			},
 			mockBehavior: func(s *mock_services.MockUserAuthService, user *model.User) {
 				// service response
 				outputUser := &model.User{
 					ID:       "1",
 					Username: "test_name",
-					Password: "test_password",
+					Password: "Test_passw0rd",
 				}
-				s.EXPECT().RegisterUser(user).Return(outputUser, nil)
+				s.EXPECT().RegisterUser(gomock.Any(), user).Return(outputUser, nil)
 			},
 			expectedStatusCode: http.StatusCreated,
 			expectedResponse: `{"Created new user 'test_name' with id":"1"}
@@ -65,7 +73,7 @@ func TestHandler_RegisterUser(t *testing.T) {
 			inputUser:          &model.User{},
 			mockBehavior:       func(s *mock_services.MockUserAuthService, user *model.User) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -76,48 +84,85 @@ func TestHandler_RegisterUser(t *testing.T) {
 			inputUser:          &model.User{},
 			mockBehavior:       func(s *mock_services.MockUserAuthService, user *model.User) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `Key: 'User.Password' Error:Field validation for 'Password' failed on the 'required' tag
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"password","code":"required","message":"password is required"}]}
 `,
 			testName: "test-3-Handler:Validation Err",
 		},
 		{
 			inputJson: `{
 				"username": "test_name",
-				"password": "test_password"
+				"password": "Test_passw0rd"
 			}`,
 			// service request
 			inputUser: &model.User{
 				Username: "test_name",
-				Password: "test_password",
+				Password: "Test_passw0rd",
 			},
 			mockBehavior: func(s *mock_services.MockUserAuthService, user *model.User) {
 				// service response
-				s.EXPECT().RegisterUser(user).Return(nil, e.New(errors.ErrDBDuplicate))
+				s.EXPECT().RegisterUser(gomock.Any(), user).Return(nil, errors.ErrConflict)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"user 'test_name' is already exists"}
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"user 'test_name' is already exists","code":"conflict","request_id":"test-request-id"}
 `,
 			testName: "test-4-Service:User is already exists Err",
 		},
 		{
 			inputJson: `{
 				"username": "test_name",
-				"password": "test_password"
+				"password": "Test_passw0rd"
 			}`,
 			// service request
 			inputUser: &model.User{
 				Username: "test_name",
-				Password: "test_password",
+				Password: "Test_passw0rd",
 			},
 			mockBehavior: func(s *mock_services.MockUserAuthService, user *model.User) {
 				// service response
-				s.EXPECT().RegisterUser(user).Return(nil, e.New("some db Err"))
+				s.EXPECT().RegisterUser(gomock.Any(), user).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-5-Service:Db resp Err",
 		},
+		{
+			inputJson: `{
+				"username": "1st name",
+				"password": "weak"
+			}`,
+			inputUser:          &model.User{},
+			mockBehavior:       func(s *mock_services.MockUserAuthService, user *model.User) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"username","code":"username","message":"username must start with a letter and contain only letters, digits, '.', '_' and '-'"},{"field":"password","code":"min","message":"password must be at least 8 characters"}]}
+`,
+			testName: "test-6-Handler:All validation errors",
+		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "test_password"
+			}`,
+			inputUser:          &model.User{},
+			mockBehavior:       func(s *mock_services.MockUserAuthService, user *model.User) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"password","code":"password","message":"password must contain upper and lower case letters and digits"}]}
+`,
+			testName: "test-7-Handler:Weak password",
+		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "Test_passw0rd",
+				"role": "admin"
+			}`,
+			inputUser:          &model.User{},
+			mockBehavior:       func(s *mock_services.MockUserAuthService, user *model.User) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"role","code":"unknown_field","message":"role is not a known field"}]}
+`,
+			testName: "test-8-Handler:Unknown field",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -140,6 +185,7 @@ func TestHandler_RegisterUser(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, dictionary.Register, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -152,14 +198,17 @@ func TestHandler_RegisterUser(t *testing.T) {
 func TestHandler_GenerateToken(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockUserAuthService, userName, password string)
+	type guardBehavior func(g *mock_services.MockLoginGuardService, userName string)
 
 	testTable := []struct {
 		inputJson          string
 		userName           string
 		password           string
 		mockBehavior       mockBehavior
+		guardBehavior      guardBehavior
 		expectedStatusCode int
 		expectedResponse   string
+		expectedRetryAfter string
 		testName           string
 	}{
 		{
@@ -172,21 +221,51 @@ func TestHandler_GenerateToken(t *testing.T) {
 			password: "test_password",
 			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {
 				// service response
-				outputToken := "generatedToken"
-				s.EXPECT().GenerateToken(userName, password).Return(outputToken, nil)
+				outputTokens := &dto.LoginResp{TokensResp: &dto.TokensResp{
+					AccessToken:  "generatedToken",
+					RefreshToken: "refreshToken",
+					ExpiresIn:    900,
+				}}
+				s.EXPECT().GenerateToken(gomock.Any(), userName, password).Return(outputTokens, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"token":"Bearer generatedToken"}
+			expectedResponse: `{"token":"Bearer generatedToken","refresh_token":"refreshToken","expires_in":900}
 `,
 			testName: "test-1-Handler:OK",
 		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "test_password"
+			}`,
+			// service request
+			userName: "test_name",
+			password: "test_password",
+			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {
+				// service response
+				outputChallenge := &dto.LoginResp{TwoFactorChallengeResp: &dto.TwoFactorChallengeResp{
+					TwoFactorRequired:  true,
+					Challenge:          "challenge",
+					ChallengeExpiresIn: 300,
+				}}
+				s.EXPECT().GenerateToken(gomock.Any(), userName, password).Return(outputChallenge, nil)
+			},
+			guardBehavior: func(g *mock_services.MockLoginGuardService, userName string) {
+				// failed attempts are not reset before the second step
+				g.EXPECT().Check(userName, "192.0.2.1").Return(time.Duration(0), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"two_factor_required":true,"challenge":"challenge","challenge_expires_in":300}
+`,
+			testName: "test-1-Handler:Two-factor challenge",
+		},
 		{
 			inputJson: `{
 				"username": "test_name
 			}`,
 			mockBehavior:       func(s *mock_services.MockUserAuthService, userName, password string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -196,7 +275,7 @@ func TestHandler_GenerateToken(t *testing.T) {
 			}`,
 			mockBehavior:       func(s *mock_services.MockUserAuthService, userName, password string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `Key: 'UserAuth.Password' Error:Field validation for 'Password' failed on the 'required' tag
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"password","code":"required","message":"password is required"}]}
 `,
 			testName: "test-3-Handler:Validation Err",
 		},
@@ -210,12 +289,17 @@ func TestHandler_GenerateToken(t *testing.T) {
 			password: "test_password",
 			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {
 				// service response
-				s.EXPECT().GenerateToken(userName, password).Return("", e.New(errors.ErrDBNotExists))
+				// unknown user is not told apart from wrong password
+				s.EXPECT().GenerateToken(gomock.Any(), userName, password).Return(nil, errors.ErrInvalidCredentials)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No user with name 'test_name'"}
+			guardBehavior: func(g *mock_services.MockLoginGuardService, userName string) {
+				g.EXPECT().Check(userName, "192.0.2.1").Return(time.Duration(0), nil)
+				g.EXPECT().Fail(userName, "192.0.2.1")
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid username or password","code":"invalid_credentials","request_id":"test-request-id"}
 `,
-			testName: "test-4-Service:User not found",
+			testName: "test-4-Service:Unknown user",
 		},
 		{
 			inputJson: `{
@@ -227,13 +311,66 @@ func TestHandler_GenerateToken(t *testing.T) {
 			password: "test_password",
 			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {
 				// service response
-				s.EXPECT().GenerateToken(userName, password).Return("", e.New("some db Err"))
+				s.EXPECT().GenerateToken(gomock.Any(), userName, password).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-5-Service:Db resp Err",
 		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "wrong_password"
+			}`,
+			// service request
+			userName: "test_name",
+			password: "wrong_password",
+			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {
+				// service response
+				s.EXPECT().GenerateToken(gomock.Any(), userName, password).Return(nil, errors.ErrInvalidCredentials)
+			},
+			guardBehavior: func(g *mock_services.MockLoginGuardService, userName string) {
+				g.EXPECT().Check(userName, "192.0.2.1").Return(time.Duration(0), nil)
+				g.EXPECT().Fail(userName, "192.0.2.1")
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid username or password","code":"invalid_credentials","request_id":"test-request-id"}
+`,
+			testName: "test-6-Service:Invalid credentials",
+		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "test_password"
+			}`,
+			userName:     "test_name",
+			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {},
+			guardBehavior: func(g *mock_services.MockLoginGuardService, userName string) {
+				g.EXPECT().Check(userName, "192.0.2.1").Return(1500*time.Millisecond, errors.ErrTooManyAttempts)
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedResponse: `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"too many failed login attempts, try again later","code":"too_many_attempts","request_id":"test-request-id"}
+`,
+			expectedRetryAfter: "2",
+			testName:           "test-7-Guard:Backoff",
+		},
+		{
+			inputJson: `{
+				"username": "test_name",
+				"password": "test_password"
+			}`,
+			userName:     "test_name",
+			mockBehavior: func(s *mock_services.MockUserAuthService, userName, password string) {},
+			guardBehavior: func(g *mock_services.MockLoginGuardService, userName string) {
+				g.EXPECT().Check(userName, "192.0.2.1").Return(15*time.Minute, errors.ErrAccountLocked)
+			},
+			expectedStatusCode: http.StatusLocked,
+			expectedResponse: `{"type":"about:blank","title":"Locked","status":423,"detail":"account is temporarily locked after too many failed login attempts","code":"account_locked","request_id":"test-request-id"}
+`,
+			expectedRetryAfter: "900",
+			testName:           "test-8-Guard:Locked",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -244,11 +381,21 @@ func TestHandler_GenerateToken(t *testing.T) {
 			// Init mock service
 			auth := mock_services.NewMockUserAuthService(c)
 			testCase.mockBehavior(auth, testCase.userName, testCase.password)
+			// Init mock login guard, by default every attempt is allowed
+			guard := mock_services.NewMockLoginGuardService(c)
+			guard.EXPECT().ClientIP(gomock.Any(), gomock.Any()).Return("192.0.2.1").AnyTimes()
+			if testCase.guardBehavior != nil {
+				testCase.guardBehavior(guard, testCase.userName)
+			} else {
+				guard.EXPECT().Check(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				guard.EXPECT().Fail(gomock.Any(), gomock.Any()).AnyTimes()
+				guard.EXPECT().Reset(gomock.Any()).AnyTimes()
+			}
 			// Init testing logger with "fatal" level (5)
 			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
 			loggingMiddleware := l.NewLoggerMiddleware(logger)
 			// Init service
-			service := &services.Services{Auth: auth}
+			service := &services.Services{Auth: auth, LoginGuard: guard}
 			handler := NewHandler(service, loggingMiddleware)
 			// Test server
 			router := httprouter.New()
@@ -256,6 +403,246 @@ func TestHandler_GenerateToken(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, dictionary.Login, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+			require.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestHandler_RefreshToken(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockUserAuthService, refreshToken string)
+
+	testTable := []struct {
+		inputJson          string
+		refreshToken       string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson: `{"refresh_token": "oldRefreshToken"}`,
+			// service request
+			refreshToken: "oldRefreshToken",
+			mockBehavior: func(s *mock_services.MockUserAuthService, refreshToken string) {
+				// service response
+				outputTokens := &dto.TokensResp{
+					AccessToken:  "generatedToken",
+					RefreshToken: "newRefreshToken",
+					ExpiresIn:    900,
+				}
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).Return(outputTokens, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"token":"Bearer generatedToken","refresh_token":"newRefreshToken","expires_in":900}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJson:          `{}`,
+			mockBehavior:       func(s *mock_services.MockUserAuthService, refreshToken string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"refresh_token","code":"required","message":"refresh_token is required"}]}
+`,
+			testName: "test-2-Handler:Validation Err",
+		},
+		{
+			inputJson: `{"refresh_token": "oldRefreshToken"}`,
+			// service request
+			refreshToken: "oldRefreshToken",
+			mockBehavior: func(s *mock_services.MockUserAuthService, refreshToken string) {
+				// service response
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).Return(nil, errors.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid refresh token","code":"invalid_refresh_token","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Invalid refresh token",
+		},
+		{
+			inputJson: `{"refresh_token": "oldRefreshToken"}`,
+			// service request
+			refreshToken: "oldRefreshToken",
+			mockBehavior: func(s *mock_services.MockUserAuthService, refreshToken string) {
+				// service response
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).Return(nil, e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			auth := mock_services.NewMockUserAuthService(c)
+			testCase.mockBehavior(auth, testCase.refreshToken)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Auth: auth}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.RefreshToken, handler.LogMiddleware(handler.RefreshToken))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, dictionary.RefreshToken, bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_Logout(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockUserAuthService, tokenID string)
+
+	testTable := []struct {
+		userID             string
+		tokenID            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			userID:  "1",
+			tokenID: "jti",
+			mockBehavior: func(s *mock_services.MockUserAuthService, tokenID string) {
+				s.EXPECT().Logout(gomock.Any(), tokenID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Logged out user with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			userID:  "1",
+			tokenID: "jti",
+			mockBehavior: func(s *mock_services.MockUserAuthService, tokenID string) {
+				s.EXPECT().Logout(gomock.Any(), tokenID).Return(e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			auth := mock_services.NewMockUserAuthService(c)
+			testCase.mockBehavior(auth, testCase.tokenID)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Auth: auth}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.Logout, handler.LogMiddleware(handler.Logout))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, dictionary.Logout, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", testCase.userID)
+			req.Header.Set("token_id", testCase.tokenID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetJWKS(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockUserAuthService)
+
+	testTable := []struct {
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			mockBehavior: func(s *mock_services.MockUserAuthService) {
+				// service response
+				outputKeys := &jwtkeys.JWKS{
+					Keys: []jwtkeys.JWK{
+						{
+							Kty: "OKP",
+							Kid: "key1",
+							Use: "sig",
+							Alg: "EdDSA",
+							Crv: "Ed25519",
+							X:   "x",
+						},
+					},
+				}
+				s.EXPECT().JWKS().Return(outputKeys)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"keys":[{"kty":"OKP","kid":"key1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"x"}]}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			mockBehavior: func(s *mock_services.MockUserAuthService) {
+				// service response
+				s.EXPECT().JWKS().Return(&jwtkeys.JWKS{Keys: []jwtkeys.JWK{}})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"keys":[]}
+`,
+			testName: "test-2-Handler:HMAC keys only",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			auth := mock_services.NewMockUserAuthService(c)
+			testCase.mockBehavior(auth)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Auth: auth}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.GET(dictionary.JWKS, handler.LogMiddleware(handler.GetJWKS))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, dictionary.JWKS, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -282,13 +669,15 @@ func TestHandler_GetUserByID(t *testing.T) {
 			mockBehavior: func(s *mock_services.MockUserService, userID string) {
 				// service response
 				outputUser := &dto.UserResp{
-					ID:       "1",
-					Username: "test_name",
+					ID:        "1",
+					Username:  "test_name",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
 				}
-				s.EXPECT().GetUserByID(userID).Return(outputUser, nil)
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(outputUser, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `{"id":"1","username":"test_name"}
+			expectedResponse: `{"id":"1","username":"test_name","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}
 `,
 			testName: "test-1-Handler:OK",
 		},
@@ -297,10 +686,10 @@ func TestHandler_GetUserByID(t *testing.T) {
 			userID: "2",
 			mockBehavior: func(s *mock_services.MockUserService, userID string) {
 				// service response
-				s.EXPECT().GetUserByID(userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No user with id '2'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No user with id '2'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:User not found",
 		},
@@ -309,10 +698,10 @@ func TestHandler_GetUserByID(t *testing.T) {
 			userID: "3",
 			mockBehavior: func(s *mock_services.MockUserService, userID string) {
 				// service response
-				s.EXPECT().GetUserByID(userID).Return(nil, e.New("some db Err"))
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
@@ -338,6 +727,7 @@ func TestHandler_GetUserByID(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%s", testCase.userID), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -352,6 +742,7 @@ func TestHandler_GetAllUsers(t *testing.T) {
 	type mockBehavior func(s *mock_services.MockUserService)
 
 	testTable := []struct {
+		query              string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -362,41 +753,75 @@ func TestHandler_GetAllUsers(t *testing.T) {
 				// service response
 				outputUser := []dto.UserResp{
 					{
-						ID:       "1",
-						Username: "test_name1",
+						ID:        "1",
+						Username:  "test_name1",
+						CreatedAt: testTime,
+						UpdatedAt: testTime,
 					},
 					{
-						ID:       "2",
-						Username: "test_name2",
+						ID:        "2",
+						Username:  "test_name2",
+						CreatedAt: testTime,
+						UpdatedAt: testTime,
 					},
 				}
-				s.EXPECT().GetAllUsers().Return(outputUser, nil)
+				s.EXPECT().GetAllUsers(gomock.Any(), &dto.ListQuery{}).Return(outputUser, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: `[{"id":"1","username":"test_name1"},{"id":"2","username":"test_name2"}]
+			expectedResponse: `[{"id":"1","username":"test_name1","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"},{"id":"2","username":"test_name2","created_at":"2023-01-02T03:04:05Z","updated_at":"2023-01-02T03:04:05Z"}]
 `,
 			testName: "test-1-Handler:OK",
 		},
 		{
 			mockBehavior: func(s *mock_services.MockUserService) {
 				// service response
-				s.EXPECT().GetAllUsers().Return(nil, nil)
+				s.EXPECT().GetAllUsers(gomock.Any(), &dto.ListQuery{}).Return(nil, nil)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"no users"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no users","code":"users_not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:Users not found",
 		},
 		{
 			mockBehavior: func(s *mock_services.MockUserService) {
 				// service response
-				s.EXPECT().GetAllUsers().Return(nil, e.New("some db Err"))
+				s.EXPECT().GetAllUsers(gomock.Any(), &dto.ListQuery{}).Return(nil, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
+		{
+			query: "?created_after=2023-01-02T03:04:05Z",
+			mockBehavior: func(s *mock_services.MockUserService) {
+				// service response
+				s.EXPECT().GetAllUsers(gomock.Any(), &dto.ListQuery{CreatedAfter: &testTime}).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no users","code":"users_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-4-Handler:Created after filter",
+		},
+		{
+			query: "?updated_since=2023-01-02T03:04:05Z",
+			mockBehavior: func(s *mock_services.MockUserService) {
+				// service response
+				s.EXPECT().GetAllUsers(gomock.Any(), &dto.ListQuery{UpdatedSince: &testTime}).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"no users","code":"users_not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Handler:Updated since filter",
+		},
+		{
+			query:              "?created_after=yesterday",
+			mockBehavior:       func(s *mock_services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid query parameter 'created_after': time must be in RFC 3339 format","code":"invalid_query_param","request_id":"test-request-id"}
+`,
+			testName: "test-6-Handler:Invalid created_after",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -418,7 +843,8 @@ func TestHandler_GetAllUsers(t *testing.T) {
 			router.GET(dictionary.UsersURL, handler.LogMiddleware(handler.GetAllUsers))
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodGet, dictionary.UsersURL, nil)
+			req := httptest.NewRequest(http.MethodGet, dictionary.UsersURL+testCase.query, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -432,12 +858,13 @@ func TestHandler_UpdateUser(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string)
 	userName := "test_name"
-	password := "test_password"
+	password := "Test_passw0rd"
 
 	testTable := []struct {
 		inputJson          string
 		inputUser          *dto.UserUpdate
 		userID             string
+		role               string
 		mockBehavior       mockBehavior
 		expectedStatusCode int
 		expectedResponse   string
@@ -446,7 +873,7 @@ func TestHandler_UpdateUser(t *testing.T) {
 		{
 			inputJson: `{
 				"username": "test_name",
-				"password": "test_password"
+				"password": "Test_passw0rd"
 			}`,
 			// service request
 			inputUser: &dto.UserUpdate{
@@ -455,8 +882,8 @@ func TestHandler_UpdateUser(t *testing.T) {
 			},
 			userID: "1",
 			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
-				s.EXPECT().GetUserByID(userID).Return(nil, nil)
-				s.EXPECT().UpdateUser(user, userID).Return(nil)
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: `{"Updated user with id":"1"}
@@ -471,7 +898,7 @@ func TestHandler_UpdateUser(t *testing.T) {
 			inputUser:          &dto.UserUpdate{},
 			mockBehavior:       func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `invalid character '\n' in string literal
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed JSON body: invalid character '\\n' in string","code":"malformed_request","request_id":"test-request-id"}
 `,
 			testName: "test-2-Handler:Bad JSON",
 		},
@@ -481,17 +908,17 @@ func TestHandler_UpdateUser(t *testing.T) {
 			userID: "2",
 			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
 				// service response
-				s.EXPECT().GetUserByID(userID).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No user with id '2'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No user with id '2'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:User not found",
 		},
 		{
 			inputJson: `{
 				"username": "test_name",
-				"password": "test_password"
+				"password": "Test_passw0rd"
 			}`,
 			// service request
 			inputUser: &dto.UserUpdate{
@@ -500,14 +927,56 @@ func TestHandler_UpdateUser(t *testing.T) {
 			},
 			userID: "1",
 			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
-				s.EXPECT().GetUserByID(userID).Return(nil, nil)
-				s.EXPECT().UpdateUser(user, userID).Return(e.New("some db Err"))
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-4-Service:Db resp Err",
 		},
+		{
+			inputJson: `{
+				"role": "admin"
+			}`,
+			userID:             "1",
+			role:               "user",
+			mockBehavior:       func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"access denied","code":"access_denied","request_id":"test-request-id"}
+`,
+			testName: "test-5-Handler:Role change by user",
+		},
+		{
+			inputJson: `{
+				"role": "owner"
+			}`,
+			userID:             "1",
+			role:               "admin",
+			mockBehavior:       func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"role","code":"oneof","message":"role must be one of: user, admin"}]}
+`,
+			testName: "test-6-Handler:Invalid role",
+		},
+		{
+			inputJson: `{
+				"password": "Test_passw0rd"
+			}`,
+			// service request
+			inputUser: &dto.UserUpdate{
+				Password: &password,
+			},
+			userID: "1",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(e.New("failed to revoke sessions"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-7-Service:Password update Err",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -530,6 +999,159 @@ func TestHandler_UpdateUser(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/users/%s", testCase.userID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_role", testCase.role)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_PatchUser(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string)
+	userName := "new_name"
+	password := "Test_passw0rd"
+	roleAdmin := "admin"
+
+	testTable := []struct {
+		inputJson          string
+		contentType        string
+		inputUser          *dto.UserUpdate
+		userID             string
+		role               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJson:   `[{"op": "replace", "path": "/username", "value": "new_name"}, {"op": "add", "path": "/password", "value": "Test_passw0rd"}]`,
+			contentType: "application/json-patch+json",
+			// service request
+			inputUser: &dto.UserUpdate{
+				Username: &userName,
+				Password: &password,
+			},
+			userID: "1",
+			role:   "user",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				current := &dto.UserResp{ID: userID, Username: "test_name", Role: "user"}
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(current, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Updated user with id":"1"}
+`,
+			testName: "test-1-Handler:JSON patch OK",
+		},
+		{
+			inputJson:   `{"role": "admin"}`,
+			contentType: "application/merge-patch+json",
+			// service request
+			inputUser: &dto.UserUpdate{
+				Role: &roleAdmin,
+			},
+			userID: "2",
+			role:   "admin",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				current := &dto.UserResp{ID: userID, Username: "test_name", Role: "user"}
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(current, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Updated user with id":"2"}
+`,
+			testName: "test-2-Handler:Role change by admin",
+		},
+		{
+			inputJson:   `{"role": "admin"}`,
+			contentType: "application/merge-patch+json",
+			userID:      "1",
+			role:        "user",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				current := &dto.UserResp{ID: userID, Username: "test_name", Role: "user"}
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"access denied","code":"access_denied","request_id":"test-request-id"}
+`,
+			testName: "test-3-Handler:Role change by user",
+		},
+		{
+			inputJson:   `{"username": "1st", "password": "short"}`,
+			contentType: "application/merge-patch+json",
+			userID:      "1",
+			role:        "user",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				current := &dto.UserResp{ID: userID, Username: "test_name", Role: "user"}
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(current, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"username","code":"username","message":"username must start with a letter and contain only letters, digits, '.', '_' and '-'"},{"field":"password","code":"min","message":"password must be at least 8 characters"}]}
+`,
+			testName: "test-4-Handler:Patched user is invalid",
+		},
+		{
+			inputJson:   `{}`,
+			contentType: "application/merge-patch+json",
+			userID:      "2",
+			role:        "admin",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, errors.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No user with id '2'","code":"not_found","request_id":"test-request-id"}
+`,
+			testName: "test-5-Service:User not found",
+		},
+		{
+			inputJson:   `{"password": "Test_passw0rd"}`,
+			contentType: "application/merge-patch+json",
+			// service request
+			inputUser: &dto.UserUpdate{
+				Password: &password,
+			},
+			userID: "1",
+			role:   "user",
+			mockBehavior: func(s *mock_services.MockUserService, user *dto.UserUpdate, userID string) {
+				current := &dto.UserResp{ID: userID, Username: "1st user", Role: "user"}
+				s.EXPECT().GetUserByID(gomock.Any(), userID).Return(current, nil)
+				s.EXPECT().UpdateUser(gomock.Any(), user, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Updated user with id":"1"}
+`,
+			testName: "test-6-Handler:Unchanged legacy username is not validated",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			userSrv := mock_services.NewMockUserService(c)
+			testCase.mockBehavior(userSrv, testCase.inputUser, testCase.userID)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{User: userSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.PATCH(dictionary.UserURL, handler.LogMiddleware(handler.PatchUser))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/users/%s", testCase.userID), bytes.NewBufferString(testCase.inputJson))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_role", testCase.role)
+			req.Header.Set("Content-Type", testCase.contentType)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -555,8 +1177,8 @@ func TestHandler_DeleteUser(t *testing.T) {
 			userID: "1",
 			mockBehavior: func(s *mock_services.MockUserService, id string) {
 				// service response
-				s.EXPECT().GetUserByID(id).Return(nil, nil)
-				s.EXPECT().DeleteUser(id).Return(1, nil)
+				s.EXPECT().GetUserByID(gomock.Any(), id).Return(nil, nil)
+				s.EXPECT().DeleteUser(gomock.Any(), id).Return(1, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: `{"Deleted user with id":1}
@@ -568,10 +1190,10 @@ func TestHandler_DeleteUser(t *testing.T) {
 			userID: "2",
 			mockBehavior: func(s *mock_services.MockUserService, id string) {
 				// service response
-				s.EXPECT().GetUserByID(id).Return(nil, e.New(errors.ErrDBNotExists))
+				s.EXPECT().GetUserByID(gomock.Any(), id).Return(nil, errors.ErrNotFound)
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"error":"No user with id '2'"}
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse: `{"type":"about:blank","title":"Not Found","status":404,"detail":"No user with id '2'","code":"not_found","request_id":"test-request-id"}
 `,
 			testName: "test-2-Service:User not found",
 		},
@@ -580,11 +1202,11 @@ func TestHandler_DeleteUser(t *testing.T) {
 			userID: "1",
 			mockBehavior: func(s *mock_services.MockUserService, id string) {
 				// service response
-				s.EXPECT().GetUserByID(id).Return(nil, nil)
-				s.EXPECT().DeleteUser(id).Return(0, e.New("some db Err"))
+				s.EXPECT().GetUserByID(gomock.Any(), id).Return(nil, nil)
+				s.EXPECT().DeleteUser(gomock.Any(), id).Return(0, e.New("some db Err"))
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse: `{"desc":"some db Err","error":"db response error"}
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
 `,
 			testName: "test-3-Service:Db resp Err",
 		},
@@ -610,6 +1232,7 @@ func TestHandler_DeleteUser(t *testing.T) {
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/users/%s", testCase.userID), nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			// Make Request
 			router.ServeHTTP(w, req)
 			// Assert
@@ -4,6 +4,7 @@ package user
 import (
 	"github.com/julienschmidt/httprouter"
 
+	"web/internal/adapters/router/middleware"
 	"web/internal/domain/services"
 	"web/internal/utils/dictionary"
 )
@@ -28,8 +29,32 @@ func Register(router *httprouter.Router, service *services.Services, logFn dicti
 
 	router.POST(dictionary.Register, h.LogMiddleware(h.RegisterUser))
 	router.POST(dictionary.Login, h.LogMiddleware(h.GenerateToken))
-	router.GET(dictionary.UserURL, h.LogMiddleware(h.GetUserByID))
-	router.GET(dictionary.UsersURL, h.LogMiddleware(h.GetAllUsers))
-	router.PUT(dictionary.UserURL, h.LogMiddleware(h.UpdateUser))
-	router.DELETE(dictionary.UserURL, h.LogMiddleware(h.DeleteUser))
+	router.POST(dictionary.LoginTwoFactor, h.LogMiddleware(h.LoginTwoFactor))
+	router.POST(dictionary.RefreshToken, h.LogMiddleware(h.RefreshToken))
+	router.POST(dictionary.Logout, h.LogMiddleware(middleware.CheckToken(h.Logout, h.service.Auth)))
+	router.GET(dictionary.JWKS, h.LogMiddleware(h.GetJWKS))
+	router.GET(dictionary.UserURL, h.LogMiddleware(h.ownerOrAdmin(h.GetUserByID)))
+	router.GET(dictionary.UsersURL, h.LogMiddleware(middleware.CheckToken(middleware.CheckAdmin(h.GetAllUsers), h.service.Auth)))
+	router.PUT(dictionary.UserURL, h.LogMiddleware(h.ownerOrAdmin(h.UpdateUser)))
+	router.PATCH(dictionary.UserURL, h.LogMiddleware(h.ownerOrAdmin(h.PatchUser)))
+	router.DELETE(dictionary.UserURL, h.LogMiddleware(h.ownerOrAdmin(h.DeleteUser)))
+
+	router.POST(dictionary.TwoFactorEnroll, h.LogMiddleware(middleware.CheckToken(h.EnrollTwoFactor, h.service.Auth)))
+	router.POST(dictionary.TwoFactorConfirm, h.LogMiddleware(middleware.CheckToken(h.ConfirmTwoFactor, h.service.Auth)))
+	router.POST(dictionary.TwoFactorDisable, h.LogMiddleware(middleware.CheckToken(h.DisableTwoFactor, h.service.Auth)))
+
+	router.POST(dictionary.PasswordForgot, h.LogMiddleware(h.ForgotPassword))
+	router.POST(dictionary.PasswordReset, h.LogMiddleware(h.ResetPassword))
+	router.POST(dictionary.EmailVerify, h.LogMiddleware(h.VerifyEmail))
+	router.POST(dictionary.EmailVerifyResend, h.LogMiddleware(middleware.CheckToken(h.ResendVerification, h.service.Auth)))
+
+	// personal access tokens can be managed only with JWT
+	router.POST(dictionary.AccessTokensURL, h.LogMiddleware(middleware.CheckToken(h.CreateAccessToken, h.service.Auth)))
+	router.GET(dictionary.AccessTokensURL, h.LogMiddleware(middleware.CheckToken(h.GetAllAccessTokens, h.service.Auth)))
+	router.DELETE(dictionary.AccessTokenURL, h.LogMiddleware(middleware.CheckToken(h.DeleteAccessToken, h.service.Auth)))
+}
+
+// ownerOrAdmin allow user handler only for account owner or admin.
+func (h *Handler) ownerOrAdmin(next httprouter.Handle) httprouter.Handle {
+	return middleware.CheckToken(middleware.CheckOwnerOrAdmin(next), h.service.Auth)
 }
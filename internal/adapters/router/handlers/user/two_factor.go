@@ -0,0 +1,158 @@
+package user
+
+import (
+	e "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/adapters/router/validate"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// LoginTwoFactor second login step: exchange challenge and code for tokens.
+func (h *Handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	req := dto.TwoFactorLoginReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	// codes are guessed under the same guard as passwords, new challenges do not give new attempts
+	userName, err := h.service.Auth.ChallengeUsername(ctx, req.Challenge)
+	if e.Is(err, errors.ErrInvalidChallenge) {
+		functions.Abort(ctx, w, http.StatusUnauthorized, nil, err, "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	clientIP := h.service.LoginGuard.ClientIP(r.RemoteAddr, r.Header.Get("X-Real-IP"))
+
+	if !h.checkLoginGuard(w, r, userName, clientIP) {
+		return
+	}
+
+	tokens, err := h.service.Auth.VerifyTwoFactor(ctx, req.Challenge, req.Code)
+	if e.Is(err, errors.ErrInvalidTwoFactor) {
+		h.service.LoginGuard.Fail(userName, clientIP)
+	}
+
+	if e.Is(err, errors.ErrInvalidChallenge) || e.Is(err, errors.ErrInvalidTwoFactor) {
+		functions.Abort(ctx, w, http.StatusUnauthorized, nil, err, "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+		return
+	}
+
+	h.service.LoginGuard.Reset(userName)
+
+	tokens.AccessToken = fmt.Sprintf("Bearer %s", tokens.AccessToken)
+
+	functions.MakeJSONResponse(w, http.StatusOK, tokens)
+}
+
+// EnrollTwoFactor make TOTP secret and provisioning URI for authenticator app.
+func (h *Handler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	enroll, err := h.service.TwoFactor.Enroll(ctx, userID)
+	if e.Is(err, errors.ErrTwoFactorEnabled) {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, dictionary.User, userID)
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, enroll)
+}
+
+// ConfirmTwoFactor enable two-factor auth with the first code, recovery codes are returned.
+func (h *Handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	req := dto.TwoFactorCodeReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	codes, err := h.service.TwoFactor.Confirm(ctx, userID, req.Code)
+	if !h.twoFactorErrCheck(w, r, err) {
+		return
+	}
+
+	functions.MakeJSONResponse(w, http.StatusOK, codes)
+}
+
+// DisableTwoFactor turn two-factor auth off with TOTP or recovery code.
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	userID := r.Header.Get("user_id")
+	ctx := r.Context()
+
+	req := dto.TwoFactorCodeReq{}
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	if err := validate.InputJSONValidate(req); err != nil {
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+		return
+	}
+
+	err := h.service.TwoFactor.Disable(ctx, userID, req.Code)
+	if !h.twoFactorErrCheck(w, r, err) {
+		return
+	}
+
+	resp := make(map[string]string)
+	resp["Disabled two-factor auth for user with id"] = userID
+
+	functions.MakeJSONResponse(w, http.StatusOK, resp)
+}
+
+// twoFactorErrCheck make error response for confirm and disable. Returns true if there is no error.
+func (h *Handler) twoFactorErrCheck(w http.ResponseWriter, r *http.Request, err error) bool {
+	ctx := r.Context()
+
+	switch {
+	case err == nil:
+		return true
+	case e.Is(err, errors.ErrInvalidTwoFactor):
+		functions.Abort(ctx, w, http.StatusUnauthorized, nil, err, "", "")
+	case e.Is(err, errors.ErrTwoFactorEnabled), e.Is(err, errors.ErrTwoFactorNotEnabled):
+		functions.Abort(ctx, w, http.StatusBadRequest, nil, err, "", "")
+	default:
+		functions.Abort(ctx, w, http.StatusBadRequest, err, errors.ErrDBResponse, "", "")
+	}
+
+	return false
+}
@@ -0,0 +1,375 @@
+package user
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+
+	"web/internal/config"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/errors"
+	"web/internal/domain/services"
+	"web/internal/domain/services/mocks"
+	"web/internal/utils/dictionary"
+	l "web/pkg/logger"
+)
+
+func TestHandler_LoginTwoFactor(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockUserAuthService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"challenge": "challenge", "code": "123456"}`,
+			mockBehavior: func(s *mock_services.MockUserAuthService) {
+				// service response
+				s.EXPECT().ChallengeUsername(gomock.Any(), "challenge").Return("test_name", nil)
+				s.EXPECT().VerifyTwoFactor(gomock.Any(), "challenge", "123456").Return(&dto.TokensResp{
+					AccessToken:  "generatedToken",
+					RefreshToken: "refreshToken",
+					ExpiresIn:    900,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"token":"Bearer generatedToken","refresh_token":"refreshToken","expires_in":900}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON:          `{"challenge": "challenge"}`,
+			mockBehavior:       func(s *mock_services.MockUserAuthService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed","request_id":"test-request-id","errors":[{"field":"code","code":"required","message":"code is required"}]}
+`,
+			testName: "test-2-Handler:Validation Err",
+		},
+		{
+			inputJSON: `{"challenge": "challenge", "code": "000000"}`,
+			mockBehavior: func(s *mock_services.MockUserAuthService) {
+				// service response
+				s.EXPECT().ChallengeUsername(gomock.Any(), "challenge").Return("test_name", nil)
+				s.EXPECT().VerifyTwoFactor(gomock.Any(), "challenge", "000000").Return(nil, errors.ErrInvalidTwoFactor)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid two-factor code","code":"invalid_two_factor_code","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Invalid code",
+		},
+		{
+			inputJSON: `{"challenge": "expired", "code": "123456"}`,
+			mockBehavior: func(s *mock_services.MockUserAuthService) {
+				// service response
+				s.EXPECT().ChallengeUsername(gomock.Any(), "expired").Return("", errors.ErrInvalidChallenge)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid or expired two-factor challenge","code":"invalid_challenge","request_id":"test-request-id"}
+`,
+			testName: "test-4-Service:Invalid challenge",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			auth := mock_services.NewMockUserAuthService(c)
+			testCase.mockBehavior(auth)
+			// Init mock login guard, every attempt is allowed
+			guard := mock_services.NewMockLoginGuardService(c)
+			guard.EXPECT().ClientIP(gomock.Any(), gomock.Any()).Return("192.0.2.1").AnyTimes()
+			guard.EXPECT().Check(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+			guard.EXPECT().Fail(gomock.Any(), gomock.Any()).AnyTimes()
+			guard.EXPECT().Reset(gomock.Any()).AnyTimes()
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{Auth: auth, LoginGuard: guard}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.LoginTwoFactor, handler.LogMiddleware(handler.LoginTwoFactor))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_LoginTwoFactorGuard(t *testing.T) {
+	// Init mock controller
+	c := gomock.NewController(t)
+	defer c.Finish()
+	// Init mock service, every challenge gets a new challenge token
+	auth := mock_services.NewMockUserAuthService(c)
+	auth.EXPECT().ChallengeUsername(gomock.Any(), gomock.Any()).Return("test_name", nil).AnyTimes()
+	auth.EXPECT().VerifyTwoFactor(gomock.Any(), gomock.Any(), "000000").Return(nil, errors.ErrInvalidTwoFactor).Times(3)
+	// Init login guard, backoff starts after 3 failures
+	guard := services.NewLoginGuardService(config.App{
+		LoginBackoffAfter:    3,
+		LoginBackoffBase:     time.Minute,
+		LoginLockoutAfter:    10,
+		LoginLockoutDuration: 15 * time.Minute,
+		LoginIPBackoffAfter:  10,
+		LoginIPLockoutAfter:  50,
+	})
+	// Init testing logger with "fatal" level (5)
+	logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+	loggingMiddleware := l.NewLoggerMiddleware(logger)
+	// Init service
+	service := &services.Services{Auth: auth, LoginGuard: guard}
+	handler := NewHandler(service, loggingMiddleware)
+	// Test server
+	router := httprouter.New()
+	router.POST(dictionary.LoginTwoFactor, handler.LogMiddleware(handler.LoginTwoFactor))
+
+	expectedCodes := []int{
+		http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests,
+	}
+
+	for i, expectedCode := range expectedCodes {
+		// Test Request
+		w := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"challenge": "challenge%d", "code": "000000"}`, i)
+		req := httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBufferString(body))
+		req.Header.Set("X-Request-ID", testRequestID)
+		// Make Request
+		router.ServeHTTP(w, req)
+		// Assert
+		require.Equal(t, expectedCode, w.Code)
+	}
+}
+
+func TestHandler_EnrollTwoFactor(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTwoFactorService)
+
+	testTable := []struct {
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Enroll(gomock.Any(), "1").Return(&dto.TwoFactorEnrollResp{
+					Secret:          "JBSWY3DPEHPK3PXP",
+					ProvisioningURI: "otpauth://totp/note-service:test_name?secret=JBSWY3DPEHPK3PXP",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"secret":"JBSWY3DPEHPK3PXP",` +
+				`"provisioning_uri":"otpauth://totp/note-service:test_name?secret=JBSWY3DPEHPK3PXP"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Enroll(gomock.Any(), "1").Return(nil, errors.ErrTwoFactorEnabled)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"two-factor auth is already enabled","code":"two_factor_enabled","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Already enabled",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			twoFactorSrv := mock_services.NewMockTwoFactorService(c)
+			testCase.mockBehavior(twoFactorSrv)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{TwoFactor: twoFactorSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.TwoFactorEnroll, handler.LogMiddleware(handler.EnrollTwoFactor))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/2fa/enroll", nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ConfirmTwoFactor(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTwoFactorService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"code": "123456"}`,
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Confirm(gomock.Any(), "1", "123456").Return(&dto.RecoveryCodesResp{
+					RecoveryCodes: []string{"abcde-fghij"},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"recovery_codes":["abcde-fghij"]}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON: `{"code": "000000"}`,
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Confirm(gomock.Any(), "1", "000000").Return(nil, errors.ErrInvalidTwoFactor)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid two-factor code","code":"invalid_two_factor_code","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Invalid code",
+		},
+		{
+			inputJSON: `{"code": "123456"}`,
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Confirm(gomock.Any(), "1", "123456").Return(nil, errors.ErrTwoFactorNotEnabled)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse: `{"type":"about:blank","title":"Conflict","status":409,"detail":"two-factor auth is not enrolled","code":"two_factor_not_enrolled","request_id":"test-request-id"}
+`,
+			testName: "test-3-Service:Not enrolled",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			twoFactorSrv := mock_services.NewMockTwoFactorService(c)
+			testCase.mockBehavior(twoFactorSrv)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{TwoFactor: twoFactorSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.TwoFactorConfirm, handler.LogMiddleware(handler.ConfirmTwoFactor))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/2fa/confirm", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_DisableTwoFactor(t *testing.T) {
+	// Init mock func obj
+	type mockBehavior func(s *mock_services.MockTwoFactorService)
+
+	testTable := []struct {
+		inputJSON          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			inputJSON: `{"code": "abcde-fghij"}`,
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Disable(gomock.Any(), "1", "abcde-fghij").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"Disabled two-factor auth for user with id":"1"}
+`,
+			testName: "test-1-Handler:OK",
+		},
+		{
+			inputJSON: `{"code": "123456"}`,
+			mockBehavior: func(s *mock_services.MockTwoFactorService) {
+				// service response
+				s.EXPECT().Disable(gomock.Any(), "1", "123456").Return(e.New("some db Err"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db response error","code":"internal_error","request_id":"test-request-id"}
+`,
+			testName: "test-2-Service:Db resp Err",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init mock controller
+			c := gomock.NewController(t)
+			defer c.Finish()
+			// Init mock service
+			twoFactorSrv := mock_services.NewMockTwoFactorService(c)
+			testCase.mockBehavior(twoFactorSrv)
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Init service
+			service := &services.Services{TwoFactor: twoFactorSrv}
+			handler := NewHandler(service, loggingMiddleware)
+			// Test server
+			router := httprouter.New()
+			router.POST(dictionary.TwoFactorDisable, handler.LogMiddleware(handler.DisableTwoFactor))
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/2fa/disable", bytes.NewBufferString(testCase.inputJSON))
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", "1")
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
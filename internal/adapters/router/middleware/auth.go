@@ -14,7 +14,9 @@ import (
 )
 
 // CheckToken - handler middleware. Check bearer token for auth.
-func CheckToken(next httprouter.Handle, auth services.UserAuthService) httprouter.Handle {
+// Personal access tokens are accepted only if they have all scopes of the route,
+// routes without scopes are available only with JWT.
+func CheckToken(next httprouter.Handle, auth services.UserAuthService, scopes ...string) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		header := r.Header.Get("Authorization")
 		if header == "" {
@@ -38,14 +40,44 @@ func CheckToken(next httprouter.Handle, auth services.UserAuthService) httproute
 			return
 		}
 
-		userID, err := auth.ParseToken(headerParts[1])
+		claims, err := auth.ParseToken(r.Context(), headerParts[1])
 		if err != nil {
 			functions.Abort(r.Context(), w, http.StatusUnauthorized, nil, err, "", "")
 			return
 		}
 
-		r.Header.Set("user_id", userID)
+		if claims.Personal && !hasScopes(claims.Scopes, scopes) {
+			functions.Abort(r.Context(), w, http.StatusForbidden, nil, errors.ErrTokenScope, "", "")
+			return
+		}
+
+		r.Header.Set("user_id", claims.UserID)
+		r.Header.Set("token_id", claims.Id)
+		r.Header.Set("user_role", claims.Role)
 
 		next(w, r, ps)
 	}
 }
+
+// hasScopes check that token scopes contain all required ones.
+func hasScopes(tokenScopes, required []string) bool {
+	if len(required) == 0 {
+		return false
+	}
+
+	for _, scope := range required {
+		found := false
+		for _, tokenScope := range tokenScopes {
+			if tokenScope == scope {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
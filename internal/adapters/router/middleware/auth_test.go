@@ -10,13 +10,17 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/require"
 
-	"web/internal/adapters/router/handlers/user"
 	"web/internal/config"
+	e "web/internal/domain/errors"
 	"web/internal/domain/services"
 	"web/internal/domain/services/mocks"
+	"web/internal/utils/dictionary"
 	l "web/pkg/logger"
 )
 
+// testRequestID request id of test requests, error responses have it.
+const testRequestID = "test-request-id"
+
 func TestCheckToken(t *testing.T) {
 	// Init mock func obj
 	type mockBehavior func(s *mock_services.MockUserAuthService, token string)
@@ -36,7 +40,7 @@ func TestCheckToken(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_services.MockUserAuthService, token string) {
-				s.EXPECT().ParseToken(token).Return("1", nil)
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&dictionary.TokenClaims{UserID: "1"}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedUserID:     "1",
@@ -46,7 +50,7 @@ func TestCheckToken(t *testing.T) {
 			headerName:         "",
 			mockBehavior:       func(s *mock_services.MockUserAuthService, token string) {},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse: `{"error":"empty auth header"}
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"empty auth header","code":"empty_auth_header","request_id":"test-request-id"}
 `,
 			testName: "test-2-Empty auth header",
 		},
@@ -55,7 +59,7 @@ func TestCheckToken(t *testing.T) {
 			headerValue:        "Bearrrrrr token",
 			mockBehavior:       func(s *mock_services.MockUserAuthService, token string) {},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse: `{"error":"invalid auth header"}
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid auth header","code":"invalid_auth_header","request_id":"test-request-id"}
 `,
 			testName: "test-3-Invalid Bearer",
 		},
@@ -64,7 +68,7 @@ func TestCheckToken(t *testing.T) {
 			headerValue:        "Bearer ",
 			mockBehavior:       func(s *mock_services.MockUserAuthService, token string) {},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse: `{"error":"empty token"}
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"empty token","code":"empty_token","request_id":"test-request-id"}
 `,
 			testName: "test-4-Empty token",
 		},
@@ -73,7 +77,7 @@ func TestCheckToken(t *testing.T) {
 			headerValue:        "Bearer",
 			mockBehavior:       func(s *mock_services.MockUserAuthService, token string) {},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse: `{"error":"invalid auth header"}
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid auth header","code":"invalid_auth_header","request_id":"test-request-id"}
 `,
 			testName: "test-5-Invalid auth header",
 		},
@@ -82,13 +86,56 @@ func TestCheckToken(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_services.MockUserAuthService, token string) {
-				s.EXPECT().ParseToken(token).Return("1", errors.New("failed to parse token"))
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(nil, errors.New("failed to parse token"))
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse: `{"error":"failed to parse token"}
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"failed to parse token","code":"unauthorized","request_id":"test-request-id"}
 `,
 			testName: "test-6-Service Err",
 		},
+		{
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_services.MockUserAuthService, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(nil, e.ErrTokenRevoked)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"token is revoked","code":"token_revoked","request_id":"test-request-id"}
+`,
+			testName: "test-7-Revoked token",
+		},
+		{
+			headerName:  "Authorization",
+			headerValue: "Bearer nst_token",
+			token:       "nst_token",
+			mockBehavior: func(s *mock_services.MockUserAuthService, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&dictionary.TokenClaims{
+					UserID:   "1",
+					Personal: true,
+					Scopes:   []string{dictionary.ScopeNotesRead},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedUserID:     "1",
+			testName:           "test-8-Personal token with scope",
+		},
+		{
+			headerName:  "Authorization",
+			headerValue: "Bearer nst_token",
+			token:       "nst_token",
+			mockBehavior: func(s *mock_services.MockUserAuthService, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&dictionary.TokenClaims{
+					UserID:   "1",
+					Personal: true,
+					Scopes:   []string{dictionary.ScopeTagsWrite},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"insufficient token scope","code":"insufficient_scope","request_id":"test-request-id"}
+`,
+			testName: "test-9-Personal token without scope",
+		},
 	}
 	for _, testCase := range testTable {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -103,23 +150,24 @@ func TestCheckToken(t *testing.T) {
 			loggingMiddleware := l.NewLoggerMiddleware(logger)
 			// Init service
 			service := &services.Services{Auth: auth}
-			handler := user.NewHandler(service, loggingMiddleware)
 			// actual user_id from r header
 			var actualUserID string
 			// Test server
 			router := httprouter.New()
 			router.GET("/protected",
-				handler.LogMiddleware(
+				loggingMiddleware(
 					CheckToken(
 						func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 							actualUserID = r.Header.Get("user_id")
 						},
-						service.Auth),
+						service.Auth,
+						dictionary.ScopeNotesRead),
 				),
 			)
 			// Test Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			req.Header.Set("X-Request-ID", testRequestID)
 			req.Header.Set(testCase.headerName, testCase.headerValue)
 			// Make Request
 			router.ServeHTTP(w, req)
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// CheckAdmin - handler middleware. Allow request only for admins. Must be used after CheckToken.
+func CheckAdmin(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if r.Header.Get("user_role") != dictionary.RoleAdmin {
+			functions.Abort(r.Context(), w, http.StatusForbidden, nil, errors.ErrForbidden, "", "")
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
+
+// CheckOwnerOrAdmin - handler middleware. Allow request for admins and for user whose id is in ":id" param.
+// Must be used after CheckToken.
+func CheckOwnerOrAdmin(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if r.Header.Get("user_role") != dictionary.RoleAdmin && r.Header.Get("user_id") != ps.ByName("id") {
+			functions.Abort(r.Context(), w, http.StatusForbidden, nil, errors.ErrForbidden, "", "")
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+
+	"web/internal/config"
+	l "web/pkg/logger"
+)
+
+func TestCheckAdmin(t *testing.T) {
+	testTable := []struct {
+		role               string
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			role:               "admin",
+			expectedStatusCode: http.StatusOK,
+			testName:           "test-1-OK",
+		},
+		{
+			role:               "user",
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"access denied","code":"access_denied","request_id":"test-request-id"}
+`,
+			testName: "test-2-Not admin",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Test server
+			router := httprouter.New()
+			router.GET("/users",
+				loggingMiddleware(
+					CheckAdmin(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {}),
+				),
+			)
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_role", testCase.role)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
+
+func TestCheckOwnerOrAdmin(t *testing.T) {
+	testTable := []struct {
+		userID             string
+		role               string
+		paramID            string
+		expectedStatusCode int
+		expectedResponse   string
+		testName           string
+	}{
+		{
+			userID:             "1",
+			role:               "user",
+			paramID:            "1",
+			expectedStatusCode: http.StatusOK,
+			testName:           "test-1-Owner",
+		},
+		{
+			userID:             "1",
+			role:               "admin",
+			paramID:            "2",
+			expectedStatusCode: http.StatusOK,
+			testName:           "test-2-Admin",
+		},
+		{
+			userID:             "1",
+			role:               "user",
+			paramID:            "2",
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"access denied","code":"access_denied","request_id":"test-request-id"}
+`,
+			testName: "test-3-Other user",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Init testing logger with "fatal" level (5)
+			logger := l.NewLogger(&config.Config{Logger: config.Logger{LogLevel: 5}})
+			loggingMiddleware := l.NewLoggerMiddleware(logger)
+			// Test server
+			router := httprouter.New()
+			router.GET("/users/:id",
+				loggingMiddleware(
+					CheckOwnerOrAdmin(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {}),
+				),
+			)
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/"+testCase.paramID, nil)
+			req.Header.Set("X-Request-ID", testRequestID)
+			req.Header.Set("user_id", testCase.userID)
+			req.Header.Set("user_role", testCase.role)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// StaticRoutes - handler middleware. Serve requests to static paths by their own handlers, others by next.
+// httprouter does not allow a static segment where another route of the method has a parameter,
+// e.g. /notes/search and /notes/:id, so the static route is registered through the parameter one.
+func StaticRoutes(next httprouter.Handle, routes map[string]httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if handle, ok := routes[r.URL.Path]; ok {
+			handle(w, r, nil)
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
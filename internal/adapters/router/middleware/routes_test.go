@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticRoutes(t *testing.T) {
+	testTable := []struct {
+		path             string
+		expectedResponse string
+		testName         string
+	}{
+		{
+			path:             "/notes/search",
+			expectedResponse: "search",
+			testName:         "test-1-Static route",
+		},
+		{
+			path:             "/notes/1",
+			expectedResponse: "note 1",
+			testName:         "test-2-Parameter route",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Test server
+			router := httprouter.New()
+			router.GET("/notes/:id",
+				StaticRoutes(
+					func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+						w.Write([]byte("note " + ps.ByName("id"))) //nolint:errcheck
+					},
+					map[string]httprouter.Handle{
+						"/notes/search": func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+							w.Write([]byte("search")) //nolint:errcheck
+						},
+					},
+				),
+			)
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			// Make Request
+			router.ServeHTTP(w, req)
+			// Assert
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, testCase.expectedResponse, w.Body.String())
+		})
+	}
+}
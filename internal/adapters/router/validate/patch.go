@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	e "errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+
+	"web/internal/domain/errors"
+	"web/pkg/jsonpatch"
+)
+
+// media types of patch request bodies.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// DecodePatch patch dst with JSON Merge Patch or JSON Patch body by contentType.
+// Patch is applied to JSON of dst, patched document is decoded to zeroed dst, so removed fields are zero values.
+// Unknown fields are rejected like in DecodeJSON, patched dst must be validated by caller.
+func DecodePatch(contentType string, body io.Reader, dst interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var apply func(doc, patch []byte) ([]byte, error)
+
+	switch mediaType {
+	case MergePatchType:
+		apply = jsonpatch.MergePatch
+	case JSONPatchType:
+		apply = jsonpatch.Apply
+	default:
+		return errors.ErrPatchMediaType
+	}
+
+	patch, err := io.ReadAll(body)
+	if err != nil {
+		return errors.ErrMalformedJSON.Wrap(err)
+	}
+
+	doc, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(doc, patch)
+	if err != nil {
+		return patchError(err)
+	}
+
+	value := reflect.ValueOf(dst).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	return DecodeJSON(bytes.NewReader(patched), dst)
+}
+
+// patchError typed error of patch which can not be applied, failed test operation is a conflict.
+func patchError(err error) error {
+	patchErr := errors.ErrInvalidPatch.Wrap(err)
+	if e.Is(err, jsonpatch.ErrTestFailed) {
+		patchErr = errors.ErrPatchTestFailed.Wrap(err)
+	}
+
+	patchErr.Message = fmt.Sprintf("%s: %s", patchErr.Message, err.Error())
+
+	return patchErr
+}
@@ -2,18 +2,187 @@
 package validate
 
 import (
+	"encoding/json"
+	e "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
 	"github.com/go-playground/validator/v10"
 
+	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
 )
 
-// InputJSONValidate input JSON validation.
-func InputJSONValidate(inputJSON interface{}) error {
-	err := dictionary.Validate.Struct(inputJSON)
-	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			return err
+// usernameRegexp username format: starts with letter, then letters, digits, '.', '_' or '-'.
+var usernameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9._-]*$`)
+
+// unknownFieldPrefix prefix of json decoder error about unknown field.
+const unknownFieldPrefix = "json: unknown field "
+
+// unknownFieldCode code of field error about unknown field.
+const unknownFieldCode = "unknown_field"
+
+// structValidator validator of request DTOs, field errors have JSON names of fields.
+var structValidator = newValidator()
+
+// newValidator validator builder with JSON field names and custom username, password and tag path rules.
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	v.RegisterValidation("username", func(fl validator.FieldLevel) bool { //nolint:errcheck,gosec
+		return usernameRegexp.MatchString(fl.Field().String())
+	})
+
+	v.RegisterValidation("password", func(fl validator.FieldLevel) bool { //nolint:errcheck,gosec
+		return strongPassword(fl.Field().String())
+	})
+
+	v.RegisterValidation("tagpath", func(fl validator.FieldLevel) bool { //nolint:errcheck,gosec
+		return tagPath(fl.Field().String())
+	})
+
+	return v
+}
+
+// strongPassword password has letters in both cases and digits.
+func strongPassword(password string) bool {
+	var hasUpper, hasLower, hasDigit bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	return hasUpper && hasLower && hasDigit
+}
+
+// tagPath tag path has no empty tag names and no spaces around them, e.g. "work/projects/alpha".
+func tagPath(path string) bool {
+	for _, name := range strings.Split(path, dictionary.TagPathSeparator) {
+		if name == "" || name != strings.TrimSpace(name) {
+			return false
 		}
 	}
-	return nil
+
+	return true
+}
+
+// DecodeJSON decode request JSON body to dst, unknown fields are rejected.
+// Malformed body is errors.ErrMalformedJSON, unknown field is errors.ErrValidation with field error.
+func DecodeJSON(body io.Reader, dst interface{}) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+	if err == nil {
+		return nil
+	}
+
+	if strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		field := strings.Trim(strings.TrimPrefix(err.Error(), unknownFieldPrefix), `"`)
+
+		return errors.ErrValidation.Wrap(err).WithFields(errors.FieldError{
+			Field:   field,
+			Code:    unknownFieldCode,
+			Message: fmt.Sprintf("%s is not a known field", field),
+		})
+	}
+
+	malformedErr := errors.ErrMalformedJSON.Wrap(err)
+	malformedErr.Message = fmt.Sprintf("%s: %s", malformedErr.Message, err.Error())
+
+	return malformedErr
+}
+
+// InputJSONValidate input JSON validation, invalid input is errors.ErrValidation with errors of all invalid fields.
+// Struct fields named in except are not validated.
+func InputJSONValidate(inputJSON interface{}, except ...string) error {
+	err := structValidator.StructExcept(inputJSON, except...)
+
+	var validationErrs validator.ValidationErrors
+	if !e.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]errors.FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, errors.FieldError{
+			Field:   fieldPath(fieldErr),
+			Code:    fieldErr.Tag(),
+			Message: fieldMessage(fieldErr),
+		})
+	}
+
+	return errors.ErrValidation.Wrap(err).WithFields(fields...)
+}
+
+// fieldPath JSON path of field without root struct name, e.g. scopes[0].
+func fieldPath(fieldErr validator.FieldError) string {
+	path := fieldErr.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+
+	return path
+}
+
+// fieldMessage human-readable message of field error.
+func fieldMessage(fieldErr validator.FieldError) string {
+	field, param := fieldPath(fieldErr), fieldErr.Param()
+
+	unit := "characters"
+	if kind := fieldErr.Kind(); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+		unit = "items"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "required_without_all":
+		return fmt.Sprintf("%s is required when none of %s is set", field,
+			strings.ReplaceAll(strings.ToLower(param), " ", ", "))
+	case "excluded_with":
+		return fmt.Sprintf("%s can not be set together with %s", field, strings.ToLower(param))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s %s", field, param, unit)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s %s", field, param, unit)
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s %s", field, param, unit)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(param, " ", ", "))
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "numeric":
+		return fmt.Sprintf("%s must contain only digits", field)
+	case "username":
+		return fmt.Sprintf("%s must start with a letter and contain only letters, digits, '.', '_' and '-'", field)
+	case "password":
+		return fmt.Sprintf("%s must contain upper and lower case letters and digits", field)
+	case "tagpath":
+		return fmt.Sprintf("%s must be tag names separated by '/' without empty names and spaces around them", field)
+	}
+
+	return fmt.Sprintf("%s failed on the '%s' rule", field, fieldErr.Tag())
 }
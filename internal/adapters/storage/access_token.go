@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+)
+
+// accessTokenStorage personal access token storage struct.
+type accessTokenStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewAccessTokenStorage personal access token storage func builder.
+func NewAccessTokenStorage(db *sqlx.DB, queryTimeout time.Duration) AccessTokenStorage {
+	return &accessTokenStorage{db: db, timeout: queryTimeout}
+}
+
+// CreateAccessToken insert personal access token in DB.
+func (a *accessTokenStorage) CreateAccessToken(
+	ctx context.Context,
+	token *model.AccessToken,
+) (*model.AccessToken, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, token_hash, scopes, expires_at, created_at)"+
+		" VALUES ($1, $2, $3, $4, $5, $6) RETURNING id", dictionary.AccessTokensTable)
+
+	err := a.db.QueryRowContext(ctx, query,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.Scopes,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return token, nil
+}
+
+// GetAccessTokenByHash get personal access token by hash from DB.
+func (a *accessTokenStorage) GetAccessTokenByHash(ctx context.Context, tokenHash string) (*model.AccessToken, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	var token model.AccessToken
+
+	query := fmt.Sprintf("SELECT id, user_id, name, token_hash, scopes, expires_at, created_at"+
+		" FROM %s WHERE token_hash=$1", dictionary.AccessTokensTable)
+	if err := a.db.GetContext(ctx, &token, query, tokenHash); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &token, nil
+}
+
+// GetAllAccessTokens get all personal access tokens by user from DB.
+func (a *accessTokenStorage) GetAllAccessTokens(ctx context.Context, userID string) ([]model.AccessToken, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	var tokens []model.AccessToken
+
+	query := fmt.Sprintf("SELECT id, user_id, name, token_hash, scopes, expires_at, created_at"+
+		" FROM %s WHERE user_id=$1 ORDER BY id", dictionary.AccessTokensTable)
+	if err := a.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return tokens, nil
+}
+
+// DeleteAccessToken delete (revoke) personal access token by id from DB.
+func (a *accessTokenStorage) DeleteAccessToken(ctx context.Context, tokenID, userID string) (int, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	var id int
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2 RETURNING id", dictionary.AccessTokensTable)
+	if err := a.db.QueryRowContext(ctx, query, tokenID, userID).Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/pkg/database"
+)
+
+func TestAccessTokenStorage_CreateAccessToken(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(time.Hour)
+
+	testTable := []struct {
+		token    *model.AccessToken
+		expected *model.AccessToken
+		testName string
+	}{
+		{
+			token: &model.AccessToken{
+				UserID:    "1",
+				Name:      "ci",
+				TokenHash: "hash",
+				Scopes:    "notes:read,tags:write",
+				ExpiresAt: &expiresAt,
+				CreatedAt: now,
+			},
+			expected: &model.AccessToken{
+				ID:        "1",
+				UserID:    "1",
+				Name:      "ci",
+				TokenHash: "hash",
+				Scopes:    "notes:read,tags:write",
+				ExpiresAt: &expiresAt,
+				CreatedAt: now,
+			},
+			testName: "Test-1-OK",
+		},
+		{
+			token: &model.AccessToken{
+				UserID:    "1",
+				Name:      "no expiry",
+				TokenHash: "hash",
+				Scopes:    "notes:read",
+				CreatedAt: now,
+			},
+			expected: &model.AccessToken{
+				ID:        "1",
+				UserID:    "1",
+				Name:      "no expiry",
+				TokenHash: "hash",
+				Scopes:    "notes:read",
+				CreatedAt: now,
+			},
+			testName: "Test-2-Without expiry",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAccessTokenStorage(db.Client, 0)
+
+			_, err := storage.CreateAccessToken(context.Background(), testCase.token)
+			require.NoError(t, err)
+
+			actual, err := storage.GetAccessTokenByHash(context.Background(), testCase.token.TokenHash)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestAccessTokenStorage_GetAllAccessTokens(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		tokens   []*model.AccessToken
+		userID   string
+		expected []string
+		testName string
+	}{
+		{
+			tokens: []*model.AccessToken{
+				{UserID: "1", Name: "first", TokenHash: "hash1", Scopes: "notes:read", CreatedAt: now},
+				{UserID: "1", Name: "second", TokenHash: "hash2", Scopes: "tags:write", CreatedAt: now},
+			},
+			userID:   "1",
+			expected: []string{"first", "second"},
+			testName: "Test-1-OK",
+		},
+		{
+			tokens: []*model.AccessToken{
+				{UserID: "1", Name: "first", TokenHash: "hash1", Scopes: "notes:read", CreatedAt: now},
+			},
+			userID:   "2",
+			expected: nil,
+			testName: "Test-2-Other user",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAccessTokenStorage(db.Client, 0)
+
+			for _, token := range testCase.tokens {
+				if _, err := storage.CreateAccessToken(context.Background(), token); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			tokens, err := storage.GetAllAccessTokens(context.Background(), testCase.userID)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, token := range tokens {
+				actual = append(actual, token.Name)
+			}
+
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestAccessTokenStorage_DeleteAccessToken(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		userID   string
+		expected error
+		testName string
+	}{
+		{
+			userID:   "1",
+			expected: nil,
+			testName: "Test-1-OK",
+		},
+		{
+			userID:   "2",
+			expected: sql.ErrNoRows,
+			testName: "Test-2-Other user",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAccessTokenStorage(db.Client, 0)
+
+			token := &model.AccessToken{UserID: "1", Name: "ci", TokenHash: "hash", Scopes: "notes:read", CreatedAt: now}
+			if _, err := storage.CreateAccessToken(context.Background(), token); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			_, err := storage.DeleteAccessToken(context.Background(), token.ID, testCase.userID)
+			require.ErrorIs(t, err, testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+)
+
+// accountStorage password reset and email verification storage struct.
+type accountStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewAccountStorage account storage func builder.
+func NewAccountStorage(db *sqlx.DB, queryTimeout time.Duration) AccountStorage {
+	return &accountStorage{db: db, timeout: queryTimeout}
+}
+
+// GetUserByEmail get user with email state by email from DB.
+func (a *accountStorage) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	var user model.User
+
+	query := fmt.Sprintf("SELECT id, username, email, email_verified FROM %s WHERE email=$1", dictionary.UsersTable)
+	if err := a.db.GetContext(ctx, &user, query, email); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &user, nil
+}
+
+// GetUserEmail get user with email state by id from DB. Email is empty if user has not set it.
+func (a *accountStorage) GetUserEmail(ctx context.Context, userID string) (*model.User, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	var user model.User
+
+	query := fmt.Sprintf("SELECT id, username, COALESCE(email, '') AS email, email_verified FROM %s WHERE id=$1",
+		dictionary.UsersTable)
+	if err := a.db.GetContext(ctx, &user, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &user, nil
+}
+
+// CreateUserToken insert single-use token and mail with it to outbox in one transaction.
+func (a *accountStorage) CreateUserToken(ctx context.Context, token *model.UserToken, mail *model.Mail) error {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := insertUserToken(ctx, tx, token, mail); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// ResetPassword use password reset token and replace user password hash in one transaction.
+// All other reset tokens of user are used too. Returns user id or errors.ErrNotFound if token is invalid.
+func (a *accountStorage) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	userID, err := useUserToken(ctx, tx, tokenHash, dictionary.PurposeResetPassword)
+	if err != nil {
+		return "", dbError(err)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", dictionary.UsersTable)
+	if _, err := tx.ExecContext(ctx, query, passwordHash, userID); err != nil {
+		return "", dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", dbError(err)
+	}
+
+	return userID, nil
+}
+
+// VerifyEmail use email verification token and mark user email as verified in one transaction.
+// Returns user id or errors.ErrNotFound if token is invalid.
+func (a *accountStorage) VerifyEmail(ctx context.Context, tokenHash string) (string, error) {
+	ctx, cancel := queryContext(ctx, a.timeout)
+	defer cancel()
+
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	userID, err := useUserToken(ctx, tx, tokenHash, dictionary.PurposeVerifyEmail)
+	if err != nil {
+		return "", dbError(err)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET email_verified=true WHERE id=$1", dictionary.UsersTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return "", dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", dbError(err)
+	}
+
+	return userID, nil
+}
+
+// insertUserToken insert single-use token and mail with it to outbox with tx.
+func insertUserToken(ctx context.Context, tx *sqlx.Tx, token *model.UserToken, mail *model.Mail) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, purpose, expires_at)"+
+		" VALUES ($1, $2, $3, $4) RETURNING id", dictionary.UserTokensTable)
+
+	err := tx.QueryRowContext(ctx, query, token.UserID, token.TokenHash, token.Purpose, token.ExpiresAt).Scan(&token.ID)
+	if err != nil {
+		return err
+	}
+
+	return insertMail(ctx, tx, mail)
+}
+
+// useUserToken mark alive token and all other unused tokens of the same purpose as used.
+// Returns token owner id or errors.ErrNotFound if token is unknown, used or expired.
+func useUserToken(ctx context.Context, tx *sqlx.Tx, tokenHash, purpose string) (string, error) {
+	var userID string
+
+	now := time.Now().UTC()
+
+	query := fmt.Sprintf("UPDATE %s SET used_at=$1"+
+		" WHERE token_hash=$2 AND purpose=$3 AND used_at IS NULL AND expires_at > $1 RETURNING user_id",
+		dictionary.UserTokensTable)
+	if err := tx.QueryRowContext(ctx, query, now, tokenHash, purpose).Scan(&userID); err != nil {
+		return "", err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET used_at=$1 WHERE user_id=$2 AND purpose=$3 AND used_at IS NULL",
+		dictionary.UserTokensTable)
+	if _, err := tx.ExecContext(ctx, query, now, userID, purpose); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
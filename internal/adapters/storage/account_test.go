@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+	"web/pkg/database"
+)
+
+func TestAccountStorage_ResetPassword(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		purpose        string
+		ttl            time.Duration
+		useTwice       bool
+		expectedUserID string
+		expectedErr    error
+		testName       string
+	}{
+		{
+			purpose:        dictionary.PurposeResetPassword,
+			ttl:            time.Hour,
+			expectedUserID: "1",
+			testName:       "Test-1-OK",
+		},
+		{
+			purpose:     dictionary.PurposeResetPassword,
+			ttl:         -time.Minute,
+			expectedErr: sql.ErrNoRows,
+			testName:    "Test-2-Expired token",
+		},
+		{
+			purpose:     dictionary.PurposeResetPassword,
+			ttl:         time.Hour,
+			useTwice:    true,
+			expectedErr: sql.ErrNoRows,
+			testName:    "Test-3-Used token",
+		},
+		{
+			purpose:     dictionary.PurposeVerifyEmail,
+			ttl:         time.Hour,
+			expectedErr: sql.ErrNoRows,
+			testName:    "Test-4-Wrong purpose",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "old_hash"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAccountStorage(db.Client, 0)
+
+			err := storage.CreateUserToken(context.Background(), &model.UserToken{
+				UserID:    "1",
+				TokenHash: "token_hash",
+				Purpose:   testCase.purpose,
+				ExpiresAt: time.Now().UTC().Add(testCase.ttl),
+			}, &model.Mail{Recipient: "test@example.com", Subject: "subject", Body: "body"})
+			require.NoError(t, err)
+
+			if testCase.useTwice {
+				_, err := storage.ResetPassword(context.Background(), "token_hash", "first_hash")
+				require.NoError(t, err)
+			}
+
+			userID, err := storage.ResetPassword(context.Background(), "token_hash", "new_hash")
+			require.ErrorIs(t, err, testCase.expectedErr)
+			require.Equal(t, testCase.expectedUserID, userID)
+
+			if testCase.expectedErr == nil {
+				var password string
+				query := fmt.Sprintf("SELECT password FROM %s WHERE id=1", dictionary.UsersTable)
+				require.NoError(t, db.Client.Get(&password, query))
+				require.Equal(t, "new_hash", password)
+			}
+		})
+	}
+}
+
+func TestAccountStorage_VerifyEmail(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		tokenHash   string
+		expected    *model.User
+		expectedErr error
+		testName    string
+	}{
+		{
+			tokenHash: "token_hash",
+			expected: &model.User{
+				ID:            "1",
+				Username:      "test_name",
+				Email:         "test@example.com",
+				EmailVerified: true,
+			},
+			testName: "Test-1-OK",
+		},
+		{
+			tokenHash: "unknown_hash",
+			expected: &model.User{
+				ID:       "1",
+				Username: "test_name",
+				Email:    "test@example.com",
+			},
+			expectedErr: sql.ErrNoRows,
+			testName:    "Test-2-Unknown token",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			user := &model.User{Username: "test_name", Password: "test_password", Email: "test@example.com"}
+			verification := &model.UserToken{
+				TokenHash: "token_hash",
+				Purpose:   dictionary.PurposeVerifyEmail,
+				ExpiresAt: time.Now().UTC().Add(time.Hour),
+			}
+			mail := &model.Mail{Recipient: user.Email, Subject: "subject", Body: "body"}
+
+			if _, err := NewAuthStorage(db.Client, 0).RegisterUser(context.Background(), user, verification, mail); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAccountStorage(db.Client, 0)
+
+			_, err := storage.VerifyEmail(context.Background(), testCase.tokenHash)
+			require.ErrorIs(t, err, testCase.expectedErr)
+
+			actual, err := storage.GetUserByEmail(context.Background(), "test@example.com")
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -11,30 +14,102 @@ import (
 
 // userAuthStorage auth storage struct.
 type userAuthStorage struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	timeout time.Duration
 }
 
 // NewAuthStorage auth storage func builder.
-func NewAuthStorage(db *sqlx.DB) UserAuthStorage {
-	return &userAuthStorage{db: db}
+func NewAuthStorage(db *sqlx.DB, queryTimeout time.Duration) UserAuthStorage {
+	return &userAuthStorage{db: db, timeout: queryTimeout}
 }
 
-// RegisterUser insert user in DB.
-func (s *userAuthStorage) RegisterUser(user *model.User) (*model.User, error) {
-	query := fmt.Sprintf("INSERT INTO %s (username, password) VALUES ($1, $2) RETURNING id", dictionary.UsersTable)
-	if err := s.db.QueryRow(query, user.Username, user.Password).Scan(&user.ID); err != nil {
-		return nil, err
+// RegisterUser insert user in DB. If user has email, verification token and mail to outbox
+// are inserted in the same transaction, so the mail is never lost or sent for a missing user.
+func (s *userAuthStorage) RegisterUser(
+	ctx context.Context,
+	user *model.User,
+	verification *model.UserToken,
+	mail *model.Mail,
+) (*model.User, error) {
+	ctx, cancel := queryContext(ctx, s.timeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	user.CreatedAt = timestamp()
+	user.UpdatedAt = user.CreatedAt
+
+	query := fmt.Sprintf("INSERT INTO %s (username, password, email, created_at, updated_at)"+
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id", dictionary.UsersTable)
+
+	err = tx.QueryRowContext(ctx, query,
+		user.Username, user.Password, nullString(user.Email), user.CreatedAt, user.UpdatedAt,
+	).Scan(&user.ID)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	if verification != nil {
+		verification.UserID = user.ID
+		if err := insertUserToken(ctx, tx, verification, mail); err != nil {
+			return nil, dbError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err)
 	}
 
 	return user, nil
 }
 
-// GetUserForToken get user from DB for token gen func.
-func (s *userAuthStorage) GetUserForToken(userName, passwordHash string) (*model.User, error) {
+// GetUserByUsername get user with password hash from DB for token gen func.
+func (s *userAuthStorage) GetUserByUsername(ctx context.Context, userName string) (*model.User, error) {
+	ctx, cancel := queryContext(ctx, s.timeout)
+	defer cancel()
+
 	var user model.User
 
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND password=$2", dictionary.UsersTable)
-	err := s.db.Get(&user, query, userName, passwordHash)
+	query := fmt.Sprintf("SELECT id, username, password, role, totp_enabled FROM %s WHERE username=$1",
+		dictionary.UsersTable)
+	if err := s.db.GetContext(ctx, &user, query, userName); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &user, nil
+}
+
+// GetUserRole get user role from DB.
+func (s *userAuthStorage) GetUserRole(ctx context.Context, userID string) (string, error) {
+	ctx, cancel := queryContext(ctx, s.timeout)
+	defer cancel()
+
+	var role string
+
+	query := fmt.Sprintf("SELECT role FROM %s WHERE id=$1", dictionary.UsersTable)
+	if err := s.db.GetContext(ctx, &role, query, userID); err != nil {
+		return "", dbError(err)
+	}
+
+	return role, nil
+}
+
+// UpdatePasswordHash replace user password hash in DB.
+func (s *userAuthStorage) UpdatePasswordHash(ctx context.Context, userID, passwordHash string) error {
+	ctx, cancel := queryContext(ctx, s.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", dictionary.UsersTable)
+	_, err := s.db.ExecContext(ctx, query, passwordHash, userID)
+
+	return dbError(err)
+}
 
-	return &user, err
+// nullString store empty optional string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
@@ -1,12 +1,15 @@
 package storage
 
 import (
+	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
 	"web/pkg/database"
 )
 
@@ -16,6 +19,8 @@ func TestUserAuthStorage_RegisterUser(t *testing.T) {
 
 	testTable := []struct {
 		user, expected *model.User
+		verification   *model.UserToken
+		mail           *model.Mail
 		err            error
 		testName       string
 	}{
@@ -43,6 +48,30 @@ func TestUserAuthStorage_RegisterUser(t *testing.T) {
 			},
 			testName: "Test-2-OK",
 		},
+		{
+			user: &model.User{
+				Username: "test_name2",
+				Password: "test_password2",
+				Email:    "test@example.com",
+			},
+			verification: &model.UserToken{
+				TokenHash: "verification_hash",
+				Purpose:   dictionary.PurposeVerifyEmail,
+				ExpiresAt: time.Now().UTC().Add(time.Hour),
+			},
+			mail: &model.Mail{
+				Recipient: "test@example.com",
+				Subject:   "subject",
+				Body:      "body",
+			},
+			expected: &model.User{
+				ID:       "1",
+				Username: "test_name2",
+				Password: "test_password2",
+				Email:    "test@example.com",
+			},
+			testName: "Test-3-With email",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -50,20 +79,29 @@ func TestUserAuthStorage_RegisterUser(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
 
-			storage := NewAuthStorage(db.Client)
+			storage := NewAuthStorage(db.Client, 0)
 
-			actual, err := storage.RegisterUser(testCase.user)
+			actual, err := storage.RegisterUser(context.Background(), testCase.user, testCase.verification, testCase.mail)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
 
+			require.WithinDuration(t, time.Now(), actual.CreatedAt, time.Minute)
+			require.Equal(t, actual.CreatedAt, actual.UpdatedAt)
+			testCase.expected.CreatedAt, testCase.expected.UpdatedAt = actual.CreatedAt, actual.UpdatedAt
+
 			require.Equal(t, testCase.expected, actual)
 			require.NoError(t, testCase.err, err)
+
+			if testCase.mail != nil {
+				require.Equal(t, "1", testCase.verification.UserID)
+				require.Equal(t, "1", testCase.mail.ID)
+			}
 		})
 	}
 }
 
-func TestUserAuthStorage_GetUserForToken(t *testing.T) {
+func TestUserAuthStorage_GetUserByUsername(t *testing.T) {
 	db := database.NewTestDBClient()
 	defer db.Close()
 
@@ -79,7 +117,10 @@ func TestUserAuthStorage_GetUserForToken(t *testing.T) {
 				Password: "test_password_hash",
 			},
 			expected: &model.User{
-				ID: "1",
+				ID:       "1",
+				Username: "test_name",
+				Password: "test_password_hash",
+				Role:     "user",
 			},
 			testName: "Test-1-OK",
 		},
@@ -89,7 +130,10 @@ func TestUserAuthStorage_GetUserForToken(t *testing.T) {
 				Password: "test_password_hash1",
 			},
 			expected: &model.User{
-				ID: "1",
+				ID:       "1",
+				Username: "test_name1",
+				Password: "test_password_hash1",
+				Role:     "user",
 			},
 			testName: "Test-2-OK",
 		},
@@ -104,9 +148,9 @@ func TestUserAuthStorage_GetUserForToken(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewAuthStorage(db.Client)
+			storage := NewAuthStorage(db.Client, 0)
 
-			actual, err := storage.GetUserForToken(testCase.user.Username, testCase.user.Password)
+			actual, err := storage.GetUserByUsername(context.Background(), testCase.user.Username)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -116,3 +160,54 @@ func TestUserAuthStorage_GetUserForToken(t *testing.T) {
 		})
 	}
 }
+
+func TestUserAuthStorage_UpdatePasswordHash(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		user     *model.User
+		newHash  string
+		expected *model.User
+		err      error
+		testName string
+	}{
+		{
+			user: &model.User{
+				Username: "test_name",
+				Password: "test_password_hash",
+			},
+			newHash: "$argon2id$v=19$m=65536,t=1,p=4$salt$hash",
+			expected: &model.User{
+				ID:       "1",
+				Username: "test_name",
+				Password: "$argon2id$v=19$m=65536,t=1,p=4$salt$hash",
+				Role:     "user",
+			},
+			testName: "Test-1-OK",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(testCase.user); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewAuthStorage(db.Client, 0)
+
+			err := storage.UpdatePasswordHash(context.Background(), testCase.user.ID, testCase.newHash)
+			require.NoError(t, testCase.err, err)
+
+			actual, err := storage.GetUserByUsername(context.Background(), testCase.user.Username)
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"database/sql"
+	e "errors"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+
+	"web/internal/domain/errors"
+)
+
+// dbError convert DB driver error to typed domain error: missing row is errors.ErrNotFound, unique violation is
+// errors.ErrConflict, foreign key violation is errors.ErrReference, other violations are errors.ErrInvalidValue.
+// Other errors, typed ones included, are returned as is.
+func dbError(err error) error {
+	var (
+		pqErr     *pq.Error
+		sqliteErr sqlite3.Error
+	)
+
+	switch {
+	case err == nil, errors.As(err) != nil:
+		return err
+	case e.Is(err, sql.ErrNoRows):
+		return errors.ErrNotFound.Wrap(err)
+	case e.As(err, &pqErr):
+		return pqError(pqErr)
+	case e.As(err, &sqliteErr):
+		return sqliteError(sqliteErr)
+	}
+
+	return err
+}
+
+// pqError typed error of postgres error by its code.
+func pqError(err *pq.Error) error {
+	switch err.Code.Name() {
+	case "unique_violation":
+		return errors.ErrConflict.Wrap(err)
+	case "foreign_key_violation":
+		return errors.ErrReference.Wrap(err)
+	case "not_null_violation", "check_violation", "string_data_right_truncation", "invalid_text_representation":
+		return errors.ErrInvalidValue.Wrap(err)
+	}
+
+	return err
+}
+
+// sqliteError typed error of sqlite error by its extended code.
+func sqliteError(err sqlite3.Error) error {
+	switch err.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return errors.ErrConflict.Wrap(err)
+	case sqlite3.ErrConstraintForeignKey:
+		return errors.ErrReference.Wrap(err)
+	case sqlite3.ErrConstraintNotNull, sqlite3.ErrConstraintCheck:
+		return errors.ErrInvalidValue.Wrap(err)
+	}
+
+	return err
+}
+
+// versionError error of change with optimistic lock which changed nothing, err is error of affected rows count.
+func versionError(err error) error {
+	if err != nil {
+		return dbError(err)
+	}
+
+	return errors.ErrVersionMismatch
+}
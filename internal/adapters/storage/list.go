@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"web/internal/domain/entities/dto"
+	"web/internal/utils/dictionary"
+)
+
+// likeEscaper escape LIKE wildcards, queries use backslash as ESCAPE character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// timestamp current time for created_at and updated_at columns, rounded to DB precision.
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// listConditions append list query filters to where conditions, placeholders continue args numbering.
+func listConditions(listQuery *dto.ListQuery, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if listQuery == nil {
+		return conditions, args
+	}
+
+	if listQuery.CreatedAfter != nil {
+		args = append(args, listQuery.CreatedAfter.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at > $%d", len(args)))
+	}
+
+	if listQuery.UpdatedSince != nil {
+		args = append(args, listQuery.UpdatedSince.UTC())
+		conditions = append(conditions, fmt.Sprintf("updated_at >= $%d", len(args)))
+	}
+
+	return conditions, args
+}
+
+// pageConditions append list query filters and title prefix of paginated list, total count of list uses them.
+func pageConditions(listQuery *dto.ListQuery, titleColumn string, conditions []string,
+	args []interface{},
+) ([]string, []interface{}) {
+	conditions, args = listConditions(listQuery, conditions, args)
+
+	if listQuery != nil && listQuery.TitlePrefix != "" {
+		args = append(args, likeEscaper.Replace(listQuery.TitlePrefix)+"%")
+		conditions = append(conditions, fmt.Sprintf(`%s LIKE $%d ESCAPE '\'`, titleColumn, len(args)))
+	}
+
+	return conditions, args
+}
+
+// cursorCondition append keyset condition to start page after the cursor, id breaks ties of equal sort values.
+func cursorCondition(listQuery *dto.ListQuery, titleColumn string, conditions []string,
+	args []interface{},
+) ([]string, []interface{}) {
+	if listQuery == nil || listQuery.Cursor == nil {
+		return conditions, args
+	}
+
+	cursor := listQuery.Cursor
+
+	operator := ">"
+	if listQuery.Desc {
+		operator = "<"
+	}
+
+	switch cursor.Sort {
+	case dictionary.SortTitle:
+		args = append(args, *cursor.Title, cursor.ID)
+	case dictionary.SortCreatedAt, dictionary.SortUpdatedAt:
+		args = append(args, cursor.Time.UTC(), cursor.ID)
+	default:
+		args = append(args, cursor.ID)
+		return append(conditions, fmt.Sprintf("id %s $%d", operator, len(args))), args
+	}
+
+	return append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)",
+		sortColumn(listQuery, titleColumn), operator, len(args)-1, len(args))), args
+}
+
+// orderClause ORDER BY and LIMIT clauses of list query, items are ordered by id without sort.
+func orderClause(listQuery *dto.ListQuery, titleColumn string) string {
+	if listQuery == nil {
+		return " ORDER BY id"
+	}
+
+	direction := ""
+	if listQuery.Desc {
+		direction = " DESC"
+	}
+
+	clause := fmt.Sprintf(" ORDER BY id%s", direction)
+	if column := sortColumn(listQuery, titleColumn); column != "id" {
+		clause = fmt.Sprintf(" ORDER BY %s%s, id%s", column, direction, direction)
+	}
+
+	if listQuery.Limit > 0 {
+		clause += fmt.Sprintf(" LIMIT %d", listQuery.Limit)
+	}
+
+	return clause
+}
+
+// sortColumn column of list query sort, title sort is by title column of the table.
+func sortColumn(listQuery *dto.ListQuery, titleColumn string) string {
+	switch listQuery.Sort {
+	case dictionary.SortTitle:
+		return titleColumn
+	case dictionary.SortCreatedAt, dictionary.SortUpdatedAt:
+		return listQuery.Sort
+	default:
+		return "id"
+	}
+}
+
+// whereClause join conditions to WHERE clause, empty if there are no conditions.
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// versionCondition condition of optimistic lock on version with placeholder argID, it is empty for version 0.
+func versionCondition(version, argID int) string {
+	if version == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" AND version=$%d", argID)
+}
+
+// versionArgs args of query with versionCondition, version is appended if it is not 0.
+func versionArgs(version int, args ...interface{}) []interface{} {
+	if version == 0 {
+		return args
+	}
+
+	return append(args, version)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+)
+
+// mailStorage mail outbox storage struct.
+type mailStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewMailStorage mail outbox storage func builder.
+func NewMailStorage(db *sqlx.DB, queryTimeout time.Duration) MailStorage {
+	return &mailStorage{db: db, timeout: queryTimeout}
+}
+
+// ClaimPendingMails claim oldest not sent mails, which have not reached the attempts limit, until claimedUntil.
+// Claimed mails are skipped by other claims until then, so concurrent dispatchers do not send the same mail.
+func (m *mailStorage) ClaimPendingMails(
+	ctx context.Context,
+	limit, maxAttempts int,
+	claimedUntil time.Time,
+) ([]model.Mail, error) {
+	ctx, cancel := queryContext(ctx, m.timeout)
+	defer cancel()
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var mails []model.Mail
+
+	// SQLite has no row locks, its write transactions are serialized
+	lock := " FOR UPDATE SKIP LOCKED"
+	if m.db.DriverName() == "sqlite3" {
+		lock = ""
+	}
+
+	query := fmt.Sprintf("SELECT id, recipient, subject, body, created_at, sent_at, attempts, last_error FROM %s"+
+		" WHERE sent_at IS NULL AND attempts < $1 AND (claimed_until IS NULL OR claimed_until < $2)"+
+		" ORDER BY id LIMIT $3%s", dictionary.MailOutboxTable, lock)
+	if err := tx.SelectContext(ctx, &mails, query, maxAttempts, time.Now().UTC(), limit); err != nil {
+		return nil, dbError(err)
+	}
+
+	if len(mails) == 0 {
+		return mails, nil
+	}
+
+	placeholders := make([]string, 0, len(mails))
+	args := []interface{}{claimedUntil}
+
+	for _, mail := range mails {
+		args = append(args, mail.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET claimed_until=$1 WHERE id IN (%s)",
+		dictionary.MailOutboxTable, strings.Join(placeholders, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return mails, nil
+}
+
+// MarkMailSent set mail delivery time.
+func (m *mailStorage) MarkMailSent(ctx context.Context, mailID string) error {
+	ctx, cancel := queryContext(ctx, m.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET sent_at=$1, attempts=attempts+1 WHERE id=$2", dictionary.MailOutboxTable)
+	_, err := m.db.ExecContext(ctx, query, time.Now().UTC(), mailID)
+
+	return dbError(err)
+}
+
+// MarkMailFailed count failed delivery attempt with its error, mail is released for the next claim.
+func (m *mailStorage) MarkMailFailed(ctx context.Context, mailID, lastError string) error {
+	ctx, cancel := queryContext(ctx, m.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET attempts=attempts+1, last_error=$1, claimed_until=NULL WHERE id=$2",
+		dictionary.MailOutboxTable)
+	_, err := m.db.ExecContext(ctx, query, lastError, mailID)
+
+	return dbError(err)
+}
+
+// insertMail insert mail to outbox with tx.
+func insertMail(ctx context.Context, tx *sqlx.Tx, mail *model.Mail) error {
+	mail.CreatedAt = time.Now().UTC()
+
+	query := fmt.Sprintf("INSERT INTO %s (recipient, subject, body, created_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		dictionary.MailOutboxTable)
+
+	return tx.QueryRowContext(ctx, query, mail.Recipient, mail.Subject, mail.Body, mail.CreatedAt).Scan(&mail.ID)
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/pkg/database"
+)
+
+func TestMailStorage_ClaimPendingMails(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		sent, failed []string
+		maxAttempts  int
+		expectedIDs  []string
+		testName     string
+	}{
+		{
+			maxAttempts: 5,
+			expectedIDs: []string{"1", "2", "3"},
+			testName:    "Test-1-All pending",
+		},
+		{
+			sent:        []string{"2"},
+			failed:      []string{"3"},
+			maxAttempts: 5,
+			expectedIDs: []string{"1", "3"},
+			testName:    "Test-2-Sent skipped",
+		},
+		{
+			failed:      []string{"1"},
+			maxAttempts: 1,
+			expectedIDs: []string{"2", "3"},
+			testName:    "Test-3-Attempts limit",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			tx := db.Client.MustBegin()
+			for i := 0; i < 3; i++ {
+				mail := &model.Mail{Recipient: "test@example.com", Subject: "subject", Body: "body"}
+				if err := insertMail(context.Background(), tx, mail); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewMailStorage(db.Client, 0)
+
+			for _, id := range testCase.sent {
+				require.NoError(t, storage.MarkMailSent(context.Background(), id))
+			}
+
+			for _, id := range testCase.failed {
+				require.NoError(t, storage.MarkMailFailed(context.Background(), id, "connection refused"))
+			}
+
+			mails, err := storage.ClaimPendingMails(context.Background(), 10, testCase.maxAttempts,
+				time.Now().UTC().Add(time.Minute))
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(mails))
+			for _, mail := range mails {
+				actualIDs = append(actualIDs, mail.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func TestMailStorage_ClaimPendingMailsClaimed(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	tx := db.Client.MustBegin()
+	for i := 0; i < 3; i++ {
+		mail := &model.Mail{Recipient: "test@example.com", Subject: "subject", Body: "body"}
+		if err := insertMail(context.Background(), tx, mail); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewMailStorage(db.Client, 0)
+	ctx := context.Background()
+
+	// the first dispatcher claims two mails, the second one gets only the rest
+	mails, err := storage.ClaimPendingMails(ctx, 2, 5, time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, mails, 2)
+
+	mails, err = storage.ClaimPendingMails(ctx, 10, 5, time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, mails, 1)
+	require.Equal(t, "3", mails[0].ID)
+
+	// failed mail is released, expired claim is taken again
+	require.NoError(t, storage.MarkMailFailed(ctx, "1", "connection refused"))
+	db.Client.MustExec("UPDATE mail_outbox SET claimed_until=$1 WHERE id=2", time.Now().UTC().Add(-time.Minute))
+
+	mails, err = storage.ClaimPendingMails(ctx, 10, 5, time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+
+	actualIDs := make([]string, 0, len(mails))
+	for _, mail := range mails {
+		actualIDs = append(actualIDs, mail.ID)
+	}
+
+	require.Equal(t, []string{"1", "2"}, actualIDs)
+}
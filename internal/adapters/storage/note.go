@@ -1,63 +1,231 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
+	e "errors"
 	"fmt"
-	"log"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
+	"web/pkg/tagquery"
 )
 
+// notTrashed condition of notes which are not in trash.
+const notTrashed = "deleted_at IS NULL"
+
+// escapedInfo note info with HTML special characters escaped, so search snippets carry only highlight markup.
+// NULL info is empty.
+const escapedInfo = "replace(replace(replace(replace(replace(coalesce(info, ''), '&', '&amp;'), '<', '&lt;')," +
+	` '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+
 // noteStorage note storage struct.
 type noteStorage struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	timeout time.Duration
 }
 
 // NewNoteStorage note storage func builder.
-func NewNoteStorage(db *sqlx.DB) NoteStorage {
-	return &noteStorage{db: db}
+func NewNoteStorage(db *sqlx.DB, queryTimeout time.Duration) NoteStorage {
+	return &noteStorage{db: db, timeout: queryTimeout}
 }
 
 // CreateNote create note in DB.
-func (n *noteStorage) CreateNote(note *model.Note, userID string) (*model.Note, error) {
-	query := fmt.Sprintf("INSERT INTO %s (title, info, user_id) VALUES ($1, $2, $3) RETURNING id", dictionary.NotesTable)
-	if err := n.db.QueryRow(query, note.Title, note.Info, userID).Scan(&note.ID); err != nil {
-		return nil, err
+func (n *noteStorage) CreateNote(ctx context.Context, note *model.Note, userID string) (*model.Note, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	note.CreatedAt = timestamp()
+	note.UpdatedAt = note.CreatedAt
+
+	query := fmt.Sprintf("INSERT INTO %s (title, info, user_id, created_at, updated_at)"+
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id", dictionary.NotesTable)
+	row := n.db.QueryRowContext(ctx, query, note.Title, note.Info, userID, note.CreatedAt, note.UpdatedAt)
+	if err := row.Scan(&note.ID); err != nil {
+		return nil, dbError(err)
 	}
 
 	return note, nil
 }
 
 // GetNoteByID get note by id from DB.
-func (n *noteStorage) GetNoteByID(id string, userID string) (*dto.NoteResp, error) {
+func (n *noteStorage) GetNoteByID(ctx context.Context, id string, userID string) (*dto.NoteResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
 	var note dto.NoteResp
 
-	query := fmt.Sprintf("SELECT title, info FROM %s WHERE id=$1 AND user_id=$2", dictionary.NotesTable)
-	if err := n.db.Get(&note, query, id, userID); err != nil {
-		return nil, err
+	query := fmt.Sprintf("SELECT title, info, notebook_id, created_at, updated_at, version FROM %s"+
+		" WHERE id=$1 AND user_id=$2 AND %s", dictionary.NotesTable, notTrashed)
+	if err := n.db.GetContext(ctx, &note, query, id, userID); err != nil {
+		return nil, dbError(err)
 	}
 
 	return &note, nil
 }
 
-// GetAllNotesByUser get all notes by user from DB.
-func (n *noteStorage) GetAllNotesByUser(userID string) ([]dto.NotesResp, error) {
+// GetAllNotesByUser get all notes by user from DB by list query filters, sort, cursor and limit.
+func (n *noteStorage) GetAllNotesByUser(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+) ([]dto.NotesResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
 	var notes []dto.NotesResp
 
-	query := fmt.Sprintf("SELECT id, title, info FROM %s WHERE user_id=$1", dictionary.NotesTable)
-	if err := n.db.Select(&notes, query, userID); err != nil {
-		return nil, err
+	conditions, args := pageConditions(listQuery, "title", []string{"user_id=$1", notTrashed},
+		[]interface{}{userID})
+	conditions, args = tagsExprCondition(listTagsExpr(listQuery), listTagDescendants(listQuery), conditions, args)
+	conditions, args = cursorCondition(listQuery, "title", conditions, args)
+
+	query := fmt.Sprintf("SELECT id, title, info, created_at, updated_at FROM %s%s%s",
+		dictionary.NotesTable, whereClause(conditions), orderClause(listQuery, "title"))
+	if err := n.db.SelectContext(ctx, &notes, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	return notes, nil
+}
+
+// CountNotesByUser count notes by user in DB by list query filters, cursor and limit are not applied.
+func (n *noteStorage) CountNotesByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var total int
+
+	conditions, args := pageConditions(listQuery, "title", []string{"user_id=$1", notTrashed},
+		[]interface{}{userID})
+	conditions, args = tagsExprCondition(listTagsExpr(listQuery), listTagDescendants(listQuery), conditions, args)
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", dictionary.NotesTable, whereClause(conditions))
+	if err := n.db.GetContext(ctx, &total, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+
+	return total, nil
+}
+
+// SearchNotes full-text search of user notes ranked by relevance, found notes have all tags of search query.
+// SQLite has no text search, there notes are matched by LIKE and ones with matched title rank first.
+func (n *noteStorage) SearchNotes(
+	ctx context.Context,
+	userID string,
+	searchQuery *dto.NoteSearchQuery,
+) ([]dto.NoteSearchResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var notes []dto.NoteSearchResp
+
+	query, args := n.searchQuery(userID, searchQuery)
+	if err := n.db.SelectContext(ctx, &notes, query, args...); err != nil {
+		return nil, dbError(err)
 	}
 
 	return notes, nil
 }
 
-// UpdateNote update note by id in DB.
-func (n *noteStorage) UpdateNote(newNote *dto.NoteUpdate, noteID string) error {
+// searchQuery notes search query of DB driver, text query is $1 and user id is $2.
+func (n *noteStorage) searchQuery(userID string, searchQuery *dto.NoteSearchQuery) (string, []interface{}) {
+	if n.db.DriverName() == "sqlite3" {
+		conditions, args := tagsExprCondition(searchTagsExpr(searchQuery.Tags), searchQuery.TagDescendants,
+			[]string{`(title LIKE $1 ESCAPE '\' OR info LIKE $1 ESCAPE '\')`, "user_id=$2", notTrashed},
+			[]interface{}{"%" + likeEscaper.Replace(searchQuery.Query) + "%", userID})
+
+		return fmt.Sprintf("SELECT id, title, %s AS snippet,"+
+			` CASE WHEN title LIKE $1 ESCAPE '\' THEN 1.0 ELSE 0.0 END AS rank, created_at, updated_at`+
+			" FROM %s%s ORDER BY rank DESC, id LIMIT %d",
+			escapedInfo, dictionary.NotesTable, whereClause(conditions), searchQuery.Limit), args
+	}
+
+	conditions, args := tagsExprCondition(searchTagsExpr(searchQuery.Tags), searchQuery.TagDescendants,
+		[]string{"search @@ search_query", "user_id=$2", notTrashed}, []interface{}{searchQuery.Query, userID})
+
+	return fmt.Sprintf("SELECT id, title,"+
+		" ts_headline('%[2]s', %[5]s, search_query, 'MaxFragments=2, StartSel=<b>, StopSel=</b>') AS snippet,"+
+		" ts_rank(search, search_query) AS rank, created_at, updated_at"+
+		" FROM %[1]s, websearch_to_tsquery('%[2]s', $1) search_query%[3]s"+
+		" ORDER BY rank DESC, id LIMIT %[4]d",
+		dictionary.NotesTable, dictionary.SearchConfig, whereClause(conditions), searchQuery.Limit, escapedInfo), args
+}
+
+// searchTagsExpr expression of notes which have all tags of search query, nil without tags.
+func searchTagsExpr(tags []string) tagquery.Expr {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	operands := make([]tagquery.Expr, 0, len(tags))
+	for _, tag := range tags {
+		operands = append(operands, tagquery.Tag{Name: tag})
+	}
+
+	return tagquery.And{Operands: operands}
+}
+
+// listTagsExpr tag expression of list query, nil without list query.
+func listTagsExpr(listQuery *dto.ListQuery) tagquery.Expr {
+	if listQuery == nil {
+		return nil
+	}
+
+	return listQuery.Tags
+}
+
+// listTagDescendants tags of list query tag expression match their child tags, false without list query.
+func listTagDescendants(listQuery *dto.ListQuery) bool {
+	return listQuery != nil && listQuery.TagDescendants
+}
+
+// UpdateNote update note by id in DB, previous title and info of note are kept as its new revision by the user
+// if the update changes them.
+// Note version is incremented, if version is not 0 and note has another one errors.ErrVersionMismatch is returned.
+// Only maxRevisions latest revisions of note are kept, 0 keeps all of them. New version of note is returned.
+func (n *noteStorage) UpdateNote(
+	ctx context.Context,
+	newNote *dto.NoteUpdate,
+	noteID, userID string,
+	version, maxRevisions int,
+) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var current dto.NoteResp
+
+	query := fmt.Sprintf("SELECT title, info FROM %s WHERE id=$1 AND user_id=$2 AND %s",
+		dictionary.NotesTable, notTrashed)
+	if err := tx.GetContext(ctx, &current, query, noteID, userID); err != nil {
+		return 0, dbError(err)
+	}
+
+	now := timestamp()
+	changed := (newNote.Title != nil && *newNote.Title != current.Title) ||
+		(newNote.Info != nil && *newNote.Info != current.Info)
+
+	rev := 0
+	if changed {
+		if rev, err = insertRevision(ctx, tx, noteID, userID, now); err != nil {
+			return 0, dbError(err)
+		}
+	}
+
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argID := 1
@@ -74,126 +242,460 @@ func (n *noteStorage) UpdateNote(newNote *dto.NoteUpdate, noteID string) error {
 		argID++
 	}
 
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argID), "version=version+1")
+	args = append(args, now)
+	argID++
+
 	setQuery := strings.Join(setValues, ", ")
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", dictionary.NotesTable, setQuery, argID)
-	args = append(args, noteID)
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d%s RETURNING version", dictionary.NotesTable, setQuery,
+		argID, versionCondition(version, argID+1))
+	args = versionArgs(version, append(args, noteID)...)
+
+	var newVersion int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&newVersion); err != nil {
+		if version != 0 && e.Is(err, sql.ErrNoRows) {
+			return 0, versionError(nil)
+		}
+
+		return 0, dbError(err)
+	}
+
+	if changed && maxRevisions > 0 {
+		query := fmt.Sprintf("DELETE FROM %s WHERE note_id=$1 AND rev <= $2", dictionary.RevisionsTable)
+		if _, err := tx.ExecContext(ctx, query, noteID, rev-maxRevisions); err != nil {
+			return 0, dbError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(err)
+	}
+
+	return newVersion, nil
+}
+
+// insertRevision copy current title and info of user note to its next revision authored by the user,
+// number of revision is returned. Trashed notes have no revisions, for them sql.ErrNoRows is returned.
+func insertRevision(ctx context.Context, tx *sqlx.Tx, noteID, userID string, createdAt time.Time) (int, error) {
+	var rev int
+
+	query := fmt.Sprintf("INSERT INTO %[1]s (note_id, rev, title, info, author_id, created_at)"+
+		" SELECT id, (SELECT COALESCE(MAX(rev), 0) + 1 FROM %[1]s WHERE note_id=$1), title, info, $2, $3"+
+		" FROM %[2]s WHERE id=$1 AND user_id=$2 AND %[3]s RETURNING rev",
+		dictionary.RevisionsTable, dictionary.NotesTable, notTrashed)
+	if err := tx.QueryRowContext(ctx, query, noteID, userID, createdAt).Scan(&rev); err != nil {
+		return 0, err
+	}
+
+	return rev, nil
+}
+
+// GetNoteRevisions get revisions of user note from DB without their info, the latest revisions are first.
+func (n *noteStorage) GetNoteRevisions(ctx context.Context, noteID, userID string) ([]dto.NoteRevisionsResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var revisions []dto.NoteRevisionsResp
+
+	query := fmt.Sprintf("SELECT r.rev, r.title, r.author_id, r.created_at FROM %s r JOIN %s n ON n.id = r.note_id"+
+		" WHERE r.note_id=$1 AND n.user_id=$2 AND n.%s ORDER BY r.rev DESC",
+		dictionary.RevisionsTable, dictionary.NotesTable, notTrashed)
+	if err := n.db.SelectContext(ctx, &revisions, query, noteID, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return revisions, nil
+}
+
+// GetNoteRevision get revision of user note by its number from DB.
+func (n *noteStorage) GetNoteRevision(ctx context.Context, noteID, rev, userID string) (*dto.NoteRevisionResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var revision dto.NoteRevisionResp
 
-	_, err := n.db.Exec(query, args...)
+	query := fmt.Sprintf("SELECT r.rev, r.title, r.info, r.author_id, r.created_at FROM %s r"+
+		" JOIN %s n ON n.id = r.note_id WHERE r.note_id=$1 AND r.rev=$2 AND n.user_id=$3 AND n.%s",
+		dictionary.RevisionsTable, dictionary.NotesTable, notTrashed)
+	if err := n.db.GetContext(ctx, &revision, query, noteID, rev, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &revision, nil
+}
+
+// DeleteNote move note by id to trash, trashed notes are hidden from all note queries except trash ones.
+// If version is not 0 and note has another one errors.ErrVersionMismatch is returned.
+func (n *noteStorage) DeleteNote(ctx context.Context, noteID, userID string, version int) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var id int
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=$1 WHERE id=$2 AND user_id=$3 AND %s%s RETURNING id",
+		dictionary.NotesTable, notTrashed, versionCondition(version, 4))
+	row := n.db.QueryRowContext(ctx, query, versionArgs(version, timestamp(), noteID, userID)...)
+	if err := row.Scan(&id); err != nil {
+		if version != 0 && e.Is(err, sql.ErrNoRows) {
+			return 0, versionError(nil)
+		}
+
+		return 0, dbError(err)
+	}
+
+	return id, nil
+}
+
+// GetTrash get trashed notes of user from DB, recently trashed notes are first.
+func (n *noteStorage) GetTrash(ctx context.Context, userID string) ([]dto.TrashNoteResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var notes []dto.TrashNoteResp
 
-	return err
+	query := fmt.Sprintf("SELECT id, title, info, created_at, updated_at, deleted_at FROM %s"+
+		" WHERE user_id=$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id", dictionary.NotesTable)
+	if err := n.db.SelectContext(ctx, &notes, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return notes, nil
 }
 
-// DeleteNote delete note by id from DB.
-func (n *noteStorage) DeleteNote(noteID, userID string) (int, error) {
+// RestoreNote move note by id from trash back to notes, it is updated now.
+// If user has another note with the same title errors.ErrNoteTitleTaken is returned.
+func (n *noteStorage) RestoreNote(ctx context.Context, noteID, userID string) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
 	var id int
 
-	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2 RETURNING id", dictionary.NotesTable)
-	if err := n.db.QueryRow(query, noteID, userID).Scan(&id); err != nil {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=NULL, updated_at=$1, version=version+1"+
+		" WHERE id=$2 AND user_id=$3 AND deleted_at IS NOT NULL RETURNING id", dictionary.NotesTable)
+	if err := n.db.QueryRowContext(ctx, query, timestamp(), noteID, userID).Scan(&id); err != nil {
+		if err = dbError(err); e.Is(err, errors.ErrConflict) {
+			return 0, errors.ErrNoteTitleTaken.Wrap(err)
+		}
+
 		return 0, err
 	}
 
 	return id, nil
 }
 
-// SetTags - set tags to note by ID.
-func (n *noteStorage) SetTags(noteID string, tags map[string]string) (string, error) {
-	for tagID, tagName := range tags {
-		query := fmt.Sprintf("INSERT INTO %s (note_id, tag_id) VALUES ($1, $2)", dictionary.NotesTagsTable)
-		if res := n.db.QueryRow(query, noteID, tagID); res.Err() != nil {
-			return tagName, res.Err()
+// PurgeNote delete trashed note by id from DB permanently.
+func (n *noteStorage) PurgeNote(ctx context.Context, noteID, userID string) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var id int
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2 AND deleted_at IS NOT NULL RETURNING id",
+		dictionary.NotesTable)
+	if err := n.db.QueryRowContext(ctx, query, noteID, userID).Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+
+	return id, nil
+}
+
+// PurgeTrash delete notes trashed before the time from DB permanently, count of deleted notes is returned.
+func (n *noteStorage) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", dictionary.NotesTable)
+
+	res, err := n.db.ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	purged, err := res.RowsAffected()
+
+	return int(purged), dbError(err)
+}
+
+// UpdateNoteTags change tags of note in one transaction, links which already are in requested state are kept.
+// If some tag is not a tag of the user nothing is changed and its id is returned with errors.ErrNotFound.
+func (n *noteStorage) UpdateNoteTags(
+	ctx context.Context,
+	noteID, userID string,
+	update *dto.NoteTagsUpdate,
+) (*dto.NoteTagsResp, string, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	requested := append(append([]int64{}, update.Add...), update.Remove...)
+	if update.Set != nil {
+		requested = append(requested, *update.Set...)
+	}
+
+	requested = uniqueIDs(requested)
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, "", dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if tagID, err := checkUserTags(ctx, tx, userID, requested); err != nil {
+		return nil, tagID, dbError(err)
+	}
+
+	resp := &dto.NoteTagsResp{Removed: make([]int64, 0)}
+	toAdd := update.Add
+
+	switch {
+	case update.Set != nil:
+		toAdd = *update.Set
+		resp.Removed, err = deleteNoteTags(ctx, tx, noteID, "NOT IN", uniqueIDs(*update.Set))
+	case len(update.Remove) > 0:
+		resp.Removed, err = deleteNoteTags(ctx, tx, noteID, "IN", uniqueIDs(update.Remove))
+	}
+
+	if err != nil {
+		return nil, "", dbError(err)
+	}
+
+	if resp.Added, err = insertNoteTags(ctx, tx, noteID, uniqueIDs(toAdd)); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	changed := make(map[int64]bool, len(resp.Added)+len(resp.Removed))
+	for _, id := range append(append([]int64{}, resp.Added...), resp.Removed...) {
+		changed[id] = true
+	}
+
+	resp.Unchanged = make([]int64, 0)
+
+	for _, id := range requested {
+		if !changed[id] {
+			resp.Unchanged = append(resp.Unchanged, id)
 		}
 	}
 
-	return "", nil
+	resp.Added, resp.Removed = uniqueIDs(resp.Added), uniqueIDs(resp.Removed)
+
+	return resp, "", nil
 }
 
-// RemoveTags - remove tags from note by ID.
-func (n *noteStorage) RemoveTags(noteID string, tags map[string]string) (string, error) {
-	for tagID, tagName := range tags {
-		query := fmt.Sprintf("DELETE FROM %s WHERE note_id=$1 AND tag_id=$2", dictionary.NotesTagsTable)
-		if res := n.db.QueryRow(query, noteID, tagID); res.Err() != nil {
-			return tagName, res.Err()
+// checkUserTags check all tags are tags of user, id of the first missing tag is returned with sql.ErrNoRows.
+func checkUserTags(ctx context.Context, tx *sqlx.Tx, userID string, tagIDs []int64) (string, error) {
+	if len(tagIDs) == 0 {
+		return "", nil
+	}
+
+	placeholders, args := idsPlaceholders([]interface{}{userID}, tagIDs)
+
+	var found []int64
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id=$1 AND id IN (%s)", dictionary.TagsTable, placeholders)
+	if err := tx.SelectContext(ctx, &found, query, args...); err != nil {
+		return "", err
+	}
+
+	owned := make(map[int64]bool, len(found))
+	for _, id := range found {
+		owned[id] = true
+	}
+
+	for _, id := range tagIDs {
+		if !owned[id] {
+			return strconv.FormatInt(id, 10), sql.ErrNoRows
 		}
 	}
 
 	return "", nil
 }
 
-// GetAllNotesWithTags get all notes with tags by user.
-func (n *noteStorage) GetAllNotesWithTags(userID string, notes []dto.NotesResp) ([]dto.NoteWithTagsResp, error) {
-	tempNotes := make([]dto.NoteWithTagsResp, len(notes))
-	resultNotes := make([]dto.NoteWithTagsResp, 0)
+// insertNoteTags link tags to note, existing links are skipped, ids of linked tags are returned.
+func insertNoteTags(ctx context.Context, tx *sqlx.Tx, noteID string, tagIDs []int64) ([]int64, error) {
+	added := make([]int64, 0)
+	if len(tagIDs) == 0 {
+		return added, nil
+	}
 
-	for index, note := range notes {
-		query := fmt.Sprintf("SELECT notes.id, notes.title, notes.info, tags.id, tags.tagname"+
-			" FROM %s JOIN notes_tags"+
-			" ON notes.id = notes_tags.note_id"+
-			" JOIN tags"+
-			" ON notes_tags.tag_id = tags.id"+
-			" AND tags.user_id = $1 AND notes.id = $2", dictionary.NotesTable)
+	values := make([]string, 0, len(tagIDs))
+	args := []interface{}{noteID, timestamp()}
 
-		row, err := n.db.Query(query, userID, note.ID)
-		if err != nil {
-			return nil, err
-		}
+	for _, id := range tagIDs {
+		args = append(args, id)
+		values = append(values, fmt.Sprintf("($1, $2, $%d)", len(args)))
+	}
 
-		for row.Next() {
-			var note dto.NoteWithTags
+	query := fmt.Sprintf("INSERT INTO %s (note_id, created_at, tag_id) VALUES %s ON CONFLICT DO NOTHING"+
+		" RETURNING tag_id", dictionary.NotesTagsTable, strings.Join(values, ", "))
+	if err := tx.SelectContext(ctx, &added, query, args...); err != nil {
+		return nil, err
+	}
 
-			if err := row.Scan(&note.ID, &note.Title, &note.Info, &note.TagsResp.ID, &note.TagsResp.TagName); err != nil {
-				log.Println(err)
-			}
+	return added, nil
+}
 
-			if note.ID != "" {
-				tempNotes[index].ID = note.ID
-				tempNotes[index].Title = note.Title
-				tempNotes[index].Info = note.Info
-				tempNotes[index].TagsResp = append(tempNotes[index].TagsResp, note.TagsResp)
-			}
-		}
+// deleteNoteTags unlink tags with ids IN or NOT IN tagIDs from note, ids of unlinked tags are returned.
+// NOT IN empty tagIDs unlinks all tags of note.
+func deleteNoteTags(ctx context.Context, tx *sqlx.Tx, noteID, operator string, tagIDs []int64) ([]int64, error) {
+	removed := make([]int64, 0)
+
+	where := "note_id=$1"
+	placeholders, args := idsPlaceholders([]interface{}{noteID}, tagIDs)
+
+	if len(tagIDs) > 0 {
+		where += fmt.Sprintf(" AND tag_id %s (%s)", operator, placeholders)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s RETURNING tag_id", dictionary.NotesTagsTable, where)
+	if err := tx.SelectContext(ctx, &removed, query, args...); err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// idsPlaceholders append ids to args, placeholders of ids continue args numbering.
+func idsPlaceholders(args []interface{}, ids []int64) (string, []interface{}) {
+	placeholders := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
 	}
 
-	for _, note := range tempNotes {
-		if note.ID != "" {
-			resultNotes = append(resultNotes, note)
+	return strings.Join(placeholders, ", "), args
+}
+
+// uniqueIDs sorted copy of ids without duplicates.
+func uniqueIDs(ids []int64) []int64 {
+	sorted := append([]int64{}, ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	unique := make([]int64, 0, len(sorted))
+
+	for i, id := range sorted {
+		if i == 0 || id != sorted[i-1] {
+			unique = append(unique, id)
 		}
 	}
 
-	return resultNotes, nil
+	return unique
+}
+
+// GetAllNotesWithTags stream notes of user with their tags to fn in one query, notes are ordered by id.
+// Notes without tags are passed with empty tags only if includeUntagged is set.
+// Query has no timeout deadline, its rows are read while fn writes streamed response.
+func (n *noteStorage) GetAllNotesWithTags(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+	includeUntagged bool,
+	fn func(note *dto.NoteWithTagsResp) error,
+) error {
+	conditions, args := listConditions(listQuery, []string{"user_id=$1", notTrashed}, []interface{}{userID})
+	conditions, args = tagsExprCondition(listTagsExpr(listQuery), listTagDescendants(listQuery), conditions, args)
+
+	join := "JOIN"
+	if includeUntagged {
+		join = "LEFT JOIN"
+	}
+
+	rows, err := n.db.QueryContext(ctx, notesWithTagsQuery(whereClause(conditions), join), args...)
+	if err != nil {
+		return dbError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	return dbError(scanNotesWithTags(rows, fn))
 }
 
 // GetNoteWithAllTags get note by id with all tags by user.
-func (n *noteStorage) GetNoteWithAllTags(userID, noteID string, note *dto.NoteResp) (dto.NoteWithTagsResp, error) {
+func (n *noteStorage) GetNoteWithAllTags(ctx context.Context, userID, noteID string) (dto.NoteWithTagsResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
 	var resultNote dto.NoteWithTagsResp
 
-	query := fmt.Sprintf("SELECT notes.id, notes.title, notes.info, tags.id, tags.tagname"+
-		" FROM %s JOIN notes_tags"+
-		" ON notes.id = notes_tags.note_id"+
-		" JOIN tags"+
-		" ON notes_tags.tag_id = tags.id"+
-		" AND tags.user_id = $1 AND notes.id = $2", dictionary.NotesTable)
+	query := notesWithTagsQuery(whereClause([]string{"user_id=$1", "id=$2", notTrashed}), "JOIN")
 
-	row, err := n.db.Query(query, userID, noteID)
+	rows, err := n.db.QueryContext(ctx, query, userID, noteID)
 	if err != nil {
-		return dto.NoteWithTagsResp{}, err
+		return dto.NoteWithTagsResp{}, dbError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	if err := scanNotesWithTags(rows, func(note *dto.NoteWithTagsResp) error {
+		resultNote = *note
+		return nil
+	}); err != nil {
+		return dto.NoteWithTagsResp{}, dbError(err)
+	}
+
+	if resultNote.ID == "" {
+		return dto.NoteWithTagsResp{}, errors.NotFound("note_tags_not_found",
+			fmt.Sprintf("Note with id '%s' has no tags", noteID))
 	}
 
-	for row.Next() {
-		var note dto.NoteWithTags
+	return resultNote, nil
+}
+
+// notesWithTagsQuery query of notes matching where clause joined with their tags, one row per note tag.
+// Tags are joined to notes of the same user, LEFT JOIN keeps notes without tags as one row with NULL tag.
+func notesWithTagsQuery(where, join string) string {
+	return fmt.Sprintf("SELECT notes.id, notes.title, notes.info,"+
+		" tags.id, tags.tagname, tags.created_at, tags.updated_at"+
+		" FROM (SELECT id, title, info, user_id FROM %[1]s%[4]s) notes"+
+		" %[5]s (%[2]s JOIN %[3]s ON %[3]s.id = %[2]s.tag_id)"+
+		" ON %[2]s.note_id = notes.id AND %[3]s.user_id = notes.user_id"+
+		" ORDER BY notes.id, tags.id",
+		dictionary.NotesTable, dictionary.NotesTagsTable, dictionary.TagsTable, where, join)
+}
 
-		if err := row.Scan(&note.ID, &note.Title, &note.Info, &note.TagsResp.ID, &note.TagsResp.TagName); err != nil {
-			log.Println(err)
+// scanNotesWithTags group rows of notesWithTagsQuery into notes, every note is passed to fn when all its rows are read.
+func scanNotesWithTags(rows *sql.Rows, fn func(note *dto.NoteWithTagsResp) error) error {
+	var note *dto.NoteWithTagsResp
+
+	for rows.Next() {
+		var (
+			row                    dto.NotesResp
+			tagID, tagName         sql.NullString
+			tagCreated, tagUpdated sql.NullTime
+		)
+
+		if err := rows.Scan(&row.ID, &row.Title, &row.Info, &tagID, &tagName, &tagCreated, &tagUpdated); err != nil {
+			return err
+		}
+
+		if note == nil || note.ID != row.ID {
+			if note != nil {
+				if err := fn(note); err != nil {
+					return err
+				}
+			}
+
+			note = &dto.NoteWithTagsResp{ID: row.ID, Title: row.Title, Info: row.Info, TagsResp: make([]dto.TagsResp, 0)}
 		}
 
-		resultNote.ID = note.ID
-		resultNote.Title = note.Title
-		resultNote.Info = note.Info
-		resultNote.TagsResp = append(resultNote.TagsResp, note.TagsResp)
+		if tagID.Valid {
+			note.TagsResp = append(note.TagsResp, dto.TagsResp{
+				ID:        tagID.String,
+				TagName:   tagName.String,
+				CreatedAt: tagCreated.Time,
+				UpdatedAt: tagUpdated.Time,
+			})
+		}
 	}
 
-	if resultNote.ID == "" {
-		return dto.NoteWithTagsResp{}, fmt.Errorf(fmt.Sprintf("Note with id '%s' has no tags", noteID))
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return resultNote, nil
+	if note == nil {
+		return nil
+	}
+
+	return fn(note)
 }
@@ -1,16 +1,23 @@
 package storage
 
 import (
+	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 	"web/pkg/database"
+	"web/pkg/tagquery"
 )
 
+// epoch default created_at and updated_at of rows inserted by test db client.
+var epoch = time.Unix(0, 0).UTC()
+
 func TestNoteStorage_CreateNote(t *testing.T) {
 	db := database.NewTestDBClient()
 	defer db.Close()
@@ -40,13 +47,17 @@ func TestNoteStorage_CreateNote(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
 
-			storage := NewNoteStorage(db.Client)
+			storage := NewNoteStorage(db.Client, 0)
 
-			actual, err := storage.CreateNote(testCase.note, "1")
+			actual, err := storage.CreateNote(context.Background(), testCase.note, "1")
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
 
+			require.WithinDuration(t, time.Now(), actual.CreatedAt, time.Minute)
+			require.Equal(t, actual.CreatedAt, actual.UpdatedAt)
+			testCase.expected.CreatedAt, testCase.expected.UpdatedAt = actual.CreatedAt, actual.UpdatedAt
+
 			require.Equal(t, testCase.expected, actual)
 			require.NoError(t, testCase.err, err)
 		})
@@ -69,8 +80,11 @@ func TestNoteStorage_GetNoteByID(t *testing.T) {
 				Info:  "test_info",
 			},
 			expected: &dto.NoteResp{
-				Title: "test_title",
-				Info:  "test_info",
+				Title:     "test_title",
+				Info:      "test_info",
+				CreatedAt: epoch,
+				UpdatedAt: epoch,
+				Version:   1,
 			},
 			testName: "Test-1-OK",
 		},
@@ -85,9 +99,9 @@ func TestNoteStorage_GetNoteByID(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			storage := NewNoteStorage(db.Client, 0)
 
-			actual, err := storage.GetNoteByID("1", "1")
+			actual, err := storage.GetNoteByID(context.Background(), "1", "1")
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -115,9 +129,11 @@ func TestNoteStorage_GetAllNotesByUser(t *testing.T) {
 			},
 			expected: []dto.NotesResp{
 				{
-					ID:    "1",
-					Title: "test_title",
-					Info:  "test_info",
+					ID:        "1",
+					Title:     "test_title",
+					Info:      "test_info",
+					CreatedAt: epoch,
+					UpdatedAt: epoch,
 				},
 			},
 			testName: "Test-1-OK",
@@ -133,9 +149,9 @@ func TestNoteStorage_GetAllNotesByUser(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			storage := NewNoteStorage(db.Client, 0)
 
-			actual, err := storage.GetAllNotesByUser("1")
+			actual, err := storage.GetAllNotesByUser(context.Background(), "1", nil)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -182,9 +198,9 @@ func TestNoteStorage_UpdateNote(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			storage := NewNoteStorage(db.Client, 0)
 
-			actual := storage.UpdateNote(testCase.newNote, "1")
+			_, actual := storage.UpdateNote(context.Background(), testCase.newNote, "1", "1", 0, 0)
 
 			require.NoError(t, testCase.expected, actual)
 		})
@@ -221,9 +237,9 @@ func TestNoteStorage_DeleteNote(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			storage := NewNoteStorage(db.Client, 0)
 
-			actual, err := storage.DeleteNote("1", "1")
+			actual, err := storage.DeleteNote(context.Background(), "1", "1", 0)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -234,29 +250,284 @@ func TestNoteStorage_DeleteNote(t *testing.T) {
 	}
 }
 
-func TestNoteStorage_SetTags(t *testing.T) {
+func TestNoteStorage_UpdateNoteTags(t *testing.T) {
 	db := database.NewTestDBClient()
 	defer db.Close()
 
+	ids := func(ids ...int64) []int64 { return append([]int64{}, ids...) }
+
 	testTable := []struct {
-		note     *model.Note
-		tags     map[string]string
-		expected string
-		err      error
+		update       *dto.NoteTagsUpdate
+		expected     *dto.NoteTagsResp
+		expectedTags []int64
+		tagID        string
+		isErr        bool
+		testName     string
+	}{
+		{
+			update:       &dto.NoteTagsUpdate{Set: &[]int64{2, 3, 3}},
+			expected:     &dto.NoteTagsResp{Added: ids(3), Removed: ids(1), Unchanged: ids(2)},
+			expectedTags: ids(2, 3),
+			testName:     "Test-1-Set tags",
+		},
+		{
+			update:       &dto.NoteTagsUpdate{Set: &[]int64{}},
+			expected:     &dto.NoteTagsResp{Added: ids(), Removed: ids(1, 2), Unchanged: ids()},
+			expectedTags: ids(),
+			testName:     "Test-2-Set no tags",
+		},
+		{
+			update:       &dto.NoteTagsUpdate{Add: ids(2, 3), Remove: ids(1)},
+			expected:     &dto.NoteTagsResp{Added: ids(3), Removed: ids(1), Unchanged: ids(2)},
+			expectedTags: ids(2, 3),
+			testName:     "Test-3-Add and remove tags",
+		},
+		{
+			update:       &dto.NoteTagsUpdate{Add: ids(1), Remove: ids(3)},
+			expected:     &dto.NoteTagsResp{Added: ids(), Removed: ids(), Unchanged: ids(1, 3)},
+			expectedTags: ids(1, 2),
+			testName:     "Test-4-Nothing changed",
+		},
+		{
+			update:       &dto.NoteTagsUpdate{Add: ids(3, 4), Remove: ids(1)},
+			expectedTags: ids(1, 2),
+			tagID:        "4",
+			isErr:        true,
+			testName:     "Test-5-Tag of other user rolls back",
+		},
+		{
+			update:       &dto.NoteTagsUpdate{Set: &[]int64{5}},
+			expectedTags: ids(1, 2),
+			tagID:        "5",
+			isErr:        true,
+			testName:     "Test-6-Unknown tag rolls back",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			for _, name := range []string{"tag1", "tag2", "tag3"} {
+				if err := db.InsertTestTag(&model.Tag{TagName: name}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			if err := db.InsertTestTag(&model.Tag{TagName: "tag4"}, "2"); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1), (1, 2)")
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual, tagID, err := storage.UpdateNoteTags(context.Background(), "1", "1", testCase.update)
+			require.Equal(t, testCase.isErr, err != nil)
+			require.Equal(t, testCase.tagID, tagID)
+			require.Equal(t, testCase.expected, actual)
+
+			tags := make([]int64, 0)
+			require.NoError(t, db.Client.Select(&tags, "SELECT tag_id FROM notes_tags WHERE note_id = 1 ORDER BY tag_id"))
+			require.Equal(t, testCase.expectedTags, tags)
+		})
+	}
+}
+
+func TestNoteStorage_CreateNotePerUserTitle(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		userID   string
+		isErr    bool
 		testName string
 	}{
 		{
-			note: &model.Note{
-				Title: "test_title",
-				Info:  "test_info",
+			userID:   "2",
+			testName: "Test-1-Same title of other user",
+		},
+		{
+			userID:   "1",
+			isErr:    true,
+			testName: "Test-2-Same title of the same user",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, name := range []string{"test_name1", "test_name2"} {
+				if err := db.InsertTestUser(&model.User{Username: name, Password: "test_password"}); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			_, err := storage.CreateNote(context.Background(), &model.Note{Title: "TODO", Info: "test_info"}, "1")
+			require.NoError(t, err)
+
+			_, err = storage.CreateNote(context.Background(), &model.Note{Title: "TODO", Info: "test_info"}, testCase.userID)
+			require.Equal(t, testCase.isErr, err != nil)
+		})
+	}
+}
+
+func TestNoteStorage_GetAllNotesByUserFilters(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		listQuery   *dto.ListQuery
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			listQuery:   &dto.ListQuery{},
+			expectedIDs: []string{"1", "2"},
+			testName:    "Test-1-No filters",
+		},
+		{
+			listQuery:   &dto.ListQuery{CreatedAfter: &day},
+			expectedIDs: []string{"2"},
+			testName:    "Test-2-Created after",
+		},
+		{
+			listQuery:   &dto.ListQuery{UpdatedSince: &day},
+			expectedIDs: []string{"1", "2"},
+			testName:    "Test-3-Updated since",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			for _, title := range []string{"old_title", "new_title"} {
+				if _, err := storage.CreateNote(context.Background(), &model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			// first note was created before the day and edited after it
+			db.Client.MustExec("UPDATE notes SET created_at=$1 WHERE id=1", day.Add(-time.Hour))
+
+			actual, err := storage.GetAllNotesByUser(context.Background(), "1", testCase.listQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, note := range actual {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func TestNoteStorage_UpdateNoteTimestamp(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+
+	info := "new_info"
+	_, err := storage.UpdateNote(context.Background(), &dto.NoteUpdate{Info: &info}, "1", "1", 0, 0)
+	require.NoError(t, err)
+
+	actual, err := storage.GetNoteByID(context.Background(), "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, epoch, actual.CreatedAt)
+	require.WithinDuration(t, time.Now(), actual.UpdatedAt, time.Minute)
+}
+
+func TestNoteStorage_GetAllNotesByUserPage(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	cursorTitle := "b_title"
+
+	testTable := []struct {
+		listQuery     *dto.ListQuery
+		expectedIDs   []string
+		expectedTotal int
+		testName      string
+	}{
+		{
+			listQuery:     &dto.ListQuery{Sort: "id", Limit: 2},
+			expectedIDs:   []string{"1", "2"},
+			expectedTotal: 4,
+			testName:      "Test-1-First page",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "id", Limit: 2, Cursor: &dto.Cursor{Sort: "id", ID: 2}},
+			expectedIDs:   []string{"3", "4"},
+			expectedTotal: 4,
+			testName:      "Test-2-Page after id cursor",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "title", Limit: 3},
+			expectedIDs:   []string{"3", "1", "4"},
+			expectedTotal: 4,
+			testName:      "Test-3-Sort by title",
+		},
+		{
+			listQuery: &dto.ListQuery{
+				Sort:   "title",
+				Limit:  3,
+				Cursor: &dto.Cursor{Sort: "title", ID: 1, Title: &cursorTitle},
 			},
-			tags: map[string]string{
-				"1": "tag1",
-				"2": "tag2",
+			expectedIDs:   []string{"4", "2"},
+			expectedTotal: 4,
+			testName:      "Test-4-Page after title cursor",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "title", Desc: true},
+			expectedIDs:   []string{"2", "4", "1", "3"},
+			expectedTotal: 4,
+			testName:      "Test-5-Sort by title descending",
+		},
+		{
+			listQuery: &dto.ListQuery{
+				Sort:   "created_at",
+				Cursor: &dto.Cursor{Sort: "created_at", ID: 2, Time: &day},
 			},
-			expected: "",
-			err:      nil,
-			testName: "Test-1-OK",
+			expectedIDs:   []string{"3", "4"},
+			expectedTotal: 4,
+			testName:      "Test-6-Page after cursor of equal created_at",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "id", TitlePrefix: "b_"},
+			expectedIDs:   []string{"1", "4"},
+			expectedTotal: 2,
+			testName:      "Test-7-Title prefix",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "id", TitlePrefix: "%"},
+			expectedIDs:   []string{},
+			expectedTotal: 0,
+			testName:      "Test-8-Title prefix wildcard is escaped",
 		},
 	}
 
@@ -265,47 +536,346 @@ func TestNoteStorage_SetTags(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
 
-			if err := db.InsertTestNote(testCase.note, "1"); err != nil {
+			storage := NewNoteStorage(db.Client, 0)
+
+			for _, title := range []string{"b_title", "c_title", "a_title", "b_title2"} {
+				if _, err := storage.CreateNote(context.Background(), &model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			db.Client.MustExec("UPDATE notes SET created_at=$1", day)
+
+			actual, err := storage.GetAllNotesByUser(context.Background(), "1", testCase.listQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, note := range actual {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+
+			total, err := storage.CountNotesByUser(context.Background(), "1", testCase.listQuery)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedTotal, total)
+		})
+	}
+}
+
+func TestNoteStorage_SearchNotes(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		searchQuery *dto.NoteSearchQuery
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			searchQuery: &dto.NoteSearchQuery{Query: "milk", Limit: 10},
+			expectedIDs: []string{"2", "1"},
+			testName:    "Test-1-Title matches rank first",
+		},
+		{
+			searchQuery: &dto.NoteSearchQuery{Query: "milk", Tags: []string{"home", "shop"}, Limit: 10},
+			expectedIDs: []string{"1"},
+			testName:    "Test-2-Notes with all tags",
+		},
+		{
+			searchQuery: &dto.NoteSearchQuery{Query: "milk", Limit: 1},
+			expectedIDs: []string{"2"},
+			testName:    "Test-3-Limit",
+		},
+		{
+			searchQuery: &dto.NoteSearchQuery{Query: "%", Limit: 10},
+			expectedIDs: []string{},
+			testName:    "Test-4-Wildcard is escaped",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			notes := []*model.Note{
+				{Title: "shopping", Info: "buy milk and bread"},
+				{Title: "milk", Info: "oat milk is over"},
+				{Title: "work", Info: "finish report"},
+			}
+			for _, note := range notes {
+				if err := db.InsertTestNote(note, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			for _, tag := range []*model.Tag{{TagName: "home"}, {TagName: "shop"}} {
+				if err := db.InsertTestTag(tag, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1), (1, 2), (2, 1)")
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual, err := storage.SearchNotes(context.Background(), "1", testCase.searchQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, note := range actual {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func TestNoteStorage_GetAllNotesByUserTagsExpr(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		expression  string
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			expression:  "work",
+			expectedIDs: []string{"1", "2", "3"},
+			testName:    "Test-1-Tag",
+		},
+		{
+			expression:  "work AND (urgent OR blocked) AND NOT archived",
+			expectedIDs: []string{"1", "2"},
+			testName:    "Test-2-Nested expression",
+		},
+		{
+			expression:  "NOT work",
+			expectedIDs: []string{"4"},
+			testName:    "Test-3-Untagged notes match NOT",
+		},
+		{
+			expression:  "other_user_tag OR missing",
+			expectedIDs: []string{},
+			testName:    "Test-4-Tags of other users do not match",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name2", Password: "test_password"}); err != nil {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			for _, title := range []string{"urgent", "blocked", "archived", "untagged"} {
+				if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
 
-			actual, err := storage.SetTags("1", testCase.tags)
-			if err != nil {
+			for _, name := range []string{"work", "urgent", "blocked", "archived"} {
+				if err := db.InsertTestTag(&model.Tag{TagName: name}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			if err := db.InsertTestTag(&model.Tag{TagName: "other_user_tag"}, "2"); err != nil {
 				log.Fatalln(err.Error())
 			}
 
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES" +
+				" (1, 1), (1, 2), (2, 1), (2, 3), (3, 1), (3, 2), (3, 4), (4, 5)")
+
+			expr, err := tagquery.Parse(testCase.expression)
+			require.NoError(t, err)
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual, err := storage.GetAllNotesByUser(context.Background(), "1", &dto.ListQuery{Tags: expr})
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, note := range actual {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+
+			total, err := storage.CountNotesByUser(context.Background(), "1", &dto.ListQuery{Tags: expr})
+			require.NoError(t, err)
+			require.Equal(t, len(testCase.expectedIDs), total)
+		})
+	}
+}
+
+func TestNoteStorage_GetAllNotesByUserTagDescendants(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		expression  string
+		descendants bool
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			expression:  "work",
+			expectedIDs: []string{"1"},
+			testName:    "Test-1-Tag without descendants",
+		},
+		{
+			expression:  "work",
+			descendants: true,
+			expectedIDs: []string{"1", "2", "3"},
+			testName:    "Test-2-Tag with descendants",
+		},
+		{
+			expression:  "work/projects",
+			descendants: true,
+			expectedIDs: []string{"2", "3"},
+			testName:    "Test-3-Child tag with descendants",
+		},
+		{
+			expression:  "NOT work",
+			descendants: true,
+			expectedIDs: []string{"4"},
+			testName:    "Test-4-Tag with same prefix is not a descendant",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, title := range []string{"work", "projects", "alpha", "workshop"} {
+				if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			for _, name := range []string{"work", "work/projects", "work/projects/alpha", "workshop"} {
+				if err := db.InsertTestTag(&model.Tag{TagName: name}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1), (2, 2), (3, 3), (4, 4)")
+
+			expr, err := tagquery.Parse(testCase.expression)
+			require.NoError(t, err)
+
+			storage := NewNoteStorage(db.Client, 0)
+			listQuery := &dto.ListQuery{Tags: expr, TagDescendants: testCase.descendants}
+
+			actual, err := storage.GetAllNotesByUser(context.Background(), "1", listQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, note := range actual {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func TestNoteStorage_GetAllNotesWithTags(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	tag := func(id, name string) dto.TagsResp {
+		return dto.TagsResp{ID: id, TagName: name, CreatedAt: epoch, UpdatedAt: epoch}
+	}
+
+	testTable := []struct {
+		includeUntagged bool
+		expected        []dto.NoteWithTagsResp
+		testName        string
+	}{
+		{
+			expected: []dto.NoteWithTagsResp{
+				{ID: "1", Title: "title1", Info: "info1", TagsResp: []dto.TagsResp{tag("1", "tag1"), tag("2", "tag2")}},
+				{ID: "3", Title: "title3", Info: "info3", TagsResp: []dto.TagsResp{tag("2", "tag2")}},
+			},
+			testName: "Test-1-Tagged notes",
+		},
+		{
+			includeUntagged: true,
+			expected: []dto.NoteWithTagsResp{
+				{ID: "1", Title: "title1", Info: "info1", TagsResp: []dto.TagsResp{tag("1", "tag1"), tag("2", "tag2")}},
+				{ID: "2", Title: "title2", Info: "info2", TagsResp: []dto.TagsResp{}},
+				{ID: "3", Title: "title3", Info: "info3", TagsResp: []dto.TagsResp{tag("2", "tag2")}},
+			},
+			testName: "Test-2-Include untagged notes",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, i := range []string{"1", "2", "3"} {
+				if err := db.InsertTestNote(&model.Note{Title: "title" + i, Info: "info" + i}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			for _, name := range []string{"tag1", "tag2"} {
+				if err := db.InsertTestTag(&model.Tag{TagName: name}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 2), (1, 1), (3, 2)")
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual := make([]dto.NoteWithTagsResp, 0)
+			err := storage.GetAllNotesWithTags(context.Background(), "1", nil, testCase.includeUntagged, func(note *dto.NoteWithTagsResp) error {
+				actual = append(actual, *note)
+				return nil
+			})
+			require.NoError(t, err)
 			require.Equal(t, testCase.expected, actual)
-			require.NoError(t, testCase.err, err)
 		})
 	}
 }
 
-func TestNoteStorage_RemoveTags(t *testing.T) {
+func TestNoteStorage_GetNoteWithAllTags(t *testing.T) {
 	db := database.NewTestDBClient()
 	defer db.Close()
 
 	testTable := []struct {
-		note     *model.Note
-		tags     map[string]string
-		expected string
-		err      error
+		noteID   string
+		expected dto.NoteWithTagsResp
+		isErr    bool
 		testName string
 	}{
 		{
-			note: &model.Note{
-				Title: "test_title",
-				Info:  "test_info",
+			noteID: "1",
+			expected: dto.NoteWithTagsResp{
+				ID:       "1",
+				Title:    "title1",
+				Info:     "info1",
+				TagsResp: []dto.TagsResp{{ID: "1", TagName: "tag1", CreatedAt: epoch, UpdatedAt: epoch}},
 			},
-			tags: map[string]string{
-				"1": "tag1",
-				"2": "tag2",
-			},
-			expected: "",
-			err:      nil,
 			testName: "Test-1-OK",
 		},
+		{
+			noteID:   "2",
+			expected: dto.NoteWithTagsResp{},
+			isErr:    true,
+			testName: "Test-2-Note has no tags",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -313,19 +883,391 @@ func TestNoteStorage_RemoveTags(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
 
-			if err := db.InsertTestNote(testCase.note, "1"); err != nil {
+			for _, i := range []string{"1", "2"} {
+				if err := db.InsertTestNote(&model.Note{Title: "title" + i, Info: "info" + i}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			if err := db.InsertTestTag(&model.Tag{TagName: "tag1"}, "1"); err != nil {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewNoteStorage(db.Client)
+			db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1)")
 
-			actual, err := storage.RemoveTags("1", testCase.tags)
-			if err != nil {
-				log.Fatalln(err.Error())
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual, err := storage.GetNoteWithAllTags(context.Background(), "1", testCase.noteID)
+			require.Equal(t, testCase.isErr, err != nil)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestNoteStorage_CanceledContext(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := storage.GetNoteByID(ctx, "1", "1")
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = storage.UpdateNoteTags(ctx, "1", "1", &dto.NoteTagsUpdate{Add: []int64{1}})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNoteStorage_Trash(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	// trashed note is hidden from notes
+	id, err := storage.DeleteNote(ctx, "1", "1", 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	_, err = storage.GetNoteByID(ctx, "1", "1")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	notes, err := storage.GetAllNotesByUser(ctx, "1", nil)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "2", notes[0].ID)
+
+	_, err = storage.DeleteNote(ctx, "1", "1", 0)
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	trash, err := storage.GetTrash(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, "1", trash[0].ID)
+	require.WithinDuration(t, time.Now(), trash[0].DeletedAt, time.Minute)
+
+	// only trashed notes are restored and purged
+	_, err = storage.RestoreNote(ctx, "2", "1")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	_, err = storage.PurgeNote(ctx, "2", "1")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	id, err = storage.RestoreNote(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	note, err := storage.GetNoteByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), note.UpdatedAt, time.Minute)
+
+	_, err = storage.DeleteNote(ctx, "2", "1", 0)
+	require.NoError(t, err)
+
+	id, err = storage.PurgeNote(ctx, "2", "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, id)
+
+	trash, err = storage.GetTrash(ctx, "1")
+	require.NoError(t, err)
+	require.Empty(t, trash)
+}
+
+func TestNoteStorage_RestoreNoteTitleTaken(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	storage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	_, err := storage.CreateNote(ctx, &model.Note{Title: "TODO", Info: "test_info"}, "1")
+	require.NoError(t, err)
+
+	_, err = storage.DeleteNote(ctx, "1", "1", 0)
+	require.NoError(t, err)
+
+	// title of trashed note is free
+	_, err = storage.CreateNote(ctx, &model.Note{Title: "TODO", Info: "test_info"}, "1")
+	require.NoError(t, err)
+
+	_, err = storage.RestoreNote(ctx, "1", "1")
+	require.ErrorIs(t, err, errors.ErrNoteTitleTaken)
+}
+
+func TestNoteStorage_PurgeTrash(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		before      time.Time
+		expected    int
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			before:      time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected:    0,
+			expectedIDs: []string{"1", "2"},
+			testName:    "Test-1-Nothing to purge",
+		},
+		{
+			before:      time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected:    1,
+			expectedIDs: []string{"2"},
+			testName:    "Test-2-Purge old trash",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, title := range []string{"test_title1", "test_title2", "test_title3"} {
+				if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
 			}
 
+			db.Client.MustExec("UPDATE notes SET deleted_at=$1 WHERE id=1", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+			db.Client.MustExec("UPDATE notes SET deleted_at=$1 WHERE id=2", time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC))
+
+			storage := NewNoteStorage(db.Client, 0)
+
+			actual, err := storage.PurgeTrash(context.Background(), testCase.before)
+			require.NoError(t, err)
 			require.Equal(t, testCase.expected, actual)
-			require.NoError(t, testCase.err, err)
+
+			trash, err := storage.GetTrash(context.Background(), "1")
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(trash))
+			for _, note := range trash {
+				actualIDs = append(actualIDs, note.ID)
+			}
+
+			require.ElementsMatch(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
+func TestNoteStorage_NoteRevisions(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		maxRevisions int
+		expectedRevs []int
+		testName     string
+	}{
+		{
+			maxRevisions: 0,
+			expectedRevs: []int{3, 2, 1},
+			testName:     "Test-1-All revisions are kept",
+		},
+		{
+			maxRevisions: 2,
+			expectedRevs: []int{3, 2},
+			testName:     "Test-2-Old revisions are deleted",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestNote(&model.Note{Title: "title_0", Info: "info_0"}, "1"); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewNoteStorage(db.Client, 0)
+			ctx := context.Background()
+
+			for _, info := range []string{"info_1", "info_2", "info_3"} {
+				info := info
+				_, err := storage.UpdateNote(ctx, &dto.NoteUpdate{Info: &info}, "1", "1", 0, testCase.maxRevisions)
+				require.NoError(t, err)
+			}
+
+			revisions, err := storage.GetNoteRevisions(ctx, "1", "1")
+			require.NoError(t, err)
+
+			actualRevs := make([]int, 0, len(revisions))
+			for _, revision := range revisions {
+				actualRevs = append(actualRevs, revision.Rev)
+				require.Equal(t, "1", revision.AuthorID)
+			}
+
+			require.Equal(t, testCase.expectedRevs, actualRevs)
+
+			// revision keeps note content before the update
+			revision, err := storage.GetNoteRevision(ctx, "1", "3", "1")
+			require.NoError(t, err)
+			require.Equal(t, "title_0", revision.Title)
+			require.Equal(t, "info_2", revision.Info)
+
+			_, err = storage.GetNoteRevision(ctx, "1", "3", "2")
+			require.ErrorIs(t, err, errors.ErrNotFound)
 		})
 	}
 }
+
+func TestNoteStorage_UnchangedNoteRevision(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	title, info := "test_title", "test_info"
+	_, err := storage.UpdateNote(ctx, &dto.NoteUpdate{Title: &title, Info: &info}, "1", "1", 0, 1)
+	require.NoError(t, err)
+
+	revisions, err := storage.GetNoteRevisions(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+
+	newInfo := "new_info"
+	_, err = storage.UpdateNote(ctx, &dto.NoteUpdate{Title: &title, Info: &newInfo}, "1", "1", 0, 1)
+	require.NoError(t, err)
+
+	revisions, err = storage.GetNoteRevisions(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, "1", revisions[0].AuthorID)
+}
+
+func TestNoteStorage_UpdateTrashedNote(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+
+	_, err := storage.DeleteNote(context.Background(), "1", "1", 0)
+	require.NoError(t, err)
+
+	info := "new_info"
+	_, err = storage.UpdateNote(context.Background(), &dto.NoteUpdate{Info: &info}, "1", "1", 0, 0)
+	require.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestNoteStorage_NoteVersion(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	info := "new_info"
+	version, err := storage.UpdateNote(ctx, &dto.NoteUpdate{Info: &info}, "1", "1", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	note, err := storage.GetNoteByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, note.Version)
+
+	// stale version changes nothing
+	staleInfo := "stale_info"
+	_, err = storage.UpdateNote(ctx, &dto.NoteUpdate{Info: &staleInfo}, "1", "1", 1, 0)
+	require.ErrorIs(t, err, errors.ErrVersionMismatch)
+
+	note, err = storage.GetNoteByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "new_info", note.Info)
+	require.Equal(t, 2, note.Version)
+
+	revisions, err := storage.GetNoteRevisions(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+
+	_, err = storage.DeleteNote(ctx, "1", "1", 1)
+	require.ErrorIs(t, err, errors.ErrVersionMismatch)
+
+	_, err = storage.DeleteNote(ctx, "1", "1", 2)
+	require.NoError(t, err)
+}
+
+func TestNoteStorage_SearchNotesSnippetEscaped(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "xss", Info: `<script>alert("x & 'y'")</script>`}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNoteStorage(db.Client, 0)
+
+	actual, err := storage.SearchNotes(context.Background(), "1", &dto.NoteSearchQuery{Query: "alert", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "&lt;script&gt;alert(&#34;x &amp; &#39;y&#39;&#34;)&lt;/script&gt;", actual[0].Snippet)
+}
+
+func TestNoteStorage_SearchNotesNullInfo(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "milk", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	db.Client.MustExec("UPDATE notes SET info=NULL WHERE id=1")
+
+	storage := NewNoteStorage(db.Client, 0)
+
+	actual, err := storage.SearchNotes(context.Background(), "1", &dto.NoteSearchQuery{Query: "milk", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "", actual[0].Snippet)
+}
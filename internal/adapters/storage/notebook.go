@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+)
+
+// notebookColumns columns of dto.NotebookResp, notes in trash are not counted.
+var notebookColumns = fmt.Sprintf("nb.id, nb.name, nb.parent_id, nb.created_at, nb.updated_at,"+
+	" (SELECT COUNT(*) FROM %s n WHERE n.notebook_id=nb.id AND n.%s) AS note_count", dictionary.NotesTable, notTrashed)
+
+// notebookStorage notebook storage struct.
+type notebookStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewNotebookStorage notebook storage func builder.
+func NewNotebookStorage(db *sqlx.DB, queryTimeout time.Duration) NotebookStorage {
+	return &notebookStorage{db: db, timeout: queryTimeout}
+}
+
+// CreateNotebook create notebook in DB, parent notebook must be notebook of the user.
+func (n *notebookStorage) CreateNotebook(
+	ctx context.Context,
+	notebook *model.Notebook,
+	userID string,
+) (*model.Notebook, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if notebook.ParentID != nil {
+		if err := checkNotebook(ctx, tx, *notebook.ParentID, userID); err != nil {
+			return nil, dbError(err)
+		}
+	}
+
+	notebook.CreatedAt = timestamp()
+	notebook.UpdatedAt = notebook.CreatedAt
+
+	query := fmt.Sprintf("INSERT INTO %s (name, parent_id, user_id, created_at, updated_at)"+
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id", dictionary.NotebooksTable)
+	row := tx.QueryRowContext(ctx, query, notebook.Name, notebook.ParentID, userID, notebook.CreatedAt, notebook.UpdatedAt)
+	if err := row.Scan(&notebook.ID); err != nil {
+		return nil, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return notebook, nil
+}
+
+// GetNotebookByID get notebook by id from DB.
+func (n *notebookStorage) GetNotebookByID(ctx context.Context, notebookID, userID string) (*dto.NotebookResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var notebook dto.NotebookResp
+
+	query := fmt.Sprintf("SELECT %s FROM %s nb WHERE nb.id=$1 AND nb.user_id=$2",
+		notebookColumns, dictionary.NotebooksTable)
+	if err := n.db.GetContext(ctx, &notebook, query, notebookID, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &notebook, nil
+}
+
+// GetAllNotebooks get all notebooks of user from DB ordered by name.
+func (n *notebookStorage) GetAllNotebooks(ctx context.Context, userID string) ([]dto.NotebookResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var notebooks []dto.NotebookResp
+
+	query := fmt.Sprintf("SELECT %s FROM %s nb WHERE nb.user_id=$1 ORDER BY nb.name, nb.id",
+		notebookColumns, dictionary.NotebooksTable)
+	if err := n.db.SelectContext(ctx, &notebooks, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return notebooks, nil
+}
+
+// GetNotebookNotes get notes right in the notebook from DB, notes of child notebooks are not included.
+func (n *notebookStorage) GetNotebookNotes(ctx context.Context, notebookID, userID string) ([]dto.NotesResp, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	var notes []dto.NotesResp
+
+	query := fmt.Sprintf("SELECT id, title, info, created_at, updated_at FROM %s"+
+		" WHERE notebook_id=$1 AND user_id=$2 AND %s ORDER BY id", dictionary.NotesTable, notTrashed)
+	if err := n.db.SelectContext(ctx, &notes, query, notebookID, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return notes, nil
+}
+
+// RenameNotebook rename notebook by id in DB.
+func (n *notebookStorage) RenameNotebook(ctx context.Context, notebookID, userID, name string) error {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET name=$1, updated_at=$2 WHERE id=$3 AND user_id=$4", dictionary.NotebooksTable)
+	_, err := n.db.ExecContext(ctx, query, name, timestamp(), notebookID, userID)
+
+	return dbError(err)
+}
+
+// MoveNotebook move notebook by id to parent notebook with its child notebooks, nil parent is root.
+// Notebook can not be moved into itself or its child notebooks, errors.ErrNotebookCycle is returned then.
+func (n *notebookStorage) MoveNotebook(ctx context.Context, notebookID, userID string, parentID *int64) error {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if parentID != nil {
+		if err := checkNotebook(ctx, tx, *parentID, userID); err != nil {
+			return dbError(err)
+		}
+
+		subtree, err := notebookSubtree(ctx, tx, notebookID, userID)
+		if err != nil {
+			return dbError(err)
+		}
+
+		for _, id := range subtree {
+			if id == *parentID {
+				return errors.ErrNotebookCycle
+			}
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET parent_id=$1, updated_at=$2 WHERE id=$3 AND user_id=$4",
+		dictionary.NotebooksTable)
+	if _, err := tx.ExecContext(ctx, query, parentID, timestamp(), notebookID, userID); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// DeleteNotebook delete notebook by id from DB.
+// Recursive delete deletes child notebooks too and moves their notes to trash,
+// otherwise child notebooks and notes are moved to parent of the notebook.
+func (n *notebookStorage) DeleteNotebook(ctx context.Context, notebookID, userID string, recursive bool) (int, error) {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var parentID sql.NullInt64
+
+	query := fmt.Sprintf("SELECT parent_id FROM %s WHERE id=$1 AND user_id=$2", dictionary.NotebooksTable)
+	if err := tx.GetContext(ctx, &parentID, query, notebookID, userID); err != nil {
+		return 0, dbError(err)
+	}
+
+	if recursive {
+		err = deleteNotebookSubtree(ctx, tx, notebookID, userID)
+	} else {
+		err = moveNotebookContents(ctx, tx, notebookID, parentID)
+	}
+
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	var id int
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1 RETURNING id", dictionary.NotebooksTable)
+	if err := tx.QueryRowContext(ctx, query, notebookID).Scan(&id); err != nil {
+		return 0, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(err)
+	}
+
+	return id, nil
+}
+
+// deleteNotebookSubtree delete child notebooks of notebook, notes of the notebook and its children are moved to trash.
+func deleteNotebookSubtree(ctx context.Context, tx *sqlx.Tx, notebookID, userID string) error {
+	subtree, err := notebookSubtree(ctx, tx, notebookID, userID)
+	if err != nil {
+		return err
+	}
+
+	placeholders, args := idsPlaceholders([]interface{}{timestamp()}, subtree)
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at=COALESCE(deleted_at, $1), notebook_id=NULL, version=version+1"+
+		" WHERE notebook_id IN (%s)", dictionary.NotesTable, placeholders)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	placeholders, args = idsPlaceholders([]interface{}{notebookID}, subtree)
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id<>$1 AND id IN (%s)", dictionary.NotebooksTable, placeholders)
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// moveNotebookContents move child notebooks and notes of notebook to its parent, nil parent is root.
+func moveNotebookContents(ctx context.Context, tx *sqlx.Tx, notebookID string, parentID sql.NullInt64) error {
+	now := timestamp()
+
+	query := fmt.Sprintf("UPDATE %s SET parent_id=$1, updated_at=$2 WHERE parent_id=$3", dictionary.NotebooksTable)
+	if _, err := tx.ExecContext(ctx, query, parentID, now, notebookID); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET notebook_id=$1, version=version+1 WHERE notebook_id=$2", dictionary.NotesTable)
+	_, err := tx.ExecContext(ctx, query, parentID, notebookID)
+
+	return err
+}
+
+// SetNoteNotebook move note by id to notebook, nil notebook removes note from its notebook.
+func (n *notebookStorage) SetNoteNotebook(ctx context.Context, noteID, userID string, notebookID *int64) error {
+	ctx, cancel := queryContext(ctx, n.timeout)
+	defer cancel()
+
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if notebookID != nil {
+		if err := checkNotebook(ctx, tx, *notebookID, userID); err != nil {
+			return dbError(err)
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET notebook_id=$1, updated_at=$2, version=version+1"+
+		" WHERE id=$3 AND user_id=$4 AND %s", dictionary.NotesTable, notTrashed)
+	if _, err := tx.ExecContext(ctx, query, notebookID, timestamp(), noteID, userID); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// checkNotebook check notebook is notebook of user, sql.ErrNoRows is returned if it is not.
+func checkNotebook(ctx context.Context, tx *sqlx.Tx, notebookID int64, userID string) error {
+	var id int64
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=$1 AND user_id=$2", dictionary.NotebooksTable)
+
+	return tx.GetContext(ctx, &id, query, notebookID, userID)
+}
+
+// notebookSubtree ids of notebook and all its child notebooks, sql.ErrNoRows is returned if notebook does not exist.
+func notebookSubtree(ctx context.Context, tx *sqlx.Tx, notebookID, userID string) ([]int64, error) {
+	var ids []int64
+
+	query := fmt.Sprintf("WITH RECURSIVE subtree(id) AS ("+
+		"SELECT id FROM %[1]s WHERE id=$1 AND user_id=$2"+
+		" UNION SELECT nb.id FROM %[1]s nb JOIN subtree s ON nb.parent_id=s.id"+
+		") SELECT id FROM subtree", dictionary.NotebooksTable)
+	if err := tx.SelectContext(ctx, &ids, query, notebookID, userID); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ids, nil
+}
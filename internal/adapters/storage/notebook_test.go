@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/pkg/database"
+)
+
+// insertTestNotebooks insert notebooks work (1), work/projects (2) and work/projects/alpha (3) of user 1.
+func insertTestNotebooks(t *testing.T, storage NotebookStorage) {
+	t.Helper()
+
+	var parentID *int64
+
+	for i, name := range []string{"work", "projects", "alpha"} {
+		notebook, err := storage.CreateNotebook(context.Background(), &model.Notebook{Name: name, ParentID: parentID}, "1")
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(i+1), notebook.ID)
+
+		id := int64(i + 1)
+		parentID = &id
+	}
+}
+
+func TestNotebookStorage_CreateNotebook(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	storage := NewNotebookStorage(db.Client, 0)
+	ctx := context.Background()
+
+	insertTestNotebooks(t, storage)
+
+	// names are unique among children of the same parent only
+	_, err := storage.CreateNotebook(ctx, &model.Notebook{Name: "work"}, "1")
+	require.ErrorIs(t, err, errors.ErrConflict)
+
+	parentID := int64(1)
+	_, err = storage.CreateNotebook(ctx, &model.Notebook{Name: "work", ParentID: &parentID}, "1")
+	require.NoError(t, err)
+
+	_, err = storage.CreateNotebook(ctx, &model.Notebook{Name: "work"}, "2")
+	require.NoError(t, err)
+
+	// parent must be notebook of the user
+	_, err = storage.CreateNotebook(ctx, &model.Notebook{Name: "alien", ParentID: &parentID}, "2")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	notebook, err := storage.GetNotebookByID(ctx, "2", "1")
+	require.NoError(t, err)
+	require.Equal(t, "projects", notebook.Name)
+	require.Equal(t, "1", *notebook.ParentID)
+
+	_, err = storage.GetNotebookByID(ctx, "2", "2")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	notebooks, err := storage.GetAllNotebooks(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, notebooks, 4)
+}
+
+func TestNotebookStorage_MoveNotebook(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	storage := NewNotebookStorage(db.Client, 0)
+	ctx := context.Background()
+
+	insertTestNotebooks(t, storage)
+
+	// notebook can not be moved into itself or its children
+	for _, id := range []int64{1, 3} {
+		parentID := id
+		require.ErrorIs(t, storage.MoveNotebook(ctx, "1", "1", &parentID), errors.ErrNotebookCycle)
+	}
+
+	require.NoError(t, storage.MoveNotebook(ctx, "3", "1", nil))
+
+	notebook, err := storage.GetNotebookByID(ctx, "3", "1")
+	require.NoError(t, err)
+	require.Nil(t, notebook.ParentID)
+
+	parentID := int64(3)
+	require.NoError(t, storage.MoveNotebook(ctx, "1", "1", &parentID))
+
+	notebook, err = storage.GetNotebookByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "3", *notebook.ParentID)
+
+	require.NoError(t, storage.RenameNotebook(ctx, "1", "1", "home"))
+
+	notebook, err = storage.GetNotebookByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "home", notebook.Name)
+}
+
+func TestNotebookStorage_DeleteNotebook(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2", "test_title3"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewNotebookStorage(db.Client, 0)
+	noteStorage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	insertTestNotebooks(t, storage)
+
+	// note 1 is in work, note 2 in projects, note 3 in alpha
+	for i, noteID := range []string{"1", "2", "3"} {
+		notebookID := int64(i + 1)
+		require.NoError(t, storage.SetNoteNotebook(ctx, noteID, "1", &notebookID))
+	}
+
+	notes, err := storage.GetNotebookNotes(ctx, "2", "1")
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "2", notes[0].ID)
+
+	// contents of projects are moved to work
+	id, err := storage.DeleteNotebook(ctx, "2", "1", false)
+	require.NoError(t, err)
+	require.Equal(t, 2, id)
+
+	notebook, err := storage.GetNotebookByID(ctx, "3", "1")
+	require.NoError(t, err)
+	require.Equal(t, "1", *notebook.ParentID)
+
+	notebook, err = storage.GetNotebookByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, notebook.NoteCount)
+
+	// work and alpha are deleted, their notes are moved to trash
+	id, err = storage.DeleteNotebook(ctx, "1", "1", true)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	_, err = storage.GetNotebookByID(ctx, "3", "1")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	trash, err := noteStorage.GetTrash(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, trash, 3)
+
+	_, err = storage.DeleteNotebook(ctx, "1", "1", true)
+	require.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestNotebookStorage_SetNoteNotebook(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestNote(&model.Note{Title: "test_title", Info: "test_info"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewNotebookStorage(db.Client, 0)
+	noteStorage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	insertTestNotebooks(t, storage)
+
+	notebookID := int64(2)
+	require.NoError(t, storage.SetNoteNotebook(ctx, "1", "1", &notebookID))
+
+	// note belongs to one notebook only
+	notebookID = 3
+	require.NoError(t, storage.SetNoteNotebook(ctx, "1", "1", &notebookID))
+
+	note, err := noteStorage.GetNoteByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "3", *note.NotebookID)
+	require.Equal(t, 3, note.Version)
+
+	notebook, err := storage.GetNotebookByID(ctx, "2", "1")
+	require.NoError(t, err)
+	require.Zero(t, notebook.NoteCount)
+
+	// notebook must be notebook of the user
+	require.ErrorIs(t, storage.SetNoteNotebook(ctx, "1", "2", &notebookID), errors.ErrNotFound)
+
+	require.NoError(t, storage.SetNoteNotebook(ctx, "1", "1", nil))
+
+	note, err = noteStorage.GetNoteByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Nil(t, note.NotebookID)
+}
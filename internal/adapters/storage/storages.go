@@ -2,6 +2,10 @@
 package storage
 
 import (
+	"context"
+
+	"time"
+
 	"github.com/jmoiron/sqlx"
 
 	"web/internal/domain/entities/dto"
@@ -10,54 +14,152 @@ import (
 
 // UserAuthStorage Auth interface.
 type UserAuthStorage interface {
-	RegisterUser(user *model.User) (*model.User, error)
-	GetUserForToken(userName, password string) (*model.User, error)
+	RegisterUser(ctx context.Context, user *model.User, verification *model.UserToken,
+		mail *model.Mail) (*model.User, error)
+	GetUserByUsername(ctx context.Context, userName string) (*model.User, error)
+	GetUserRole(ctx context.Context, userID string) (string, error)
+	UpdatePasswordHash(ctx context.Context, userID, passwordHash string) error
 }
 
 // UserStorage User interface.
 type UserStorage interface {
-	GetUserByID(id string) (*dto.UserResp, error)
-	GetAllUsers() ([]dto.UserResp, error)
-	UpdateUser(newUser *dto.UserUpdate, userID string) error
-	DeleteUser(id string) (int, error)
+	GetUserByID(ctx context.Context, id string) (*dto.UserResp, error)
+	GetAllUsers(ctx context.Context, listQuery *dto.ListQuery) ([]dto.UserResp, error)
+	UpdateUser(ctx context.Context, newUser *dto.UserUpdate, userID string) error
+	DeleteUser(ctx context.Context, id string) (int, error)
 }
 
 // NoteStorage Note interface.
 type NoteStorage interface {
-	CreateNote(note *model.Note, userID string) (*model.Note, error)
-	GetNoteByID(id string, userID string) (*dto.NoteResp, error)
-	GetAllNotesByUser(userID string) ([]dto.NotesResp, error)
-	UpdateNote(newNote *dto.NoteUpdate, noteID string) error
-	DeleteNote(noteID, userID string) (int, error)
-	SetTags(noteID string, tags map[string]string) (string, error)
-	RemoveTags(noteID string, tags map[string]string) (string, error)
-	GetAllNotesWithTags(userID string, notes []dto.NotesResp) ([]dto.NoteWithTagsResp, error)
-	GetNoteWithAllTags(userID, noteID string, note *dto.NoteResp) (dto.NoteWithTagsResp, error)
+	CreateNote(ctx context.Context, note *model.Note, userID string) (*model.Note, error)
+	GetNoteByID(ctx context.Context, id string, userID string) (*dto.NoteResp, error)
+	GetAllNotesByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.NotesResp, error)
+	CountNotesByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) (int, error)
+	SearchNotes(ctx context.Context, userID string, searchQuery *dto.NoteSearchQuery) ([]dto.NoteSearchResp, error)
+	UpdateNote(ctx context.Context, newNote *dto.NoteUpdate, noteID, userID string,
+		version, maxRevisions int) (int, error)
+	GetNoteRevisions(ctx context.Context, noteID, userID string) ([]dto.NoteRevisionsResp, error)
+	GetNoteRevision(ctx context.Context, noteID, rev, userID string) (*dto.NoteRevisionResp, error)
+	DeleteNote(ctx context.Context, noteID, userID string, version int) (int, error)
+	GetTrash(ctx context.Context, userID string) ([]dto.TrashNoteResp, error)
+	RestoreNote(ctx context.Context, noteID, userID string) (int, error)
+	PurgeNote(ctx context.Context, noteID, userID string) (int, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+	UpdateNoteTags(ctx context.Context, noteID, userID string,
+		update *dto.NoteTagsUpdate) (*dto.NoteTagsResp, string, error)
+	GetAllNotesWithTags(ctx context.Context, userID string, listQuery *dto.ListQuery, includeUntagged bool,
+		fn func(note *dto.NoteWithTagsResp) error) error
+	GetNoteWithAllTags(ctx context.Context, userID, noteID string) (dto.NoteWithTagsResp, error)
 }
 
 // TagStorage Tag interface.
 type TagStorage interface {
-	CreateTag(tag *model.Tag, userID string) (*model.Tag, error)
-	GetTagByID(tagID, userID string) (*dto.TagResp, error)
-	GetAllTagsByUser(userID string) ([]dto.TagsResp, error)
-	UpdateTag(tag *dto.TagUpdate, tagID string) error
-	DeleteTag(tagID, userID string) (int, error)
+	CreateTag(ctx context.Context, tag *model.Tag, userID string) (*model.Tag, error)
+	GetTagByID(ctx context.Context, tagID, userID string) (*dto.TagResp, error)
+	GetAllTagsByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.TagsResp, error)
+	CountTagsByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) (int, error)
+	GetTagTree(ctx context.Context, userID string) ([]dto.TagTreeResp, error)
+	UpdateTag(ctx context.Context, tag *dto.TagUpdate, tagID string, version int) (int, error)
+	DeleteTag(ctx context.Context, tagID, userID string, version int) (int, error)
+	GetTagStats(ctx context.Context, userID string) ([]dto.TagStatsResp, error)
+	MergeTags(ctx context.Context, userID string, merge *dto.TagMerge) (*dto.TagMergeResp, string, error)
+}
+
+// NotebookStorage Notebook interface.
+type NotebookStorage interface {
+	CreateNotebook(ctx context.Context, notebook *model.Notebook, userID string) (*model.Notebook, error)
+	GetNotebookByID(ctx context.Context, notebookID, userID string) (*dto.NotebookResp, error)
+	GetAllNotebooks(ctx context.Context, userID string) ([]dto.NotebookResp, error)
+	GetNotebookNotes(ctx context.Context, notebookID, userID string) ([]dto.NotesResp, error)
+	RenameNotebook(ctx context.Context, notebookID, userID, name string) error
+	MoveNotebook(ctx context.Context, notebookID, userID string, parentID *int64) error
+	DeleteNotebook(ctx context.Context, notebookID, userID string, recursive bool) (int, error)
+	SetNoteNotebook(ctx context.Context, noteID, userID string, notebookID *int64) error
+}
+
+// TokenStorage Token interface.
+type TokenStorage interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldID string, newToken *model.RefreshToken) error
+	RevokeSession(ctx context.Context, accessJTI string, expiresAt time.Time) error
+	RevokeAllSessions(ctx context.Context, userID string) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// AccessTokenStorage AccessToken interface.
+type AccessTokenStorage interface {
+	CreateAccessToken(ctx context.Context, token *model.AccessToken) (*model.AccessToken, error)
+	GetAccessTokenByHash(ctx context.Context, tokenHash string) (*model.AccessToken, error)
+	GetAllAccessTokens(ctx context.Context, userID string) ([]model.AccessToken, error)
+	DeleteAccessToken(ctx context.Context, tokenID, userID string) (int, error)
+}
+
+// TwoFactorStorage TwoFactor interface.
+type TwoFactorStorage interface {
+	GetTwoFactor(ctx context.Context, userID string) (*model.TwoFactor, error)
+	SetSecret(ctx context.Context, userID, secret string) error
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID string) error
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CreateChallenge(ctx context.Context, challenge *model.TwoFactorChallenge) error
+	GetChallenge(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error)
+	AddChallengeAttempt(ctx context.Context, challengeID string) error
+	DeleteChallenge(ctx context.Context, challengeID string) error
+}
+
+// AccountStorage Account interface.
+type AccountStorage interface {
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserEmail(ctx context.Context, userID string) (*model.User, error)
+	CreateUserToken(ctx context.Context, token *model.UserToken, mail *model.Mail) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (string, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (string, error)
+}
+
+// MailStorage Mail outbox interface.
+type MailStorage interface {
+	ClaimPendingMails(ctx context.Context, limit, maxAttempts int, claimedUntil time.Time) ([]model.Mail, error)
+	MarkMailSent(ctx context.Context, mailID string) error
+	MarkMailFailed(ctx context.Context, mailID, lastError string) error
 }
 
 // Storages struct of storages interfaces.
 type Storages struct {
-	Auth UserAuthStorage
-	User UserStorage
-	Note NoteStorage
-	Tag  TagStorage
+	Auth        UserAuthStorage
+	User        UserStorage
+	Note        NoteStorage
+	Tag         TagStorage
+	Notebook    NotebookStorage
+	Token       TokenStorage
+	AccessToken AccessTokenStorage
+	TwoFactor   TwoFactorStorage
+	Account     AccountStorage
+	Mail        MailStorage
 }
 
-// NewStorages storages func builder.
-func NewStorages(db *sqlx.DB) *Storages {
+// NewStorages storages func builder. Each storage call runs its DB queries with queryTimeout deadline.
+func NewStorages(db *sqlx.DB, queryTimeout time.Duration) *Storages {
 	return &Storages{
-		Auth: NewAuthStorage(db),
-		User: NewUserStorage(db),
-		Note: NewNoteStorage(db),
-		Tag:  NewTagStorage(db),
+		Auth:        NewAuthStorage(db, queryTimeout),
+		User:        NewUserStorage(db, queryTimeout),
+		Note:        NewNoteStorage(db, queryTimeout),
+		Tag:         NewTagStorage(db, queryTimeout),
+		Notebook:    NewNotebookStorage(db, queryTimeout),
+		Token:       NewTokenStorage(db, queryTimeout),
+		AccessToken: NewAccessTokenStorage(db, queryTimeout),
+		TwoFactor:   NewTwoFactorStorage(db, queryTimeout),
+		Account:     NewAccountStorage(db, queryTimeout),
+		Mail:        NewMailStorage(db, queryTimeout),
 	}
 }
+
+// queryContext ctx of storage call DB queries with timeout deadline. Zero timeout is no deadline.
+func queryContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
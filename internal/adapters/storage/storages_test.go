@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryContext(t *testing.T) {
+	ctx, cancel := queryContext(context.Background(), 0)
+	_, ok := ctx.Deadline()
+	require.False(t, ok)
+	cancel()
+
+	ctx, cancel = queryContext(context.Background(), time.Minute)
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	cancel()
+	require.Error(t, ctx.Err())
+}
@@ -1,89 +1,503 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
+	e "errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/jmoiron/sqlx"
 
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
 )
 
 // tagStorage tag storage struct.
 type tagStorage struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	timeout time.Duration
 }
 
 // NewTagStorage tag storage func builder.
-func NewTagStorage(db *sqlx.DB) TagStorage {
-	return &tagStorage{db: db}
+func NewTagStorage(db *sqlx.DB, queryTimeout time.Duration) TagStorage {
+	return &tagStorage{db: db, timeout: queryTimeout}
 }
 
-// CreateTag create tag in DB.
-func (t *tagStorage) CreateTag(tag *model.Tag, userID string) (*model.Tag, error) {
-	query := fmt.Sprintf("INSERT INTO %s (tagname, user_id) VALUES ($1, $2) RETURNING id", dictionary.TagsTable)
-	if err := t.db.QueryRow(query, tag.TagName, userID).Scan(&tag.ID); err != nil {
-		return nil, err
+// CreateTag create tag in DB, missing parent tags of tag path are created too.
+func (t *tagStorage) CreateTag(ctx context.Context, tag *model.Tag, userID string) (*model.Tag, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	tag.CreatedAt = timestamp()
+	tag.UpdatedAt = tag.CreatedAt
+
+	parentID, err := tagParentID(ctx, tx, tag.TagName, userID, tag.CreatedAt)
+	if err != nil {
+		return nil, dbError(err)
 	}
 
+	id, err := insertTag(ctx, tx, tag.TagName, parentID, userID, tag.CreatedAt)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err)
+	}
+
+	tag.ID = strconv.FormatInt(id, 10)
+
 	return tag, nil
 }
 
+// tagParentID id of parent tag of tag path, missing parent tags are created. Tags without parent have nil parent.
+func tagParentID(ctx context.Context, tx *sqlx.Tx, tagName, userID string, now time.Time) (*int64, error) {
+	i := strings.LastIndex(tagName, dictionary.TagPathSeparator)
+	if i < 0 {
+		return nil, nil
+	}
+
+	parentName := tagName[:i]
+
+	var id int64
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id=$1 AND tagname=$2", dictionary.TagsTable)
+
+	err := tx.GetContext(ctx, &id, query, userID, parentName)
+	if err == nil {
+		return &id, nil
+	}
+
+	if !e.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	grandParentID, err := tagParentID(ctx, tx, parentName, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err = insertTag(ctx, tx, parentName, grandParentID, userID, now); err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// insertTag insert tag with parent to DB, id of the tag is returned.
+func insertTag(ctx context.Context, tx *sqlx.Tx, tagName string, parentID *int64, userID string,
+	now time.Time,
+) (int64, error) {
+	var id int64
+
+	query := fmt.Sprintf("INSERT INTO %s (tagname, parent_id, user_id, created_at, updated_at)"+
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id", dictionary.TagsTable)
+	err := tx.QueryRowContext(ctx, query, tagName, parentID, userID, now, now).Scan(&id)
+
+	return id, err
+}
+
 // GetTagByID get tag by id from DB.
-func (t *tagStorage) GetTagByID(tagID, userID string) (*dto.TagResp, error) {
+func (t *tagStorage) GetTagByID(ctx context.Context, tagID, userID string) (*dto.TagResp, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
 	var tag dto.TagResp
 
-	query := fmt.Sprintf("SELECT tagname FROM %s WHERE id=$1 AND user_id=$2", dictionary.TagsTable)
-	if err := t.db.Get(&tag, query, tagID, userID); err != nil {
-		return nil, err
+	query := fmt.Sprintf("SELECT tagname, parent_id, created_at, updated_at, version FROM %s"+
+		" WHERE id=$1 AND user_id=$2", dictionary.TagsTable)
+	if err := t.db.GetContext(ctx, &tag, query, tagID, userID); err != nil {
+		return nil, dbError(err)
 	}
 
 	return &tag, nil
 }
 
-// GetAllTagsByUser get all tags by user from DB.
-func (t *tagStorage) GetAllTagsByUser(userID string) ([]dto.TagsResp, error) {
+// GetAllTagsByUser get all tags by user from DB by list query filters, sort, cursor and limit.
+func (t *tagStorage) GetAllTagsByUser(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+) ([]dto.TagsResp, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
 	var tags []dto.TagsResp
 
-	query := fmt.Sprintf("SELECT id, tagname FROM %s WHERE user_id=$1", dictionary.TagsTable)
-	if err := t.db.Select(&tags, query, userID); err != nil {
-		return nil, err
+	conditions, args := pageConditions(listQuery, "tagname", []string{"user_id=$1"}, []interface{}{userID})
+	conditions, args = cursorCondition(listQuery, "tagname", conditions, args)
+
+	query := fmt.Sprintf("SELECT id, tagname, parent_id, created_at, updated_at FROM %s%s%s",
+		dictionary.TagsTable, whereClause(conditions), orderClause(listQuery, "tagname"))
+	if err := t.db.SelectContext(ctx, &tags, query, args...); err != nil {
+		return nil, dbError(err)
 	}
 
 	return tags, nil
 }
 
-// UpdateTag update tag by id in DB.
-func (t *tagStorage) UpdateTag(tag *dto.TagUpdate, tagID string) error {
+// CountTagsByUser count tags by user in DB by list query filters, cursor and limit are not applied.
+func (t *tagStorage) CountTagsByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) (int, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var total int
+
+	conditions, args := pageConditions(listQuery, "tagname", []string{"user_id=$1"}, []interface{}{userID})
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", dictionary.TagsTable, whereClause(conditions))
+	if err := t.db.GetContext(ctx, &total, query, args...); err != nil {
+		return 0, dbError(err)
+	}
+
+	return total, nil
+}
+
+// GetTagTree get all tags by user from DB ordered by path with their parents and note counts.
+// Notes in trash are not counted, total count has distinct notes with the tag or any of its child tags.
+func (t *tagStorage) GetTagTree(ctx context.Context, userID string) ([]dto.TagTreeResp, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var tags []dto.TagTreeResp
+
+	query := fmt.Sprintf("SELECT t.id, t.tagname, t.parent_id,"+
+		" (SELECT COUNT(*) FROM %[2]s nt JOIN %[3]s n ON n.id=nt.note_id"+
+		" WHERE nt.tag_id=t.id AND n.%[4]s) AS note_count,"+
+		" (SELECT COUNT(DISTINCT nt.note_id) FROM %[2]s nt JOIN %[1]s d ON d.id=nt.tag_id JOIN %[3]s n ON n.id=nt.note_id"+
+		" WHERE d.user_id=t.user_id AND n.%[4]s"+
+		" AND (d.id=t.id OR substr(d.tagname, 1, length(t.tagname) + 1) = t.tagname || '%[5]s')) AS total_count"+
+		" FROM %[1]s t WHERE t.user_id=$1 ORDER BY t.tagname",
+		dictionary.TagsTable, dictionary.NotesTagsTable, dictionary.NotesTable, notTrashed, dictionary.TagPathSeparator)
+	if err := t.db.SelectContext(ctx, &tags, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return tags, nil
+}
+
+// GetTagStats get all tags by user from DB ordered by path with their note counts and last use time.
+// Notes in trash are not counted, tag without notes has nil last use time.
+func (t *tagStorage) GetTagStats(ctx context.Context, userID string) ([]dto.TagStatsResp, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var tags []dto.TagStatsResp
+
+	query := fmt.Sprintf("SELECT t.id, t.tagname,"+
+		" (SELECT COUNT(*) FROM %[2]s nt JOIN %[3]s n ON n.id=nt.note_id"+
+		" WHERE nt.tag_id=t.id AND n.%[4]s) AS note_count,"+
+		" (SELECT nt.created_at FROM %[2]s nt JOIN %[3]s n ON n.id=nt.note_id"+
+		" WHERE nt.tag_id=t.id AND n.%[4]s ORDER BY nt.created_at DESC LIMIT 1) AS last_used_at"+
+		" FROM %[1]s t WHERE t.user_id=$1 ORDER BY t.tagname",
+		dictionary.TagsTable, dictionary.NotesTagsTable, dictionary.NotesTable, notTrashed)
+	if err := t.db.SelectContext(ctx, &tags, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return tags, nil
+}
+
+// UpdateTag update tag by id in DB, tag version is incremented.
+// Renamed tag is moved to parent of its new path with its child tags, paths of child tags are renamed too.
+// Missing parent tags are created, tag can not be moved into itself or its child tags, errors.ErrTagCycle then.
+// If version is not 0 and tag has another one errors.ErrVersionMismatch is returned. New version of tag is returned.
+func (t *tagStorage) UpdateTag(ctx context.Context, tag *dto.TagUpdate, tagID string, version int) (int, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var current struct {
+		TagName string `db:"tagname"`
+		UserID  string `db:"user_id"`
+	}
+
+	query := fmt.Sprintf("SELECT tagname, user_id FROM %s WHERE id=$1", dictionary.TagsTable)
+	if err := tx.GetContext(ctx, &current, query, tagID); err != nil {
+		if version != 0 && e.Is(err, sql.ErrNoRows) {
+			return 0, versionError(nil)
+		}
+
+		return 0, dbError(err)
+	}
+
+	now := timestamp()
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argID := 1
 
-	if tag.TagName != nil {
-		setValues = append(setValues, fmt.Sprintf("tagname=$%d", argID))
-		args = append(args, *tag.TagName)
-		argID++
+	renamed := tag.TagName != nil && *tag.TagName != current.TagName
+	if renamed {
+		if strings.HasPrefix(*tag.TagName, current.TagName+dictionary.TagPathSeparator) {
+			return 0, errors.ErrTagCycle
+		}
+
+		parentID, err := tagParentID(ctx, tx, *tag.TagName, current.UserID, now)
+		if err != nil {
+			return 0, dbError(err)
+		}
+
+		setValues = append(setValues, fmt.Sprintf("tagname=$%d, parent_id=$%d", argID, argID+1))
+		args = append(args, *tag.TagName, parentID)
+		argID += 2
 	}
 
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argID), "version=version+1")
+	args = append(args, now)
+	argID++
+
 	setQuery := strings.Join(setValues, ", ")
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", dictionary.TagsTable, setQuery, argID)
-	args = append(args, tagID)
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d%s RETURNING version", dictionary.TagsTable, setQuery,
+		argID, versionCondition(version, argID+1))
+	args = versionArgs(version, append(args, tagID)...)
+
+	var newVersion int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&newVersion); err != nil {
+		if version != 0 && e.Is(err, sql.ErrNoRows) {
+			return 0, versionError(nil)
+		}
+
+		return 0, dbError(err)
+	}
+
+	if renamed {
+		if err := renameChildTags(ctx, tx, current.TagName, *tag.TagName, current.UserID, now); err != nil {
+			return 0, dbError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(err)
+	}
+
+	return newVersion, nil
+}
 
-	_, err := t.db.Exec(query, args...)
+// renameChildTags replace path of tag in paths of all its child tags, their versions are incremented.
+func renameChildTags(ctx context.Context, tx *sqlx.Tx, oldName, newName, userID string, now time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET tagname=$1 || substr(tagname, $2), updated_at=$3, version=version+1"+
+		` WHERE user_id=$4 AND tagname LIKE $5 ESCAPE '\'`, dictionary.TagsTable)
+	_, err := tx.ExecContext(ctx, query, newName, utf8.RuneCountInString(oldName)+1, now, userID,
+		childTagsPattern(oldName))
 
 	return err
 }
 
-// DeleteTag delete tag by id from DB.
-func (t *tagStorage) DeleteTag(tagID, userID string) (int, error) {
-	var id int
+// childTagsPattern LIKE pattern of paths of all child tags of tag.
+func childTagsPattern(tagName string) string {
+	return likeEscaper.Replace(tagName+dictionary.TagPathSeparator) + "%"
+}
 
-	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2 RETURNING id", dictionary.TagsTable)
-	if err := t.db.QueryRow(query, tagID, userID).Scan(&id); err != nil {
-		return 0, err
+// DeleteTag delete tag by id with all its child tags from DB.
+// If version is not 0 and tag has another one errors.ErrVersionMismatch is returned.
+func (t *tagStorage) DeleteTag(ctx context.Context, tagID, userID string, version int) (int, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var (
+		id      int
+		tagName string
+	)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2%s RETURNING id, tagname", dictionary.TagsTable,
+		versionCondition(version, 3))
+	if err := tx.QueryRowContext(ctx, query, versionArgs(version, tagID, userID)...).Scan(&id, &tagName); err != nil {
+		if version != 0 && e.Is(err, sql.ErrNoRows) {
+			return 0, versionError(nil)
+		}
+
+		return 0, dbError(err)
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1 AND tagname LIKE $2 ESCAPE '\'`, dictionary.TagsTable)
+	if _, err := tx.ExecContext(ctx, query, userID, childTagsPattern(tagName)); err != nil {
+		return 0, dbError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(err)
 	}
 
 	return id, nil
 }
+
+// MergeTags move links of source tags to target tag and delete source tags from DB in one transaction.
+// Child tags of source tags are moved to target tag, target can not be a child of source, errors.ErrTagCycle then.
+// Child tag whose path already exists under target tag is merged into the existing tag the same way.
+// If some tag is not a tag of the user nothing is changed and its id is returned with errors.ErrNotFound.
+func (t *tagStorage) MergeTags(
+	ctx context.Context,
+	userID string,
+	merge *dto.TagMerge,
+) (*dto.TagMergeResp, string, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, "", dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	sources := uniqueIDs(merge.SourceIDs)
+
+	if tagID, err := checkUserTags(ctx, tx, userID, append([]int64{merge.TargetID}, sources...)); err != nil {
+		return nil, tagID, dbError(err)
+	}
+
+	var tags []struct {
+		ID      int64  `db:"id"`
+		TagName string `db:"tagname"`
+	}
+
+	placeholders, args := idsPlaceholders(nil, sources)
+
+	// longer paths first, so child tags of nested sources are moved before their parents
+	query := fmt.Sprintf("SELECT id, tagname FROM %s WHERE id IN (%s) ORDER BY length(tagname) DESC",
+		dictionary.TagsTable, placeholders)
+	if err := tx.SelectContext(ctx, &tags, query, args...); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	var target string
+
+	query = fmt.Sprintf("SELECT tagname FROM %s WHERE id=$1", dictionary.TagsTable)
+	if err := tx.GetContext(ctx, &target, query, merge.TargetID); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	for _, tag := range tags {
+		if strings.HasPrefix(target, tag.TagName+dictionary.TagPathSeparator) {
+			return nil, "", errors.ErrTagCycle
+		}
+	}
+
+	now := timestamp()
+	moved := int64(0)
+
+	for _, tag := range tags {
+		tagMoved, err := mergeTag(ctx, tx, tag.ID, tag.TagName, merge.TargetID, target, userID, now)
+		if err != nil {
+			return nil, "", dbError(err)
+		}
+
+		moved += tagMoved
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	return &dto.TagMergeResp{TargetID: merge.TargetID, Merged: sources, MovedLinks: moved}, "", nil
+}
+
+// mergeTag move links of source tag to target tag, move its child tags under target tag and delete it.
+// Child tag whose path already exists under target tag is merged into the existing one, count of notes
+// linked to target tag is returned.
+func mergeTag(ctx context.Context, tx *sqlx.Tx, sourceID int64, source string, targetID int64, target, userID string,
+	now time.Time,
+) (int64, error) {
+	moved, err := moveTagLinks(ctx, tx, []int64{sourceID}, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	var children []struct {
+		ID      int64  `db:"id"`
+		TagName string `db:"tagname"`
+	}
+
+	query := fmt.Sprintf("SELECT id, tagname FROM %s WHERE parent_id=$1", dictionary.TagsTable)
+	if err := tx.SelectContext(ctx, &children, query, sourceID); err != nil {
+		return 0, err
+	}
+
+	for _, child := range children {
+		name := target + strings.TrimPrefix(child.TagName, source)
+
+		var existingID int64
+
+		query := fmt.Sprintf("SELECT id FROM %s WHERE user_id=$1 AND tagname=$2", dictionary.TagsTable)
+		err := tx.GetContext(ctx, &existingID, query, userID, name)
+
+		switch {
+		case e.Is(err, sql.ErrNoRows):
+			query := fmt.Sprintf("UPDATE %s SET tagname=$1, parent_id=$2, updated_at=$3, version=version+1 WHERE id=$4",
+				dictionary.TagsTable)
+			if _, err := tx.ExecContext(ctx, query, name, targetID, now, child.ID); err != nil {
+				return 0, err
+			}
+
+			if err := renameChildTags(ctx, tx, child.TagName, name, userID, now); err != nil {
+				return 0, err
+			}
+		case err != nil:
+			return 0, err
+		default:
+			if _, err := mergeTag(ctx, tx, child.ID, child.TagName, existingID, name, userID, now); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", dictionary.TagsTable)
+	if _, err := tx.ExecContext(ctx, query, sourceID); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// moveTagLinks link notes of source tags to target tag and unlink them from source tags.
+// Notes already with target tag keep their link, link time of moved link is the latest one of source tags.
+func moveTagLinks(ctx context.Context, tx *sqlx.Tx, sources []int64, targetID int64) (int64, error) {
+	placeholders, args := idsPlaceholders([]interface{}{targetID}, sources)
+
+	// target id is cast, type of parameter in select list is unknown for postgres
+	query := fmt.Sprintf("INSERT INTO %[1]s (note_id, created_at, tag_id)"+
+		" SELECT note_id, MAX(created_at), CAST($1 AS integer) FROM %[1]s WHERE tag_id IN (%[2]s)"+
+		" AND note_id NOT IN (SELECT note_id FROM %[1]s WHERE tag_id=$1) GROUP BY note_id",
+		dictionary.NotesTagsTable, placeholders)
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	placeholders, args = idsPlaceholders(nil, sources)
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE tag_id IN (%s)", dictionary.NotesTagsTable, placeholders)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
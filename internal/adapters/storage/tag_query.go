@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"web/internal/utils/dictionary"
+	"web/pkg/tagquery"
+)
+
+// tagsExprCondition append condition of notes matching tag expression, placeholders continue args numbering.
+// With descendants tags of expression match their child tags too.
+func tagsExprCondition(expr tagquery.Expr, descendants bool, conditions []string,
+	args []interface{},
+) ([]string, []interface{}) {
+	if expr == nil {
+		return conditions, args
+	}
+
+	condition, args := compileTagsExpr(expr, descendants, args)
+
+	return append(conditions, condition), args
+}
+
+// compileTagsExpr compile tag expression to condition over notes_tags of the row of notes table.
+// Tag names are passed as args, every tag is an EXISTS subquery over the notes_tags primary key.
+// With descendants tag matches tag name or LIKE pattern of paths of its child tags.
+func compileTagsExpr(expr tagquery.Expr, descendants bool, args []interface{}) (string, []interface{}) {
+	switch node := expr.(type) {
+	case tagquery.Tag:
+		args = append(args, node.Name)
+		tagCondition := fmt.Sprintf("%s.tagname = $%d", dictionary.TagsTable, len(args))
+
+		if descendants {
+			args = append(args, childTagsPattern(node.Name))
+			tagCondition = fmt.Sprintf(`(%s OR %s.tagname LIKE $%d ESCAPE '\')`, tagCondition, dictionary.TagsTable,
+				len(args))
+		}
+
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.tag_id"+
+			" WHERE %[1]s.note_id = %[3]s.id AND %[2]s.user_id = %[3]s.user_id AND %[4]s)",
+			dictionary.NotesTagsTable, dictionary.TagsTable, dictionary.NotesTable, tagCondition), args
+	case tagquery.Not:
+		condition, args := compileTagsExpr(node.Operand, descendants, args)
+
+		return "NOT " + condition, args
+	case tagquery.And:
+		return compileOperands(node.Operands, " AND ", descendants, args)
+	case tagquery.Or:
+		return compileOperands(node.Operands, " OR ", descendants, args)
+	}
+
+	return "", args
+}
+
+// compileOperands compile operands joined by operator in parentheses.
+func compileOperands(operands []tagquery.Expr, operator string, descendants bool,
+	args []interface{},
+) (string, []interface{}) {
+	conditions := make([]string, 0, len(operands))
+
+	for _, operand := range operands {
+		var condition string
+
+		condition, args = compileTagsExpr(operand, descendants, args)
+		conditions = append(conditions, condition)
+	}
+
+	return "(" + strings.Join(conditions, operator) + ")", args
+}
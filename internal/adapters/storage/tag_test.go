@@ -1,13 +1,16 @@
 package storage
 
 import (
+	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 	"web/pkg/database"
 )
 
@@ -38,13 +41,17 @@ func TestTagStorage_CreateTag(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
 
-			storage := NewTagStorage(db.Client)
+			storage := NewTagStorage(db.Client, 0)
 
-			actual, err := storage.CreateTag(testCase.tag, "1")
+			actual, err := storage.CreateTag(context.Background(), testCase.tag, "1")
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
 
+			require.WithinDuration(t, time.Now(), actual.CreatedAt, time.Minute)
+			require.Equal(t, actual.CreatedAt, actual.UpdatedAt)
+			testCase.expected.CreatedAt, testCase.expected.UpdatedAt = actual.CreatedAt, actual.UpdatedAt
+
 			require.Equal(t, testCase.expected, actual)
 			require.NoError(t, testCase.err, err)
 		})
@@ -66,7 +73,10 @@ func TestTagStorage_GetTagByID(t *testing.T) {
 				TagName: "test_tag",
 			},
 			expected: &dto.TagResp{
-				TagName: "test_tag",
+				TagName:   "test_tag",
+				CreatedAt: epoch,
+				UpdatedAt: epoch,
+				Version:   1,
 			},
 			testName: "Test-1-OK",
 		},
@@ -81,9 +91,9 @@ func TestTagStorage_GetTagByID(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewTagStorage(db.Client)
+			storage := NewTagStorage(db.Client, 0)
 
-			actual, err := storage.GetTagByID("1", "1")
+			actual, err := storage.GetTagByID(context.Background(), "1", "1")
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -110,8 +120,10 @@ func TestTagStorage_GetAllTagsByUser(t *testing.T) {
 			},
 			expected: []dto.TagsResp{
 				{
-					ID:      "1",
-					TagName: "test_tag",
+					ID:        "1",
+					TagName:   "test_tag",
+					CreatedAt: epoch,
+					UpdatedAt: epoch,
 				},
 			},
 			testName: "Test-1-OK",
@@ -127,9 +139,9 @@ func TestTagStorage_GetAllTagsByUser(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewTagStorage(db.Client)
+			storage := NewTagStorage(db.Client, 0)
 
-			actual, err := storage.GetAllTagsByUser("1")
+			actual, err := storage.GetAllTagsByUser(context.Background(), "1", nil)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -173,9 +185,9 @@ func TestTagStorage_UpdateTag(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewTagStorage(db.Client)
+			storage := NewTagStorage(db.Client, 0)
 
-			actual := storage.UpdateTag(testCase.newTag, "1")
+			_, actual := storage.UpdateTag(context.Background(), testCase.newTag, "1", 0)
 
 			require.NoError(t, testCase.expected, actual)
 		})
@@ -206,14 +218,14 @@ func TestTagStorage_DeleteTag(t *testing.T) {
 		t.Run(testCase.testName, func(t *testing.T) {
 			db.SetUp()
 			defer db.TearDown()
-			
+
 			if err := db.InsertTestTag(testCase.tag, "1"); err != nil {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewTagStorage(db.Client)
+			storage := NewTagStorage(db.Client, 0)
 
-			actual, err := storage.DeleteTag("1", "1")
+			actual, err := storage.DeleteTag(context.Background(), "1", "1", 0)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -223,3 +235,452 @@ func TestTagStorage_DeleteTag(t *testing.T) {
 		})
 	}
 }
+
+func TestTagStorage_CreateTagPerUserName(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		userID   string
+		isErr    bool
+		testName string
+	}{
+		{
+			userID:   "2",
+			testName: "Test-1-Same name of other user",
+		},
+		{
+			userID:   "1",
+			isErr:    true,
+			testName: "Test-2-Same name of the same user",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, name := range []string{"test_name1", "test_name2"} {
+				if err := db.InsertTestUser(&model.User{Username: name, Password: "test_password"}); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			storage := NewTagStorage(db.Client, 0)
+
+			_, err := storage.CreateTag(context.Background(), &model.Tag{TagName: "work"}, "1")
+			require.NoError(t, err)
+
+			_, err = storage.CreateTag(context.Background(), &model.Tag{TagName: "work"}, testCase.userID)
+			require.Equal(t, testCase.isErr, err != nil)
+		})
+	}
+}
+
+func TestTagStorage_TypedErrors(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		call     func(storage TagStorage) error
+		expected error
+		testName string
+	}{
+		{
+			call: func(storage TagStorage) error {
+				_, err := storage.GetTagByID(context.Background(), "2", "1")
+				return err
+			},
+			expected: errors.ErrNotFound,
+			testName: "Test-1-Missing tag",
+		},
+		{
+			call: func(storage TagStorage) error {
+				_, err := storage.CreateTag(context.Background(), &model.Tag{TagName: "work"}, "1")
+				return err
+			},
+			expected: errors.ErrConflict,
+			testName: "Test-2-Duplicate name",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestTag(&model.Tag{TagName: "work"}, "1"); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			err := testCase.call(NewTagStorage(db.Client, 0))
+			require.ErrorIs(t, err, testCase.expected)
+		})
+	}
+}
+
+func TestTagStorage_GetAllTagsByUserPage(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	cursorName := "b_name"
+
+	testTable := []struct {
+		listQuery     *dto.ListQuery
+		expectedIDs   []string
+		expectedTotal int
+		testName      string
+	}{
+		{
+			listQuery:     &dto.ListQuery{Sort: "title", Desc: true, Limit: 2},
+			expectedIDs:   []string{"2", "1"},
+			expectedTotal: 3,
+			testName:      "Test-1-Sort by name descending",
+		},
+		{
+			listQuery: &dto.ListQuery{
+				Sort:   "title",
+				Desc:   true,
+				Limit:  2,
+				Cursor: &dto.Cursor{Sort: "title", Desc: true, ID: 1, Title: &cursorName},
+			},
+			expectedIDs:   []string{"3"},
+			expectedTotal: 3,
+			testName:      "Test-2-Page after name cursor",
+		},
+		{
+			listQuery:     &dto.ListQuery{Sort: "id", TitlePrefix: "c"},
+			expectedIDs:   []string{"2"},
+			expectedTotal: 1,
+			testName:      "Test-3-Name prefix",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			storage := NewTagStorage(db.Client, 0)
+
+			for _, name := range []string{"b_name", "c_name", "a_name"} {
+				if _, err := storage.CreateTag(context.Background(), &model.Tag{TagName: name}, "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			actual, err := storage.GetAllTagsByUser(context.Background(), "1", testCase.listQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, tag := range actual {
+				actualIDs = append(actualIDs, tag.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+
+			total, err := storage.CountTagsByUser(context.Background(), "1", testCase.listQuery)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedTotal, total)
+		})
+	}
+}
+
+func TestTagStorage_TagVersion(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestTag(&model.Tag{TagName: "work"}, "1"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewTagStorage(db.Client, 0)
+	ctx := context.Background()
+
+	tagName := "home"
+	version, err := storage.UpdateTag(ctx, &dto.TagUpdate{TagName: &tagName}, "1", 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	tag, err := storage.GetTagByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, tag.Version)
+
+	// stale version changes nothing
+	staleName := "stale"
+	_, err = storage.UpdateTag(ctx, &dto.TagUpdate{TagName: &staleName}, "1", 1)
+	require.ErrorIs(t, err, errors.ErrVersionMismatch)
+
+	tag, err = storage.GetTagByID(ctx, "1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "home", tag.TagName)
+
+	_, err = storage.DeleteTag(ctx, "1", "1", 1)
+	require.ErrorIs(t, err, errors.ErrVersionMismatch)
+
+	_, err = storage.DeleteTag(ctx, "1", "1", 2)
+	require.NoError(t, err)
+}
+
+func TestTagStorage_TagHierarchy(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	storage := NewTagStorage(db.Client, 0)
+	ctx := context.Background()
+
+	// missing parent tags of path are created
+	tag, err := storage.CreateTag(ctx, &model.Tag{TagName: "work/projects/alpha"}, "1")
+	require.NoError(t, err)
+	require.Equal(t, "3", tag.ID)
+
+	_, err = storage.CreateTag(ctx, &model.Tag{TagName: "work/projects/beta"}, "1")
+	require.NoError(t, err)
+
+	_, err = storage.CreateTag(ctx, &model.Tag{TagName: "work/projects"}, "1")
+	require.ErrorIs(t, err, errors.ErrConflict)
+
+	parent, err := storage.GetTagByID(ctx, "2", "1")
+	require.NoError(t, err)
+	require.Equal(t, "work/projects", parent.TagName)
+	require.Equal(t, "1", *parent.ParentID)
+
+	// tag can not be moved into itself or its child tags
+	tagName := "work/projects/alpha/work"
+	_, err = storage.UpdateTag(ctx, &dto.TagUpdate{TagName: &tagName}, "1", 0)
+	require.ErrorIs(t, err, errors.ErrTagCycle)
+
+	// renamed tag is moved to new parent with its child tags
+	tagName = "home/projects"
+	_, err = storage.UpdateTag(ctx, &dto.TagUpdate{TagName: &tagName}, "2", 1)
+	require.NoError(t, err)
+
+	tags, err := storage.GetAllTagsByUser(ctx, "1", nil)
+	require.NoError(t, err)
+
+	actual := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		parentID := ""
+		if tag.ParentID != nil {
+			parentID = *tag.ParentID
+		}
+
+		actual[tag.TagName] = parentID
+	}
+
+	require.Equal(t, map[string]string{
+		"work":                "",
+		"home":                "",
+		"home/projects":       "5",
+		"home/projects/alpha": "2",
+		"home/projects/beta":  "2",
+	}, actual)
+
+	alpha, err := storage.GetTagByID(ctx, "3", "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, alpha.Version)
+
+	// tag is deleted with its child tags
+	id, err := storage.DeleteTag(ctx, "2", "1", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, id)
+
+	_, err = storage.GetTagByID(ctx, "3", "1")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+
+	total, err := storage.CountTagsByUser(ctx, "1", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+}
+
+func TestTagStorage_GetTagTree(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2", "test_title3"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewTagStorage(db.Client, 0)
+	ctx := context.Background()
+
+	for _, name := range []string{"work/projects", "home"} {
+		_, err := storage.CreateTag(ctx, &model.Tag{TagName: name}, "1")
+		require.NoError(t, err)
+	}
+
+	// note 1 has work and work/projects, notes 2 and 3 have work/projects
+	db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1), (1, 2), (2, 2), (3, 2)")
+
+	tags, err := storage.GetTagTree(ctx, "1")
+	require.NoError(t, err)
+
+	parentID := "1"
+	expected := []dto.TagTreeResp{
+		{ID: "3", TagName: "home"},
+		{ID: "1", TagName: "work", NoteCount: 1, TotalCount: 3},
+		{ID: "2", TagName: "work/projects", ParentID: &parentID, NoteCount: 3, TotalCount: 3},
+	}
+	require.Equal(t, expected, tags)
+}
+
+func TestTagStorage_MergeTags(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2", "test_title3"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewTagStorage(db.Client, 0)
+	ctx := context.Background()
+
+	for _, name := range []string{"todo", "TODO", "to-do/urgent", "other"} {
+		_, err := storage.CreateTag(ctx, &model.Tag{TagName: name}, "1")
+		require.NoError(t, err)
+	}
+
+	// note 1 has todo and TODO, note 2 has TODO and to-do, note 3 has to-do/urgent
+	db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 1), (1, 2), (2, 2), (2, 3), (3, 4)")
+
+	_, tagID, err := storage.MergeTags(ctx, "2", &dto.TagMerge{SourceIDs: []int64{2}, TargetID: 1})
+	require.ErrorIs(t, err, errors.ErrNotFound)
+	require.Equal(t, "1", tagID)
+
+	_, _, err = storage.MergeTags(ctx, "1", &dto.TagMerge{SourceIDs: []int64{3}, TargetID: 4})
+	require.ErrorIs(t, err, errors.ErrTagCycle)
+
+	resp, _, err := storage.MergeTags(ctx, "1", &dto.TagMerge{SourceIDs: []int64{3, 2, 3}, TargetID: 1})
+	require.NoError(t, err)
+	require.Equal(t, &dto.TagMergeResp{TargetID: 1, Merged: []int64{2, 3}, MovedLinks: 1}, resp)
+
+	tags, err := storage.GetAllTagsByUser(ctx, "1", &dto.ListQuery{})
+	require.NoError(t, err)
+
+	parentID := "1"
+	names := make(map[string]*string, len(tags))
+	for _, tag := range tags {
+		names[tag.TagName] = tag.ParentID
+	}
+	require.Equal(t, map[string]*string{"todo": nil, "todo/urgent": &parentID, "other": nil}, names)
+
+	var notes []int64
+	require.NoError(t, db.Client.Select(&notes, "SELECT note_id FROM notes_tags WHERE tag_id=1 ORDER BY note_id"))
+	require.Equal(t, []int64{1, 2}, notes)
+}
+
+func TestTagStorage_MergeTagsOverlapping(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2", "test_title3"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewTagStorage(db.Client, 0)
+	ctx := context.Background()
+
+	// TODO is 1, TODO/x is 2, todo is 3, todo/x is 4, todo/x/deep is 5, todo/y is 6
+	for _, name := range []string{"TODO/x", "todo/x/deep", "todo/y"} {
+		_, err := storage.CreateTag(ctx, &model.Tag{TagName: name}, "1")
+		require.NoError(t, err)
+	}
+
+	// note 1 has todo/x, note 2 has TODO/x and todo/x, note 3 has todo/x/deep and todo/y
+	db.Client.MustExec("INSERT INTO notes_tags (note_id, tag_id) VALUES (1, 4), (2, 2), (2, 4), (3, 5), (3, 6)")
+
+	// todo/y is nested source, todo/x collides with existing TODO/x
+	resp, _, err := storage.MergeTags(ctx, "1", &dto.TagMerge{SourceIDs: []int64{3, 6}, TargetID: 1})
+	require.NoError(t, err)
+	require.Equal(t, &dto.TagMergeResp{TargetID: 1, Merged: []int64{3, 6}, MovedLinks: 1}, resp)
+
+	tags, err := storage.GetAllTagsByUser(ctx, "1", &dto.ListQuery{})
+	require.NoError(t, err)
+
+	ids := make(map[string]string, len(tags))
+	parents := make(map[string]*string, len(tags))
+	for _, tag := range tags {
+		ids[tag.TagName] = tag.ID
+		parents[tag.TagName] = tag.ParentID
+	}
+	require.Equal(t, map[string]string{"TODO": "1", "TODO/x": "2", "TODO/x/deep": "5"}, ids)
+	require.Nil(t, parents["TODO"])
+	require.Equal(t, "1", *parents["TODO/x"])
+	require.Equal(t, "2", *parents["TODO/x/deep"])
+
+	var links []struct {
+		NoteID int64 `db:"note_id"`
+		TagID  int64 `db:"tag_id"`
+	}
+	require.NoError(t, db.Client.Select(&links, "SELECT note_id, tag_id FROM notes_tags ORDER BY tag_id, note_id"))
+	require.Equal(t, []struct {
+		NoteID int64 `db:"note_id"`
+		TagID  int64 `db:"tag_id"`
+	}{{3, 1}, {1, 2}, {2, 2}, {3, 5}}, links)
+}
+
+func TestTagStorage_GetTagStats(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	for _, title := range []string{"test_title1", "test_title2"} {
+		if err := db.InsertTestNote(&model.Note{Title: title, Info: "test_info"}, "1"); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	storage := NewTagStorage(db.Client, 0)
+	noteStorage := NewNoteStorage(db.Client, 0)
+	ctx := context.Background()
+
+	for _, name := range []string{"work", "home"} {
+		_, err := storage.CreateTag(ctx, &model.Tag{TagName: name}, "1")
+		require.NoError(t, err)
+	}
+
+	before := time.Now().Add(-time.Second)
+
+	for _, noteID := range []string{"1", "2"} {
+		_, _, err := noteStorage.UpdateNoteTags(ctx, noteID, "1", &dto.NoteTagsUpdate{Add: []int64{1}})
+		require.NoError(t, err)
+	}
+
+	// notes in trash are not counted
+	db.Client.MustExec("UPDATE notes SET deleted_at=CURRENT_TIMESTAMP WHERE id=2")
+
+	stats, err := storage.GetTagStats(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	require.Equal(t, dto.TagStatsResp{ID: "2", TagName: "home"}, stats[0])
+
+	require.Equal(t, "work", stats[1].TagName)
+	require.Equal(t, 1, stats[1].NoteCount)
+	require.NotNil(t, stats[1].LastUsedAt)
+	require.True(t, stats[1].LastUsedAt.After(before))
+}
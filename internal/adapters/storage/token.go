@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+)
+
+// tokenStorage token storage struct.
+type tokenStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewTokenStorage token storage func builder.
+func NewTokenStorage(db *sqlx.DB, queryTimeout time.Duration) TokenStorage {
+	return &tokenStorage{db: db, timeout: queryTimeout}
+}
+
+// CreateRefreshToken insert refresh token in DB.
+func (t *tokenStorage) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	return dbError(insertRefreshToken(ctx, t.db, token))
+}
+
+// GetRefreshToken get refresh token by hash from DB.
+func (t *tokenStorage) GetRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var token model.RefreshToken
+
+	query := fmt.Sprintf("SELECT id, user_id, token_hash, access_jti, access_expires_at, expires_at, revoked"+
+		" FROM %s WHERE token_hash=$1", dictionary.RefreshTokensTable)
+	if err := t.db.GetContext(ctx, &token, query, tokenHash); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken revoke old refresh token and insert new one in one transaction.
+// Returns errors.ErrNotFound if old token is already revoked.
+func (t *tokenStorage) RotateRefreshToken(ctx context.Context, oldID string, newToken *model.RefreshToken) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := fmt.Sprintf("UPDATE %s SET revoked=true WHERE id=$1 AND revoked=false", dictionary.RefreshTokensTable)
+
+	res, err := tx.ExecContext(ctx, query, oldID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+
+	if rows == 0 {
+		return dbError(sql.ErrNoRows)
+	}
+
+	if err := insertRefreshToken(ctx, tx, newToken); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// RevokeSession revoke refresh token issued with access token and access token itself.
+func (t *tokenStorage) RevokeSession(ctx context.Context, accessJTI string, expiresAt time.Time) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := fmt.Sprintf("UPDATE %s SET revoked=true WHERE access_jti=$1", dictionary.RefreshTokensTable)
+	if _, err := tx.ExecContext(ctx, query, accessJTI); err != nil {
+		return dbError(err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (jti, expires_at) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		dictionary.RevokedTokensTable)
+	if _, err := tx.ExecContext(ctx, query, accessJTI, expiresAt); err != nil {
+		return dbError(err)
+	}
+
+	// revoked list is needed only while access tokens are alive
+	query = fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", dictionary.RevokedTokensTable)
+	if _, err := tx.ExecContext(ctx, query, time.Now().UTC()); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// RevokeAllSessions revoke all refresh tokens of user and all alive access tokens issued with them.
+func (t *tokenStorage) RevokeAllSessions(ctx context.Context, userID string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := fmt.Sprintf("INSERT INTO %s (jti, expires_at)"+
+		" SELECT access_jti, access_expires_at FROM %s WHERE user_id=$1 AND access_expires_at > $2"+
+		" ON CONFLICT DO NOTHING", dictionary.RevokedTokensTable, dictionary.RefreshTokensTable)
+	if _, err := tx.ExecContext(ctx, query, userID, time.Now().UTC()); err != nil {
+		return dbError(err)
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET revoked=true WHERE user_id=$1", dictionary.RefreshTokensTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// IsTokenRevoked check access token id in revoked list.
+func (t *tokenStorage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var count int
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE jti=$1", dictionary.RevokedTokensTable)
+	if err := t.db.GetContext(ctx, &count, query, jti); err != nil {
+		return false, dbError(err)
+	}
+
+	return count > 0, nil
+}
+
+// insertRefreshToken insert refresh token with db or tx.
+func insertRefreshToken(ctx context.Context, q sqlx.QueryerContext, token *model.RefreshToken) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, access_jti, access_expires_at, expires_at)"+
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id", dictionary.RefreshTokensTable)
+
+	return q.QueryRowxContext(ctx, query,
+		token.UserID,
+		token.TokenHash,
+		token.AccessJTI,
+		token.AccessExpiresAt,
+		token.ExpiresAt,
+	).Scan(&token.ID)
+}
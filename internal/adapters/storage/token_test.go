@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/pkg/database"
+)
+
+func TestTokenStorage_CreateRefreshToken(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		token    *model.RefreshToken
+		expected *model.RefreshToken
+		err      error
+		testName string
+	}{
+		{
+			token: &model.RefreshToken{
+				UserID:          "1",
+				TokenHash:       "hash",
+				AccessJTI:       "jti",
+				AccessExpiresAt: now.Add(time.Minute),
+				ExpiresAt:       now.Add(time.Hour),
+			},
+			expected: &model.RefreshToken{
+				ID:              "1",
+				UserID:          "1",
+				TokenHash:       "hash",
+				AccessJTI:       "jti",
+				AccessExpiresAt: now.Add(time.Minute),
+				ExpiresAt:       now.Add(time.Hour),
+			},
+			testName: "Test-1-OK",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTokenStorage(db.Client, 0)
+
+			err := storage.CreateRefreshToken(context.Background(), testCase.token)
+			require.NoError(t, testCase.err, err)
+
+			actual, err := storage.GetRefreshToken(context.Background(), testCase.token.TokenHash)
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestTokenStorage_RotateRefreshToken(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		oldRevoked bool
+		expected   error
+		testName   string
+	}{
+		{
+			oldRevoked: false,
+			expected:   nil,
+			testName:   "Test-1-OK",
+		},
+		{
+			oldRevoked: true,
+			expected:   sql.ErrNoRows,
+			testName:   "Test-2-Already rotated",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTokenStorage(db.Client, 0)
+
+			oldToken := &model.RefreshToken{UserID: "1", TokenHash: "old", AccessJTI: "jti1", AccessExpiresAt: now, ExpiresAt: now}
+			if err := storage.CreateRefreshToken(context.Background(), oldToken); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			if testCase.oldRevoked {
+				if err := storage.RevokeAllSessions(context.Background(), "1"); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			newToken := &model.RefreshToken{UserID: "1", TokenHash: "new", AccessJTI: "jti2", AccessExpiresAt: now, ExpiresAt: now}
+			actual := storage.RotateRefreshToken(context.Background(), oldToken.ID, newToken)
+			require.ErrorIs(t, actual, testCase.expected)
+
+			old, err := storage.GetRefreshToken(context.Background(), "old")
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			require.True(t, old.Revoked)
+		})
+	}
+}
+
+func TestTokenStorage_RevokeSession(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		accessJTI string
+		checkJTI  string
+		expected  bool
+		testName  string
+	}{
+		{
+			accessJTI: "jti",
+			checkJTI:  "jti",
+			expected:  true,
+			testName:  "Test-1-Revoked",
+		},
+		{
+			accessJTI: "jti",
+			checkJTI:  "other_jti",
+			expected:  false,
+			testName:  "Test-2-Not revoked",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTokenStorage(db.Client, 0)
+
+			token := &model.RefreshToken{UserID: "1", TokenHash: "hash", AccessJTI: testCase.accessJTI, AccessExpiresAt: now, ExpiresAt: now}
+			if err := storage.CreateRefreshToken(context.Background(), token); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			err := storage.RevokeSession(context.Background(), testCase.accessJTI, now.Add(time.Minute))
+			require.NoError(t, err)
+
+			actual, err := storage.IsTokenRevoked(context.Background(), testCase.checkJTI)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+
+			session, err := storage.GetRefreshToken(context.Background(), "hash")
+			require.NoError(t, err)
+			require.True(t, session.Revoked)
+		})
+	}
+}
+
+func TestTokenStorage_RevokeAllSessions(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testTable := []struct {
+		tokens   []*model.RefreshToken
+		expected map[string]bool
+		testName string
+	}{
+		{
+			tokens: []*model.RefreshToken{
+				{UserID: "1", TokenHash: "hash1", AccessJTI: "alive", AccessExpiresAt: now.Add(time.Minute), ExpiresAt: now.Add(time.Hour)},
+				{UserID: "1", TokenHash: "hash2", AccessJTI: "expired", AccessExpiresAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+			},
+			expected: map[string]bool{
+				"alive":   true,
+				"expired": false,
+			},
+			testName: "Test-1-OK",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTokenStorage(db.Client, 0)
+
+			for _, token := range testCase.tokens {
+				if err := storage.CreateRefreshToken(context.Background(), token); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			err := storage.RevokeAllSessions(context.Background(), "1")
+			require.NoError(t, err)
+
+			for jti, expected := range testCase.expected {
+				actual, err := storage.IsTokenRevoked(context.Background(), jti)
+				require.NoError(t, err)
+				require.Equal(t, expected, actual)
+			}
+
+			for _, token := range testCase.tokens {
+				session, err := storage.GetRefreshToken(context.Background(), token.TokenHash)
+				require.NoError(t, err)
+				require.True(t, session.Revoked)
+			}
+		})
+	}
+}
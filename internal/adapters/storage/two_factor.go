@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+)
+
+// twoFactorStorage two-factor auth storage struct.
+type twoFactorStorage struct {
+	db      *sqlx.DB
+	timeout time.Duration
+}
+
+// NewTwoFactorStorage two-factor auth storage func builder.
+func NewTwoFactorStorage(db *sqlx.DB, queryTimeout time.Duration) TwoFactorStorage {
+	return &twoFactorStorage{db: db, timeout: queryTimeout}
+}
+
+// GetTwoFactor get user TOTP settings from DB.
+func (t *twoFactorStorage) GetTwoFactor(ctx context.Context, userID string) (*model.TwoFactor, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var twoFactor model.TwoFactor
+
+	query := fmt.Sprintf("SELECT id, username, totp_secret, totp_enabled, totp_last_step FROM %s WHERE id=$1",
+		dictionary.UsersTable)
+	if err := t.db.GetContext(ctx, &twoFactor, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &twoFactor, nil
+}
+
+// SetSecret save not confirmed TOTP secret of user in DB.
+func (t *twoFactorStorage) SetSecret(ctx context.Context, userID, secret string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET totp_secret=$1, totp_enabled=false, totp_last_step=0 WHERE id=$2",
+		dictionary.UsersTable)
+	_, err := t.db.ExecContext(ctx, query, secret, userID)
+
+	return dbError(err)
+}
+
+// Enable enable TOTP of user and replace recovery codes in one transaction.
+func (t *twoFactorStorage) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := fmt.Sprintf("UPDATE %s SET totp_enabled=true, totp_last_step=$1 WHERE id=$2", dictionary.UsersTable)
+	if _, err := tx.ExecContext(ctx, query, step, userID); err != nil {
+		return dbError(err)
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// Disable remove TOTP secret and recovery codes of user in one transaction.
+func (t *twoFactorStorage) Disable(ctx context.Context, userID string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := fmt.Sprintf("UPDATE %s SET totp_secret=NULL, totp_enabled=false, totp_last_step=0 WHERE id=$1",
+		dictionary.UsersTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return dbError(err)
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, nil); err != nil {
+		return dbError(err)
+	}
+
+	return dbError(tx.Commit())
+}
+
+// UseStep save last used TOTP step. Returns false if the step (or a later one) was already used.
+func (t *twoFactorStorage) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET totp_last_step=$1 WHERE id=$2 AND totp_last_step < $1", dictionary.UsersTable)
+
+	return execAffected(ctx, t.db, query, step, userID)
+}
+
+// UseRecoveryCode mark recovery code as used. Returns false if there is no such unused code.
+func (t *twoFactorStorage) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET used=true WHERE user_id=$1 AND code_hash=$2 AND used=false",
+		dictionary.RecoveryCodesTable)
+
+	return execAffected(ctx, t.db, query, userID, codeHash)
+}
+
+// CreateChallenge insert login challenge in DB.
+func (t *twoFactorStorage) CreateChallenge(ctx context.Context, challenge *model.TwoFactorChallenge) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id",
+		dictionary.TwoFactorChallengesTable)
+
+	row := t.db.QueryRowContext(ctx, query, challenge.UserID, challenge.TokenHash, challenge.ExpiresAt)
+
+	return dbError(row.Scan(&challenge.ID))
+}
+
+// GetChallenge get login challenge by hash with username of its user from DB.
+func (t *twoFactorStorage) GetChallenge(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error) {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	var challenge model.TwoFactorChallenge
+
+	query := fmt.Sprintf("SELECT c.id, c.user_id, u.username, c.token_hash, c.attempts, c.expires_at"+
+		" FROM %s c JOIN %s u ON u.id=c.user_id WHERE c.token_hash=$1",
+		dictionary.TwoFactorChallengesTable, dictionary.UsersTable)
+	if err := t.db.GetContext(ctx, &challenge, query, tokenHash); err != nil {
+		return nil, dbError(err)
+	}
+
+	return &challenge, nil
+}
+
+// AddChallengeAttempt increment failed attempts of login challenge.
+func (t *twoFactorStorage) AddChallengeAttempt(ctx context.Context, challengeID string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("UPDATE %s SET attempts=attempts+1 WHERE id=$1", dictionary.TwoFactorChallengesTable)
+	_, err := t.db.ExecContext(ctx, query, challengeID)
+
+	return dbError(err)
+}
+
+// DeleteChallenge delete login challenge from DB.
+func (t *twoFactorStorage) DeleteChallenge(ctx context.Context, challengeID string) error {
+	ctx, cancel := queryContext(ctx, t.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", dictionary.TwoFactorChallengesTable)
+	_, err := t.db.ExecContext(ctx, query, challengeID)
+
+	return dbError(err)
+}
+
+// replaceRecoveryCodes delete all recovery codes of user and insert new ones with tx.
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id=$1", dictionary.RecoveryCodesTable)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (user_id, code_hash) VALUES ($1, $2)", dictionary.RecoveryCodesTable)
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// execAffected exec query and report whether any row was changed.
+func execAffected(ctx context.Context, e sqlx.ExecerContext, query string, args ...interface{}) (bool, error) {
+	res, err := e.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, dbError(err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, dbError(err)
+	}
+
+	return rows > 0, nil
+}
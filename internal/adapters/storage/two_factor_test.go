@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/domain/entities/model"
+	"web/pkg/database"
+)
+
+func TestTwoFactorStorage_Enable(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		secret   string
+		step     int64
+		codes    []string
+		expected *model.TwoFactor
+		testName string
+	}{
+		{
+			secret: "JBSWY3DPEHPK3PXP",
+			step:   100,
+			codes:  []string{"hash1", "hash2"},
+			expected: &model.TwoFactor{
+				UserID:   "1",
+				Username: "test_name",
+				Secret:   sql.NullString{String: "JBSWY3DPEHPK3PXP", Valid: true},
+				Enabled:  true,
+				LastStep: 100,
+			},
+			testName: "Test-1-OK",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTwoFactorStorage(db.Client, 0)
+
+			err := storage.SetSecret(context.Background(), "1", testCase.secret)
+			require.NoError(t, err)
+
+			err = storage.Enable(context.Background(), "1", testCase.step, testCase.codes)
+			require.NoError(t, err)
+
+			actual, err := storage.GetTwoFactor(context.Background(), "1")
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestTwoFactorStorage_UseStep(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		step     int64
+		expected bool
+		testName string
+	}{
+		{
+			step:     101,
+			expected: true,
+			testName: "Test-1-New step",
+		},
+		{
+			step:     100,
+			expected: false,
+			testName: "Test-2-Reused step",
+		},
+		{
+			step:     99,
+			expected: false,
+			testName: "Test-3-Old step",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTwoFactorStorage(db.Client, 0)
+
+			if err := storage.SetSecret(context.Background(), "1", "JBSWY3DPEHPK3PXP"); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			if err := storage.Enable(context.Background(), "1", 100, nil); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			actual, err := storage.UseStep(context.Background(), "1", testCase.step)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestTwoFactorStorage_UseRecoveryCode(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	testTable := []struct {
+		codes    []string
+		expected []bool
+		testName string
+	}{
+		{
+			codes:    []string{"hash1", "hash1"},
+			expected: []bool{true, false},
+			testName: "Test-1-Single use",
+		},
+		{
+			codes:    []string{"unknown"},
+			expected: []bool{false},
+			testName: "Test-2-Unknown code",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			storage := NewTwoFactorStorage(db.Client, 0)
+
+			if err := storage.Enable(context.Background(), "1", 0, []string{"hash1", "hash2"}); err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			for i, code := range testCase.codes {
+				actual, err := storage.UseRecoveryCode(context.Background(), "1", code)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expected[i], actual)
+			}
+		})
+	}
+}
+
+func TestTwoFactorStorage_Disable(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewTwoFactorStorage(db.Client, 0)
+
+	if err := storage.SetSecret(context.Background(), "1", "JBSWY3DPEHPK3PXP"); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	if err := storage.Enable(context.Background(), "1", 100, []string{"hash1"}); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	err := storage.Disable(context.Background(), "1")
+	require.NoError(t, err)
+
+	actual, err := storage.GetTwoFactor(context.Background(), "1")
+	require.NoError(t, err)
+	require.Equal(t, &model.TwoFactor{UserID: "1", Username: "test_name"}, actual)
+
+	used, err := storage.UseRecoveryCode(context.Background(), "1", "hash1")
+	require.NoError(t, err)
+	require.False(t, used)
+}
+
+func TestTwoFactorStorage_Challenge(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	db.SetUp()
+	defer db.TearDown()
+
+	if err := db.InsertTestUser(&model.User{Username: "test_name", Password: "test_password"}); err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	storage := NewTwoFactorStorage(db.Client, 0)
+	expiresAt := time.Now().UTC().Truncate(time.Second).Add(time.Minute)
+
+	challenge := &model.TwoFactorChallenge{UserID: "1", TokenHash: "hash", ExpiresAt: expiresAt}
+	err := storage.CreateChallenge(context.Background(), challenge)
+	require.NoError(t, err)
+
+	err = storage.AddChallengeAttempt(context.Background(), challenge.ID)
+	require.NoError(t, err)
+
+	actual, err := storage.GetChallenge(context.Background(), "hash")
+	require.NoError(t, err)
+	require.Equal(t, &model.TwoFactorChallenge{
+		ID:        "1",
+		UserID:    "1",
+		Username:  "test_name",
+		TokenHash: "hash",
+		Attempts:  1,
+		ExpiresAt: expiresAt,
+	}, actual)
+
+	err = storage.DeleteChallenge(context.Background(), challenge.ID)
+	require.NoError(t, err)
+
+	_, err = storage.GetChallenge(context.Background(), "hash")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -1,52 +1,67 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	"web/internal/domain/entities/dto"
 	"web/internal/utils/dictionary"
-	"web/internal/utils/functions"
 )
 
 // userStorage user storage struct.
 type userStorage struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	timeout time.Duration
 }
 
 // NewUserStorage user storage func builder.
-func NewUserStorage(db *sqlx.DB) UserStorage {
-	return &userStorage{db: db}
+func NewUserStorage(db *sqlx.DB, queryTimeout time.Duration) UserStorage {
+	return &userStorage{db: db, timeout: queryTimeout}
 }
 
 // GetUserByID get user by id from DB.
-func (u *userStorage) GetUserByID(id string) (*dto.UserResp, error) {
+func (u *userStorage) GetUserByID(ctx context.Context, id string) (*dto.UserResp, error) {
+	ctx, cancel := queryContext(ctx, u.timeout)
+	defer cancel()
+
 	var user dto.UserResp
 
-	query := fmt.Sprintf("SELECT id, username FROM %s WHERE id=$1", dictionary.UsersTable)
-	if err := u.db.Get(&user, query, id); err != nil {
-		return nil, err
+	query := fmt.Sprintf("SELECT id, username, COALESCE(email, '') AS email, role, created_at, updated_at"+
+		" FROM %s WHERE id=$1", dictionary.UsersTable)
+	if err := u.db.GetContext(ctx, &user, query, id); err != nil {
+		return nil, dbError(err)
 	}
 
 	return &user, nil
 }
 
-// GetAllUsers get all users from DB.
-func (u *userStorage) GetAllUsers() ([]dto.UserResp, error) {
+// GetAllUsers get all users from DB by list query filters.
+func (u *userStorage) GetAllUsers(ctx context.Context, listQuery *dto.ListQuery) ([]dto.UserResp, error) {
+	ctx, cancel := queryContext(ctx, u.timeout)
+	defer cancel()
+
 	var users []dto.UserResp
 
-	query := fmt.Sprintf("SELECT id, username FROM %s", dictionary.UsersTable)
-	if err := u.db.Select(&users, query); err != nil {
-		return nil, err
+	conditions, args := listConditions(listQuery, nil, nil)
+
+	query := fmt.Sprintf("SELECT id, username, COALESCE(email, '') AS email, role, created_at, updated_at FROM %s%s",
+		dictionary.UsersTable, whereClause(conditions))
+	if err := u.db.SelectContext(ctx, &users, query, args...); err != nil {
+		return nil, dbError(err)
 	}
 
 	return users, nil
 }
 
 // UpdateUser update user by id in DB.
-func (u *userStorage) UpdateUser(newUser *dto.UserUpdate, userID string) error {
+func (u *userStorage) UpdateUser(ctx context.Context, newUser *dto.UserUpdate, userID string) error {
+	ctx, cancel := queryContext(ctx, u.timeout)
+	defer cancel()
+
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argID := 1
@@ -59,26 +74,39 @@ func (u *userStorage) UpdateUser(newUser *dto.UserUpdate, userID string) error {
 
 	if newUser.Password != nil {
 		setValues = append(setValues, fmt.Sprintf("password=$%d", argID))
-		args = append(args, functions.GeneratePasswordHash(*newUser.Password))
+		args = append(args, *newUser.Password)
 		argID++
 	}
 
+	if newUser.Role != nil {
+		setValues = append(setValues, fmt.Sprintf("role=$%d", argID))
+		args = append(args, *newUser.Role)
+		argID++
+	}
+
+	setValues = append(setValues, fmt.Sprintf("updated_at=$%d", argID))
+	args = append(args, timestamp())
+	argID++
+
 	setQuery := strings.Join(setValues, ", ")
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", dictionary.UsersTable, setQuery, argID)
 	args = append(args, userID)
 
-	_, err := u.db.Exec(query, args...)
+	_, err := u.db.ExecContext(ctx, query, args...)
 
-	return err
+	return dbError(err)
 }
 
 // DeleteUser delete user by id from DB.
-func (u *userStorage) DeleteUser(userID string) (int, error) {
+func (u *userStorage) DeleteUser(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := queryContext(ctx, u.timeout)
+	defer cancel()
+
 	var id int
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 RETURNING id", dictionary.UsersTable)
-	if err := u.db.QueryRow(query, userID).Scan(&id); err != nil {
-		return 0, err
+	if err := u.db.QueryRowContext(ctx, query, userID).Scan(&id); err != nil {
+		return 0, dbError(err)
 	}
 
 	return id, nil
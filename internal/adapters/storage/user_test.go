@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -29,8 +31,11 @@ func TestUserStorage_GetUserByID(t *testing.T) {
 			},
 			userID: "1",
 			expected: &dto.UserResp{
-				ID:       "1",
-				Username: "test_name",
+				ID:        "1",
+				Username:  "test_name",
+				Role:      "user",
+				CreatedAt: epoch,
+				UpdatedAt: epoch,
 			},
 			err:      nil,
 			testName: "Test-1-OK",
@@ -46,9 +51,9 @@ func TestUserStorage_GetUserByID(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewUserStorage(db.Client)
+			storage := NewUserStorage(db.Client, 0)
 
-			actual, err := storage.GetUserByID(testCase.userID)
+			actual, err := storage.GetUserByID(context.Background(), testCase.userID)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -76,8 +81,11 @@ func TestUserStorage_GetAllUsers(t *testing.T) {
 			},
 			expected: []dto.UserResp{
 				{
-					ID:       "1",
-					Username: "test_name",
+					ID:        "1",
+					Username:  "test_name",
+					Role:      "user",
+					CreatedAt: epoch,
+					UpdatedAt: epoch,
 				},
 			},
 			err:      nil,
@@ -94,9 +102,9 @@ func TestUserStorage_GetAllUsers(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewUserStorage(db.Client)
+			storage := NewUserStorage(db.Client, 0)
 
-			actual, err := storage.GetAllUsers()
+			actual, err := storage.GetAllUsers(context.Background(), nil)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -107,6 +115,66 @@ func TestUserStorage_GetAllUsers(t *testing.T) {
 	}
 }
 
+func TestUserStorage_GetAllUsersFilters(t *testing.T) {
+	db := database.NewTestDBClient()
+	defer db.Close()
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		listQuery   *dto.ListQuery
+		expectedIDs []string
+		testName    string
+	}{
+		{
+			listQuery:   &dto.ListQuery{},
+			expectedIDs: []string{"1", "2", "3"},
+			testName:    "Test-1-No filters",
+		},
+		{
+			listQuery:   &dto.ListQuery{CreatedAfter: &day},
+			expectedIDs: []string{"3"},
+			testName:    "Test-2-Created after",
+		},
+		{
+			listQuery:   &dto.ListQuery{UpdatedSince: &day},
+			expectedIDs: []string{"2", "3"},
+			testName:    "Test-3-Updated since",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			db.SetUp()
+			defer db.TearDown()
+
+			for _, userName := range []string{"old_name", "edited_name", "new_name"} {
+				if err := db.InsertTestUser(&model.User{Username: userName, Password: "test_password"}); err != nil {
+					log.Fatalln(err.Error())
+				}
+			}
+
+			// first user is untouched since the day, second one was edited after it, third one was created after it
+			db.Client.MustExec("UPDATE users SET created_at=$1, updated_at=$1 WHERE id=1", day.Add(-time.Hour))
+			db.Client.MustExec("UPDATE users SET created_at=$1, updated_at=$2 WHERE id=2",
+				day.Add(-time.Hour), day.Add(time.Hour))
+			db.Client.MustExec("UPDATE users SET created_at=$1, updated_at=$1 WHERE id=3", day.Add(time.Hour))
+
+			storage := NewUserStorage(db.Client, 0)
+
+			actual, err := storage.GetAllUsers(context.Background(), testCase.listQuery)
+			require.NoError(t, err)
+
+			actualIDs := make([]string, 0, len(actual))
+			for _, user := range actual {
+				actualIDs = append(actualIDs, user.ID)
+			}
+
+			require.Equal(t, testCase.expectedIDs, actualIDs)
+		})
+	}
+}
+
 func TestUserStorage_UpdateUser(t *testing.T) {
 	db := database.NewTestDBClient()
 	defer db.Close()
@@ -145,9 +213,9 @@ func TestUserStorage_UpdateUser(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewUserStorage(db.Client)
+			storage := NewUserStorage(db.Client, 0)
 
-			actual := storage.UpdateUser(testCase.newUser, testCase.userID)
+			actual := storage.UpdateUser(context.Background(), testCase.newUser, testCase.userID)
 
 			require.NoError(t, testCase.expected, actual)
 		})
@@ -186,9 +254,9 @@ func TestUserStorage_DeleteUser(t *testing.T) {
 				log.Fatalln(err.Error())
 			}
 
-			storage := NewUserStorage(db.Client)
+			storage := NewUserStorage(db.Client, 0)
 
-			actual, err := storage.DeleteUser(testCase.userID)
+			actual, err := storage.DeleteUser(context.Background(), testCase.userID)
 			if err != nil {
 				log.Fatalln(err.Error())
 			}
@@ -4,18 +4,25 @@ package config
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 // Config struct of config APP.
 type Config struct {
-	Postgres `yaml:"postgres"`
-	App      `yaml:"app"`
-	Logger   `yaml:"logger"`
+	Postgres  `yaml:"postgres"`
+	App       `yaml:"app"`
+	Logger    `yaml:"logger"`
+	Hasher    `yaml:"hasher"`
+	Token     `yaml:"token"`
+	JWT       `yaml:"jwt"`
+	TwoFactor `yaml:"twoFactor"`
+	Mail      `yaml:"mail"`
+	Notes     `yaml:"notes"`
 }
 
-// Postgres Db config.
+// Postgres Db config. QueryTimeout is deadline of DB queries of one storage call.
 type Postgres struct {
 	Host     string `yaml:"host" env:"PSQL_HOST" env-default:"localhost"`
 	Port     string `yaml:"port" env:"PSQL_PORT" env-default:"5432"`
@@ -23,14 +30,26 @@ type Postgres struct {
 	Password string `yaml:"password" env:"PSQL_PASSWORD" env-default:"pass"`
 	DBName   string `yaml:"dbName" env:"PSQL_DB_NAME" env-default:"crud"`
 	SSLMode  string `yaml:"sslMode" env:"PSQL_SSL_MODE" env-default:"disable"`
+
+	QueryTimeout time.Duration `yaml:"queryTimeout" env:"PSQL_QUERY_TIMEOUT" env-default:"5s"`
 }
 
-// App config.
+// App config. Login* params are brute-force protection thresholds of /login:
+// after LoginBackoffAfter failures every next attempt waits twice longer starting from LoginBackoffBase,
+// after LoginLockoutAfter failures username is locked for LoginLockoutDuration. LoginIP* are the same per client IP.
 type App struct {
 	Port           string `yaml:"port" env:"APP_PORT" env-default:"8000"`
 	MaxHeaderBytes int    `yaml:"maxHeaderBytes" env:"MAX_HEADER_BYTES" env-default:"20"`
 	WriteTimeout   int    `yaml:"writeTimeout" env:"APP_WRITE_TIMEOUT" env-default:"10"`
 	ReadTimeout    int    `yaml:"readTimeout" env:"APP_READ_TIMEOUT" env-default:"10"`
+	TrustRealIP    bool   `yaml:"trustRealIP" env:"APP_TRUST_REAL_IP" env-default:"false"`
+
+	LoginBackoffAfter    int           `yaml:"loginBackoffAfter" env:"LOGIN_BACKOFF_AFTER" env-default:"3"`
+	LoginBackoffBase     time.Duration `yaml:"loginBackoffBase" env:"LOGIN_BACKOFF_BASE" env-default:"1s"`
+	LoginLockoutAfter    int           `yaml:"loginLockoutAfter" env:"LOGIN_LOCKOUT_AFTER" env-default:"10"`
+	LoginLockoutDuration time.Duration `yaml:"loginLockoutDuration" env:"LOGIN_LOCKOUT_DURATION" env-default:"15m"`
+	LoginIPBackoffAfter  int           `yaml:"loginIPBackoffAfter" env:"LOGIN_IP_BACKOFF_AFTER" env-default:"10"`
+	LoginIPLockoutAfter  int           `yaml:"loginIPLockoutAfter" env:"LOGIN_IP_LOCKOUT_AFTER" env-default:"50"`
 }
 
 // Logger config.
@@ -38,6 +57,71 @@ type Logger struct {
 	LogLevel int `yaml:"logLevel" env:"LOG_LEVEL" env-default:"0"`
 }
 
+// Hasher password hashing config.
+type Hasher struct {
+	Algorithm     string `yaml:"algorithm" env:"HASHER_ALGORITHM" env-default:"argon2id"`
+	BcryptCost    int    `yaml:"bcryptCost" env:"HASHER_BCRYPT_COST" env-default:"10"`
+	Argon2Time    int    `yaml:"argon2Time" env:"HASHER_ARGON2_TIME" env-default:"1"`
+	Argon2Memory  int    `yaml:"argon2Memory" env:"HASHER_ARGON2_MEMORY" env-default:"65536"`
+	Argon2Threads int    `yaml:"argon2Threads" env:"HASHER_ARGON2_THREADS" env-default:"4"`
+}
+
+// Token JWT access and refresh tokens config.
+type Token struct {
+	AccessTTL  time.Duration `yaml:"accessTTL" env:"TOKEN_ACCESS_TTL" env-default:"15m"`
+	RefreshTTL time.Duration `yaml:"refreshTTL" env:"TOKEN_REFRESH_TTL" env-default:"720h"`
+}
+
+// JWT signing keys config. Keys are PEM (HMAC secret for HS256) from env or from files.
+// Verification keys are public keys (kid -> key) kept active during rotation.
+type JWT struct {
+	Algorithm            string            `yaml:"algorithm" env:"JWT_ALGORITHM" env-default:"HS256"`
+	KeyID                string            `yaml:"keyID" env:"JWT_KEY_ID" env-default:"default"`
+	SigningKey           string            `yaml:"signingKey" env:"JWT_SIGNING_KEY"`
+	SigningKeyFile       string            `yaml:"signingKeyFile" env:"JWT_SIGNING_KEY_FILE"`
+	VerificationKeys     map[string]string `yaml:"verificationKeys" env:"JWT_VERIFICATION_KEYS"`
+	VerificationKeyFiles map[string]string `yaml:"verificationKeyFiles" env:"JWT_VERIFICATION_KEY_FILES"`
+}
+
+// TwoFactor TOTP two-factor auth config. Skew is the number of 30s steps accepted before and after now.
+type TwoFactor struct {
+	Issuer        string        `yaml:"issuer" env:"TWO_FACTOR_ISSUER" env-default:"note-service"`
+	Skew          int           `yaml:"skew" env:"TWO_FACTOR_SKEW" env-default:"1"`
+	ChallengeTTL  time.Duration `yaml:"challengeTTL" env:"TWO_FACTOR_CHALLENGE_TTL" env-default:"5m"`
+	MaxAttempts   int           `yaml:"maxAttempts" env:"TWO_FACTOR_MAX_ATTEMPTS" env-default:"5"`
+	RecoveryCodes int           `yaml:"recoveryCodes" env:"TWO_FACTOR_RECOVERY_CODES" env-default:"10"`
+}
+
+// Mail outgoing mail config. Sender is "smtp", "file" (writes .eml files to FileDir) or "log" (logs mails
+// without body, links with tokens are not logged). URLs are templates of links in mails, "%s" is replaced by token.
+// Dispatcher claims batch of BatchSize mails for ClaimTimeout, other dispatchers skip them until then.
+type Mail struct {
+	Sender           string        `yaml:"sender" env:"MAIL_SENDER" env-default:"log"`
+	From             string        `yaml:"from" env:"MAIL_FROM" env-default:"no-reply@localhost"`
+	SMTPHost         string        `yaml:"smtpHost" env:"SMTP_HOST"`
+	SMTPPort         string        `yaml:"smtpPort" env:"SMTP_PORT" env-default:"587"`
+	SMTPUsername     string        `yaml:"smtpUsername" env:"SMTP_USERNAME"`
+	SMTPPassword     string        `yaml:"smtpPassword" env:"SMTP_PASSWORD"`
+	FileDir          string        `yaml:"fileDir" env:"MAIL_FILE_DIR" env-default:"mail"`
+	PollInterval     time.Duration `yaml:"pollInterval" env:"MAIL_POLL_INTERVAL" env-default:"5s"`
+	BatchSize        int           `yaml:"batchSize" env:"MAIL_BATCH_SIZE" env-default:"20"`
+	MaxAttempts      int           `yaml:"maxAttempts" env:"MAIL_MAX_ATTEMPTS" env-default:"5"`
+	ClaimTimeout     time.Duration `yaml:"claimTimeout" env:"MAIL_CLAIM_TIMEOUT" env-default:"5m"`
+	ResetPasswordURL string        `yaml:"resetPasswordURL" env:"MAIL_RESET_PASSWORD_URL" env-default:"http://localhost/password/reset?token=%s"` //nolint:lll
+	VerifyEmailURL   string        `yaml:"verifyEmailURL" env:"MAIL_VERIFY_EMAIL_URL" env-default:"http://localhost/email/verify?token=%s"`       //nolint:lll
+	ResetTokenTTL    time.Duration `yaml:"resetTokenTTL" env:"MAIL_RESET_TOKEN_TTL" env-default:"1h"`
+	VerifyTokenTTL   time.Duration `yaml:"verifyTokenTTL" env:"MAIL_VERIFY_TOKEN_TTL" env-default:"48h"`
+}
+
+// Notes notes config. Trashed notes are purged every PurgeInterval when they are in trash longer than TrashRetention,
+// not positive PurgeInterval disables purging.
+// MaxRevisions is the number of revisions kept per note, older ones are deleted on update, 0 keeps all of them.
+type Notes struct {
+	TrashRetention time.Duration `yaml:"trashRetention" env:"NOTES_TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval  time.Duration `yaml:"purgeInterval" env:"NOTES_PURGE_INTERVAL" env-default:"1h"`
+	MaxRevisions   int           `yaml:"maxRevisions" env:"NOTES_MAX_REVISIONS" env-default:"50"`
+}
+
 // GetConfig parse config from YAML.
 func GetConfig() *Config {
 	var once sync.Once
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// AccessTokenReq dto.
+type AccessTokenReq struct {
+	Name      string     `json:"name" validate:"required,max=255"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=notes:read notes:write tags:read tags:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// AccessTokenResp dto.
+type AccessTokenResp struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AccessTokenCreatedResp dto. Token value is shown only once.
+type AccessTokenCreatedResp struct {
+	AccessTokenResp
+	Token string `json:"token"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"web/pkg/tagquery"
+)
+
+// ListQuery dto. Filters of list endpoints, nil filters are not applied.
+// Sort is id, title, created_at or updated_at, empty sort is id. Zero limit means no limit.
+// Tags is tag expression, it filters only notes lists. With TagDescendants tags of expression match their child tags.
+type ListQuery struct {
+	CreatedAfter   *time.Time
+	UpdatedSince   *time.Time
+	TitlePrefix    string
+	Tags           tagquery.Expr
+	TagDescendants bool
+	Sort           string
+	Desc           bool
+	Limit          int
+	Cursor         *Cursor
+}
+
+// Cursor dto. Sort key of the last item of a page, next page starts after it.
+type Cursor struct {
+	Sort  string     `json:"sort"`
+	Desc  bool       `json:"desc,omitempty"`
+	ID    int64      `json:"id"`
+	Title *string    `json:"title,omitempty"`
+	Time  *time.Time `json:"time,omitempty"`
+}
+
+// NotesPage dto.
+type NotesPage struct {
+	Notes      []NotesResp `json:"notes"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Total      int         `json:"total"`
+}
+
+// TagsPage dto.
+type TagsPage struct {
+	Tags       []TagsResp `json:"tags"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Total      int        `json:"total"`
+}
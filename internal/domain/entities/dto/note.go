@@ -1,31 +1,71 @@
 // Package dto Package dto
 package dto
 
+import "time"
+
 // NoteUpdate dto.
 type NoteUpdate struct {
-	Title *string `json:"title"`
+	Title *string `json:"title" validate:"omitempty,min=1,max=255"`
 	Info  *string `json:"info"`
 }
 
-// NoteResp dto.
-type NoteResp struct {
-	Title string `json:"title"`
+// NotePatch dto. Note patched by JSON Merge Patch or JSON Patch, info removed by patch is cleared.
+type NotePatch struct {
+	Title string `json:"title" validate:"required,max=255"`
 	Info  string `json:"info"`
 }
 
+// NoteResp dto. Version is sent in ETag header.
+type NoteResp struct {
+	Title      string    `json:"title"`
+	Info       string    `json:"info"`
+	NotebookID *string   `json:"notebook_id,omitempty" db:"notebook_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Version    int       `json:"-"`
+}
+
 // NotesResp dto.
 type NotesResp struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	Info  string `json:"info"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Info      string    `json:"info"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TrashNoteResp dto. Note in trash, DeletedAt is the time it was trashed.
+type TrashNoteResp struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Info      string    `json:"info"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+// NoteRevisionsResp dto. Revision keeps title and info of note before the update made by author at CreatedAt.
+type NoteRevisionsResp struct {
+	Rev       int       `json:"rev"`
+	Title     string    `json:"title"`
+	AuthorID  string    `json:"author_id" db:"author_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
-// NoteWithTags dto.
-type NoteWithTags struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Info     string `json:"info"`
-	TagsResp `json:"tags"`
+// NoteRevisionResp dto.
+type NoteRevisionResp struct {
+	Rev       int       `json:"rev"`
+	Title     string    `json:"title"`
+	Info      string    `json:"info"`
+	AuthorID  string    `json:"author_id" db:"author_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NoteRevisionDiffResp dto. Diff is unified diff of title and info from revision to another one or current note.
+type NoteRevisionDiffResp struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Diff string `json:"diff"`
 }
 
 // NoteWithTagsResp dto.
@@ -35,3 +75,35 @@ type NoteWithTagsResp struct {
 	Info     string     `json:"info"`
 	TagsResp []TagsResp `json:"tags"`
 }
+
+// NoteSearchQuery dto. Found notes match the text query and have all tags, or their child tags with TagDescendants.
+type NoteSearchQuery struct {
+	Query          string
+	Tags           []string
+	TagDescendants bool
+	Limit          int
+}
+
+// NoteSearchResp dto. Snippet is HTML escaped note info fragment with matches highlighted by <b></b>.
+type NoteSearchResp struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet"`
+	Rank      float64   `json:"rank"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NoteTagsUpdate dto. Set replaces all tags of note, Add and Remove change them, tags are ids of user tags.
+type NoteTagsUpdate struct {
+	Set    *[]int64 `json:"set" validate:"required_without_all=Add Remove,omitempty,max=100,dive,gt=0"`
+	Add    []int64  `json:"add" validate:"excluded_with=Set,max=100,dive,gt=0"`
+	Remove []int64  `json:"remove" validate:"excluded_with=Set,max=100,dive,gt=0"`
+}
+
+// NoteTagsResp dto. Ids of tags, unchanged tags were already in the requested state.
+type NoteTagsResp struct {
+	Added     []int64 `json:"added"`
+	Removed   []int64 `json:"removed"`
+	Unchanged []int64 `json:"unchanged"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// NotebookResp dto. NoteCount is count of notes right in the notebook, notes of child notebooks are not counted.
+type NotebookResp struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  *string   `json:"parent_id" db:"parent_id"`
+	NoteCount int       `json:"note_count" db:"note_count"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NotebookTreeResp dto. Notebook with its child notebooks.
+type NotebookTreeResp struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	NoteCount int                `json:"note_count"`
+	Children  []NotebookTreeResp `json:"children"`
+}
+
+// NotebookUpdate dto. Rename of notebook.
+type NotebookUpdate struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// NotebookMove dto. Notebook without parent is moved to root.
+type NotebookMove struct {
+	ParentID *int64 `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+// NoteNotebookUpdate dto. Note without notebook is removed from its notebook.
+type NoteNotebookUpdate struct {
+	NotebookID *int64 `json:"notebook_id" validate:"omitempty,gt=0"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "web/internal/domain/errors"
+
+// Problem dto. Error response body in RFC 7807 problem details format, Code is stable machine-readable error code.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []errors.FieldError `json:"errors,omitempty"`
+}
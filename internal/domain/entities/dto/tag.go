@@ -1,17 +1,69 @@
 package dto
 
-// TagResp dto.
+import "time"
+
+// TagResp dto. Version is sent in ETag header.
 type TagResp struct {
-	TagName string `json:"tagname"`
+	TagName   string    `json:"tagname"`
+	ParentID  *string   `json:"parent_id,omitempty" db:"parent_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Version   int       `json:"-"`
 }
 
 // TagUpdate dto.
 type TagUpdate struct {
-	TagName *string `json:"tagname"`
+	TagName *string `json:"tagname" validate:"omitempty,min=1,max=255,tagpath"`
+}
+
+// TagPatch dto. Tag patched by JSON Merge Patch or JSON Patch.
+type TagPatch struct {
+	TagName string `json:"tagname" validate:"required,max=255,tagpath"`
 }
 
 // TagsResp dto.
 type TagsResp struct {
-	ID      string `json:"id"`
-	TagName string `json:"tagname"`
+	ID        string    `json:"id"`
+	TagName   string    `json:"tagname"`
+	ParentID  *string   `json:"parent_id,omitempty" db:"parent_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TagTreeResp dto. Tag with its child tags, TagName is path of the tag.
+// NoteCount is count of notes with the tag, TotalCount is count of notes with the tag or any of its child tags.
+type TagTreeResp struct {
+	ID         string        `json:"id"`
+	TagName    string        `json:"tagname"`
+	ParentID   *string       `json:"-" db:"parent_id"`
+	NoteCount  int           `json:"note_count" db:"note_count"`
+	TotalCount int           `json:"total_count" db:"total_count"`
+	Children   []TagTreeResp `json:"children"`
+}
+
+// TagMerge dto. Links of source tags are moved to target tag, then source tags are deleted.
+type TagMerge struct {
+	SourceIDs []int64 `json:"source_ids" validate:"required,min=1,max=100,dive,gt=0"`
+	TargetID  int64   `json:"target_id" validate:"required,gt=0"`
+}
+
+// TagMergeResp dto. MovedLinks is count of notes linked to target tag by merge, notes already with it are not counted.
+type TagMergeResp struct {
+	TargetID   int64   `json:"target_id"`
+	Merged     []int64 `json:"merged"`
+	MovedLinks int64   `json:"moved_links"`
+}
+
+// TagStatsResp dto. Notes in trash are not counted, LastUsedAt is the latest time the tag was linked to a note.
+type TagStatsResp struct {
+	ID         string     `json:"id"`
+	TagName    string     `json:"tagname"`
+	NoteCount  int        `json:"note_count" db:"note_count"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+// TagDuplicatesResp dto. Tags with names equal ignoring case, TagName is the name in lower case.
+type TagDuplicatesResp struct {
+	TagName string         `json:"tagname"`
+	Tags    []TagStatsResp `json:"tags"`
 }
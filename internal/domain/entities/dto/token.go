@@ -0,0 +1,13 @@
+package dto
+
+// TokensResp dto.
+type TokensResp struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshTokenReq dto.
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=255"`
+}
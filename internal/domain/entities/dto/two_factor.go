@@ -0,0 +1,37 @@
+package dto
+
+// LoginResp dto. Tokens are issued at once,
+// or a challenge is returned if user has two-factor auth enabled.
+type LoginResp struct {
+	*TokensResp
+	*TwoFactorChallengeResp
+}
+
+// TwoFactorChallengeResp dto.
+type TwoFactorChallengeResp struct {
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	Challenge          string `json:"challenge"`
+	ChallengeExpiresIn int64  `json:"challenge_expires_in"`
+}
+
+// TwoFactorLoginReq dto. Code is TOTP code or one of recovery codes.
+type TwoFactorLoginReq struct {
+	Challenge string `json:"challenge" validate:"required,max=255"`
+	Code      string `json:"code" validate:"required,max=64"`
+}
+
+// TwoFactorCodeReq dto.
+type TwoFactorCodeReq struct {
+	Code string `json:"code" validate:"required,max=64"`
+}
+
+// TwoFactorEnrollResp dto.
+type TwoFactorEnrollResp struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResp dto. Recovery codes are shown only once.
+type RecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
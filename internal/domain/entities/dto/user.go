@@ -1,19 +1,50 @@
 package dto
 
+import "time"
+
 // UserUpdate dto.
 type UserUpdate struct {
-	Username *string `json:"username"`
-	Password *string `json:"password"`
+	Username *string `json:"username" validate:"omitempty,min=3,max=32,username"`
+	Password *string `json:"password" validate:"omitempty,min=8,max=72,password"`
+	Role     *string `json:"role" validate:"omitempty,oneof=user admin"`
+}
+
+// UserPatch dto. User patched by JSON Merge Patch or JSON Patch, password is write-only and it is set by add.
+type UserPatch struct {
+	Username string  `json:"username" validate:"required,min=3,max=32,username"`
+	Password *string `json:"password,omitempty" validate:"omitempty,min=8,max=72,password"`
+	Role     string  `json:"role" validate:"required,oneof=user admin"`
 }
 
 // UserAuth dto.
 type UserAuth struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Username string `json:"username" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,max=255"`
 }
 
 // UserResp dto.
 type UserResp struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PasswordForgotReq dto.
+type PasswordForgotReq struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+// PasswordResetReq dto.
+type PasswordResetReq struct {
+	Token    string `json:"token" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72,password"`
+}
+
+// EmailVerifyReq dto.
+type EmailVerifyReq struct {
+	Token string `json:"token" validate:"required,max=255"`
 }
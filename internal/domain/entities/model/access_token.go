@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// AccessToken model. Personal access token of user, only token hash is stored.
+// Scopes are stored comma separated.
+type AccessToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	Name      string     `db:"name"`
+	TokenHash string     `db:"token_hash"`
+	Scopes    string     `db:"scopes"`
+	ExpiresAt *time.Time `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Mail model. Message in transactional outbox, it is delivered by background dispatcher.
+type Mail struct {
+	ID        string         `db:"id"`
+	Recipient string         `db:"recipient"`
+	Subject   string         `db:"subject"`
+	Body      string         `db:"body"`
+	CreatedAt time.Time      `db:"created_at"`
+	SentAt    *time.Time     `db:"sent_at"`
+	Attempts  int            `db:"attempts"`
+	LastError sql.NullString `db:"last_error"`
+}
+
+// UserToken model. Single-use token sent by mail (password reset, email verification), only hash is stored.
+type UserToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	Purpose   string     `db:"purpose"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
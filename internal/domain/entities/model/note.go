@@ -1,10 +1,14 @@
 // Package model Package model
 package model
 
+import "time"
+
 // Note model.
 type Note struct {
-	ID     string `json:"id" db:"id"`
-	Title  string `json:"title" validate:"required"`
-	Info   string `json:"info" validate:"required"`
-	UserID string `json:"user_id"`
+	ID        string    `json:"id" db:"id"`
+	Title     string    `json:"title" validate:"required,max=255"`
+	Info      string    `json:"info" validate:"required"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
 }
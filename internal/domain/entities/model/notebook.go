@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Notebook model. Folder of notes, notebooks without parent are root ones.
+type Notebook struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	ParentID  *int64    `json:"parent_id" db:"parent_id" validate:"omitempty,gt=0"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
+}
@@ -1,8 +1,12 @@
 package model
 
-// Tag model.
+import "time"
+
+// Tag model. TagName is path of tag names separated by "/", e.g. "work/projects/alpha" is a child of "work/projects".
 type Tag struct {
-	ID      string `json:"id"`
-	TagName string `json:"tagname" validate:"required"`
-	UserID  string `json:"user_id"`
+	ID        string    `json:"id"`
+	TagName   string    `json:"tagname" validate:"required,max=255,tagpath"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
 }
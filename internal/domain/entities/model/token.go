@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// RefreshToken model. One refresh token is one user session.
+type RefreshToken struct {
+	ID              string    `db:"id"`
+	UserID          string    `db:"user_id"`
+	TokenHash       string    `db:"token_hash"`
+	AccessJTI       string    `db:"access_jti"`
+	AccessExpiresAt time.Time `db:"access_expires_at"`
+	ExpiresAt       time.Time `db:"expires_at"`
+	Revoked         bool      `db:"revoked"`
+}
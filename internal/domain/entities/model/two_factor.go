@@ -0,0 +1,26 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TwoFactor model. TOTP settings of user, secret is set on enrollment and enabled after confirmation.
+type TwoFactor struct {
+	UserID   string         `db:"id"`
+	Username string         `db:"username"`
+	Secret   sql.NullString `db:"totp_secret"`
+	Enabled  bool           `db:"totp_enabled"`
+	LastStep int64          `db:"totp_last_step"`
+}
+
+// TwoFactorChallenge model. Short-lived second login step, only hash of challenge is stored.
+// Username is read with the challenge, login attempts are counted per username.
+type TwoFactorChallenge struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	Username  string    `db:"username"`
+	TokenHash string    `db:"token_hash"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
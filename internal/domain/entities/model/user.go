@@ -1,8 +1,18 @@
 package model
 
+import "time"
+
 // User model.
 type User struct {
 	ID       string `json:"id" db:"id"`
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Username string `json:"username" validate:"required,min=3,max=32,username"`
+	Password string `json:"password" validate:"required,min=8,max=72,password"`
+	Email    string `json:"email" validate:"omitempty,email,max=255"`
+	Role     string `json:"-" db:"role"`
+
+	EmailVerified    bool `json:"-" db:"email_verified"`
+	TwoFactorEnabled bool `json:"-" db:"totp_enabled"`
+
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
 }
@@ -3,28 +3,76 @@ package errors
 
 import "errors"
 
-// DB errors.
+// ErrDBResponse DB errors.
+var ErrDBResponse = errors.New("db response error")
+
+// generic errors of storages, they wrap driver errors.
 var (
-	ErrDBResponse  = errors.New("db response error")
-	ErrDBDuplicate = "duplicate key value violates unique constraint"
-	ErrDBNotExists = "no rows in result set"
+	ErrNotFound        = NotFound(CodeNotFound, "not found")
+	ErrConflict        = Conflict(CodeConflict, "already exists")
+	ErrReference       = Conflict("reference_conflict", "referenced entity does not exist or is still referenced")
+	ErrInvalidValue    = Validation(CodeInvalidValue, "invalid value")
+	ErrVersionMismatch = PreconditionFailed("version_mismatch", "version does not match If-Match, it was changed")
+)
+
+// request errors.
+var (
+	ErrInvalidQueryParam = Validation("invalid_query_param", "invalid query parameter")
+	ErrInvalidCursor     = Validation("invalid_cursor", "invalid cursor")
+	ErrMalformedJSON     = Validation(CodeMalformed, "malformed JSON body")
+	ErrValidation        = Validation(CodeValidation, "request validation failed")
+	ErrInvalidPatch      = Validation("invalid_patch", "invalid patch")
+	ErrPatchTestFailed   = Conflict("patch_test_failed", "patch test operation failed")
+	ErrPatchMediaType    = UnsupportedMedia("unsupported_patch_type",
+		"patch must be application/merge-patch+json or application/json-patch+json")
 )
 
 // user errors.
 var (
-	ErrUsersListEmpty    = errors.New("no users")
-	ErrEmptyAuthHeader   = errors.New("empty auth header")
-	ErrEmptyToken        = errors.New("empty token")
-	ErrInvalidAuthHeader = errors.New("invalid auth header")
-	ErrSigningMethod     = errors.New("invalid signing method")
-	ErrClaimsType        = errors.New("token claims are not of type *dto.TokenClaims")
+	ErrUsersListEmpty      = NotFound("users_not_found", "no users")
+	ErrEmptyAuthHeader     = Unauthorized("empty_auth_header", "empty auth header")
+	ErrEmptyToken          = Unauthorized("empty_token", "empty token")
+	ErrInvalidAuthHeader   = Unauthorized("invalid_auth_header", "invalid auth header")
+	ErrClaimsType          = Unauthorized("invalid_token", "token claims are not of type *dto.TokenClaims")
+	ErrInvalidCredentials  = Unauthorized("invalid_credentials", "invalid username or password")
+	ErrInvalidRefreshToken = Unauthorized("invalid_refresh_token", "invalid refresh token")
+	ErrTokenRevoked        = Unauthorized("token_revoked", "token is revoked")
+	ErrForbidden           = Forbidden("access_denied", "access denied")
+	ErrInvalidAccessToken  = Unauthorized("invalid_access_token", "invalid access token")
+	ErrTokenScope          = Forbidden("insufficient_scope", "insufficient token scope")
+	ErrTokenExpiresAt      = Validation("invalid_expires_at", "token expiration time must be in the future")
+	ErrInvalidTwoFactor    = Unauthorized("invalid_two_factor_code", "invalid two-factor code")
+	ErrInvalidChallenge    = Unauthorized("invalid_challenge", "invalid or expired two-factor challenge")
+	ErrTwoFactorNotEnabled = Conflict("two_factor_not_enrolled", "two-factor auth is not enrolled")
+	ErrTwoFactorEnabled    = Conflict("two_factor_enabled", "two-factor auth is already enabled")
+	ErrTooManyAttempts     = TooManyRequests("too_many_attempts", "too many failed login attempts, try again later")
+	ErrAccountLocked       = Locked("account_locked", "account is temporarily locked after too many failed login attempts")
+	ErrInvalidUserToken    = Validation("invalid_user_token", "invalid or expired token")
+	ErrEmailVerified       = Conflict("email_verified", "email is already verified")
+	ErrNoEmail             = Conflict("no_email", "user has no email")
 )
 
 // notes errors.
 var (
-	ErrNotesListEmpty         = errors.New("no notes")
-	ErrNotesListWithTagsEmpty = errors.New("no notes with tags")
+	ErrNotesListEmpty         = NotFound("notes_not_found", "no notes")
+	ErrNotesListWithTagsEmpty = NotFound("tagged_notes_not_found", "no notes with tags")
+	ErrNotesSearchEmpty       = NotFound("search_no_results", "no notes match the search")
+	ErrTagsAddRemove          = Validation("tag_added_and_removed", "tag can not be both added and removed")
+	ErrTrashEmpty             = NotFound("trash_empty", "no notes in trash")
+	ErrNoteTitleTaken         = Conflict("note_title_taken", "note with the same title already exists")
+	ErrRevisionsListEmpty     = NotFound("revisions_not_found", "note has no revisions")
 )
 
-// ErrTagsListEmpty tags errors.
-var ErrTagsListEmpty = errors.New("no tags")
+// notebooks errors.
+var (
+	ErrNotebooksListEmpty = NotFound("notebooks_not_found", "no notebooks")
+	ErrNotebookCycle      = Conflict("notebook_cycle", "notebook can not be moved into itself or its child notebooks")
+)
+
+// tags errors.
+var (
+	ErrTagsListEmpty      = NotFound("tags_not_found", "no tags")
+	ErrTagCycle           = Conflict("tag_cycle", "tag can not be moved into itself or its child tags")
+	ErrTagMergeTarget     = Validation("tag_merge_target", "tag can not be merged into itself")
+	ErrTagDuplicatesEmpty = NotFound("tag_duplicates_not_found", "no duplicate tags")
+)
@@ -0,0 +1,133 @@
+package errors
+
+import "errors"
+
+// Kind class of domain error, handlers map it to HTTP status.
+type Kind int
+
+// kinds of domain errors.
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindPrecondition
+	KindUnsupportedMedia
+	KindTooManyRequests
+	KindLocked
+)
+
+// generic error codes, errors of storages have them.
+const (
+	CodeInternal     = "internal_error"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeValidation   = "validation_failed"
+	CodeInvalidValue = "invalid_value"
+	CodeMalformed    = "malformed_request"
+)
+
+// FieldError validation error of one request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error typed domain error. Code is stable machine-readable code for clients, Err is the cause.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// Error message of error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap cause of error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is errors with the same kind and code match, so errors.Is(err, ErrNotFound) is true for wrapped causes too.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap copy of error with cause.
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+
+	return &wrapped
+}
+
+// WithFields copy of error with field errors.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	withFields := *e
+	withFields.Fields = fields
+
+	return &withFields
+}
+
+// NotFound requested entity does not exist.
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict request conflicts with current state, e.g. unique value is taken.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation request is invalid.
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Unauthorized credentials are missing or invalid.
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden user is not allowed to do it.
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// PreconditionFailed precondition of request, e.g. If-Match, does not hold for current state.
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: KindPrecondition, Code: code, Message: message}
+}
+
+// UnsupportedMedia request body has media type which is not supported.
+func UnsupportedMedia(code, message string) *Error {
+	return &Error{Kind: KindUnsupportedMedia, Code: code, Message: message}
+}
+
+// TooManyRequests client sent too many requests, it has to wait before retry.
+func TooManyRequests(code, message string) *Error {
+	return &Error{Kind: KindTooManyRequests, Code: code, Message: message}
+}
+
+// Locked requested entity is temporarily locked.
+func Locked(code, message string) *Error {
+	return &Error{Kind: KindLocked, Code: code, Message: message}
+}
+
+// As typed domain error in err chain, nil if there is none.
+func As(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"web/internal/adapters/storage"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+)
+
+// accessTokenLen personal access token random bytes length.
+const accessTokenLen = 32
+
+// accessTokenService personal access token service struct.
+type accessTokenService struct {
+	storage storage.AccessTokenStorage
+}
+
+// NewAccessTokenService personal access token service func builder.
+func NewAccessTokenService(accessTokenStorage storage.AccessTokenStorage) AccessTokenService {
+	return &accessTokenService{storage: accessTokenStorage}
+}
+
+// CreateAccessToken create personal access token. Token value is returned only here.
+func (a *accessTokenService) CreateAccessToken(
+	ctx context.Context,
+	userID string,
+	req *dto.AccessTokenReq,
+) (*dto.AccessTokenCreatedResp, error) {
+	now := time.Now().UTC()
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.ErrTokenExpiresAt
+	}
+
+	raw := make([]byte, accessTokenLen)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+
+	value := dictionary.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := &model.AccessToken{
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: hashToken(value),
+		Scopes:    strings.Join(req.Scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: now,
+	}
+	if token.ExpiresAt != nil {
+		expiresAt := token.ExpiresAt.UTC()
+		token.ExpiresAt = &expiresAt
+	}
+
+	token, err := a.storage.CreateAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AccessTokenCreatedResp{
+		AccessTokenResp: accessTokenResp(token),
+		Token:           value,
+	}, nil
+}
+
+// GetAllAccessTokens get all personal access tokens by user.
+func (a *accessTokenService) GetAllAccessTokens(ctx context.Context, userID string) ([]dto.AccessTokenResp, error) {
+	tokens, err := a.storage.GetAllAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.AccessTokenResp, 0, len(tokens))
+	for i := range tokens {
+		resp = append(resp, accessTokenResp(&tokens[i]))
+	}
+
+	return resp, nil
+}
+
+// DeleteAccessToken revoke personal access token by ID.
+func (a *accessTokenService) DeleteAccessToken(ctx context.Context, tokenID, userID string) (int, error) {
+	return a.storage.DeleteAccessToken(ctx, tokenID, userID)
+}
+
+// accessTokenResp convert model to response dto.
+func accessTokenResp(token *model.AccessToken) dto.AccessTokenResp {
+	return dto.AccessTokenResp{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    strings.Split(token.Scopes, ","),
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	e "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"web/internal/adapters/storage"
+	"web/internal/config"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/internal/utils/dictionary"
+	"web/pkg/hasher"
+)
+
+// accountService password reset and email verification service struct.
+type accountService struct {
+	storage      storage.AccountStorage
+	tokenStorage storage.TokenStorage
+	hasher       *hasher.Manager
+	cfg          config.Mail
+}
+
+// NewAccountService account service func builder.
+func NewAccountService(
+	accountStorage storage.AccountStorage,
+	tokenStorage storage.TokenStorage,
+	passwordHasher *hasher.Manager,
+	cfg config.Mail,
+) AccountService {
+	return &accountService{
+		storage:      accountStorage,
+		tokenStorage: tokenStorage,
+		hasher:       passwordHasher,
+		cfg:          cfg,
+	}
+}
+
+// ForgotPassword put mail with password reset link to outbox.
+// Unknown and not verified emails are silently ignored, so the response does not reveal registered users.
+func (a *accountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := a.storage.GetUserByEmail(ctx, normalizeEmail(email))
+	if e.Is(err, errors.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !user.EmailVerified {
+		return nil
+	}
+
+	token, mail, err := newUserTokenMail(a.cfg, dictionary.PurposeResetPassword, user)
+	if err != nil {
+		return err
+	}
+
+	return a.storage.CreateUserToken(ctx, token, mail)
+}
+
+// ResetPassword set new password by reset token. All user sessions are closed.
+func (a *accountService) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := a.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	userID, err := a.storage.ResetPassword(ctx, hashToken(token), hash)
+	if e.Is(err, errors.ErrNotFound) {
+		return errors.ErrInvalidUserToken
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.tokenStorage.RevokeAllSessions(ctx, userID)
+}
+
+// VerifyEmail mark user email as verified by verification token.
+func (a *accountService) VerifyEmail(ctx context.Context, token string) error {
+	_, err := a.storage.VerifyEmail(ctx, hashToken(token))
+	if e.Is(err, errors.ErrNotFound) {
+		return errors.ErrInvalidUserToken
+	}
+
+	return err
+}
+
+// ResendVerification put a new mail with verification link to outbox.
+func (a *accountService) ResendVerification(ctx context.Context, userID string) error {
+	user, err := a.storage.GetUserEmail(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Email == "" {
+		return errors.ErrNoEmail
+	}
+
+	if user.EmailVerified {
+		return errors.ErrEmailVerified
+	}
+
+	token, mail, err := newUserTokenMail(a.cfg, dictionary.PurposeVerifyEmail, user)
+	if err != nil {
+		return err
+	}
+
+	return a.storage.CreateUserToken(ctx, token, mail)
+}
+
+// newUserTokenMail make single-use token and mail with link to use it.
+func newUserTokenMail(cfg config.Mail, purpose string, user *model.User) (*model.UserToken, *model.Mail, error) {
+	raw := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	ttl, link, subject, action := cfg.VerifyTokenTTL, cfg.VerifyEmailURL, "Confirm your email",
+		"To confirm your email, open the link"
+	if purpose == dictionary.PurposeResetPassword {
+		ttl, link, subject, action = cfg.ResetTokenTTL, cfg.ResetPasswordURL, "Reset your password",
+			"To set a new password, open the link"
+	}
+
+	body := fmt.Sprintf("Hello, %s!\n\n%s:\n%s\n\n"+
+		"The link expires in %s. If you did not request it, ignore this mail.\n",
+		user.Username, action, strings.Replace(link, "%s", token, 1), ttl)
+
+	return &model.UserToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		Purpose:   purpose,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}, &model.Mail{
+		Recipient: user.Email,
+		Subject:   subject,
+		Body:      body,
+	}, nil
+}
+
+// normalizeEmail emails are stored in lower case.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
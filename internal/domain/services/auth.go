@@ -1,72 +1,386 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	e "errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 
 	"web/internal/adapters/storage"
+	"web/internal/config"
+	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
 	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
-	"web/internal/utils/functions"
+	"web/pkg/hasher"
+	"web/pkg/jwtkeys"
 )
 
+// refreshTokenLen refresh token random bytes length.
+const refreshTokenLen = 32
+
 // authService auth service struct.
 type authService struct {
-	storage storage.UserAuthStorage
+	storage            storage.UserAuthStorage
+	tokenStorage       storage.TokenStorage
+	accessTokenStorage storage.AccessTokenStorage
+	twoFactorStorage   storage.TwoFactorStorage
+	hasher             *hasher.Manager
+	keys               *jwtkeys.KeySet
+	cfg                config.Token
+	twoFactorCfg       config.TwoFactor
+	mailCfg            config.Mail
 }
 
 // NewAuthService auth service func builder.
-func NewAuthService(userAuthStorage storage.UserAuthStorage) UserAuthService {
-	return &authService{storage: userAuthStorage}
+func NewAuthService(
+	storages *storage.Storages,
+	passwordHasher *hasher.Manager,
+	keys *jwtkeys.KeySet,
+	cfg *config.Config,
+) UserAuthService {
+	return &authService{
+		storage:            storages.Auth,
+		tokenStorage:       storages.Token,
+		accessTokenStorage: storages.AccessToken,
+		twoFactorStorage:   storages.TwoFactor,
+		hasher:             passwordHasher,
+		keys:               keys,
+		cfg:                cfg.Token,
+		twoFactorCfg:       cfg.TwoFactor,
+		mailCfg:            cfg.Mail,
+	}
 }
 
-// RegisterUser create user.
-func (a *authService) RegisterUser(user *model.User) (*model.User, error) {
-	user.Password = functions.GeneratePasswordHash(user.Password)
-	return a.storage.RegisterUser(user)
+// RegisterUser create user. If email is set, mail with verification link is put to outbox.
+func (a *authService) RegisterUser(ctx context.Context, user *model.User) (*model.User, error) {
+	hash, err := a.hasher.Hash(user.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hash
+	user.Email = normalizeEmail(user.Email)
+
+	if user.Email == "" {
+		return a.storage.RegisterUser(ctx, user, nil, nil)
+	}
+
+	verification, mail, err := newUserTokenMail(a.mailCfg, dictionary.PurposeVerifyEmail, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.storage.RegisterUser(ctx, user, verification, mail)
 }
 
-// GenerateToken generate token for user auth.
-func (a *authService) GenerateToken(userName, password string) (string, error) {
-	user, err := a.storage.GetUserForToken(userName, functions.GeneratePasswordHash(password))
+// GenerateToken generate access and refresh tokens for user auth.
+// If user has two-factor auth enabled, only a challenge for the second step is returned.
+func (a *authService) GenerateToken(ctx context.Context, userName, password string) (*dto.LoginResp, error) {
+	user, err := a.storage.GetUserByUsername(ctx, userName)
+	if e.Is(err, errors.ErrNotFound) {
+		// unknown username is told apart from wrong password neither by response nor by time
+		a.hasher.VerifyFake(password)
+		return nil, errors.ErrInvalidCredentials
+	}
+
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dictionary.TokenClaims{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(dictionary.ExpDuration).Unix(),
-		},
-		UserID: user.ID,
-	})
+	ok, rehash, err := a.hasher.Verify(password, user.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if rehash {
+		a.rehashPassword(ctx, user.ID, password)
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := a.newChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.LoginResp{TwoFactorChallengeResp: challenge}, nil
+	}
+
+	tokens, err := a.createSession(ctx, user.ID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResp{TokensResp: tokens}, nil
+}
+
+// ChallengeUsername username of user who got login challenge, unknown challenge is errors.ErrInvalidChallenge.
+func (a *authService) ChallengeUsername(ctx context.Context, challengeToken string) (string, error) {
+	challenge, err := a.twoFactorStorage.GetChallenge(ctx, hashToken(challengeToken))
+	if e.Is(err, errors.ErrNotFound) {
+		return "", errors.ErrInvalidChallenge
+	}
 
-	tokenString, err := token.SignedString([]byte(dictionary.SigningKey))
 	if err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	return challenge.Username, nil
 }
 
-// ParseToken check token for auth.
-func (a *authService) ParseToken(accessToken string) (string, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &dictionary.TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.ErrSigningMethod
+// VerifyTwoFactor second login step: exchange challenge and TOTP or recovery code for tokens.
+func (a *authService) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*dto.TokensResp, error) {
+	challenge, err := a.twoFactorStorage.GetChallenge(ctx, hashToken(challengeToken))
+	if e.Is(err, errors.ErrNotFound) {
+		return nil, errors.ErrInvalidChallenge
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().UTC().After(challenge.ExpiresAt) || challenge.Attempts >= a.twoFactorCfg.MaxAttempts {
+		if err := a.twoFactorStorage.DeleteChallenge(ctx, challenge.ID); err != nil {
+			return nil, err
 		}
-		return []byte(dictionary.SigningKey), nil
-	})
+		return nil, errors.ErrInvalidChallenge
+	}
 
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(ctx, challenge.UserID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	ok, err := verifySecondFactor(ctx, a.twoFactorStorage, a.twoFactorCfg, twoFactor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		if err := a.twoFactorStorage.AddChallengeAttempt(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrInvalidTwoFactor
+	}
+
+	if err := a.twoFactorStorage.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	role, err := a.storage.GetUserRole(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.createSession(ctx, challenge.UserID, role)
+}
+
+// RefreshToken exchange refresh token for a new tokens pair. The old refresh token is revoked.
+func (a *authService) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokensResp, error) {
+	session, err := a.tokenStorage.GetRefreshToken(ctx, hashToken(refreshToken))
+	if e.Is(err, errors.ErrNotFound) {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// reuse of rotated token means it was stolen, so all user sessions are closed
+	if session.Revoked {
+		if err := a.tokenStorage.RevokeAllSessions(ctx, session.UserID); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	if time.Now().UTC().After(session.ExpiresAt) {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	role, err := a.storage.GetUserRole(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, newSession, err := a.newSession(session.UserID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.tokenStorage.RotateRefreshToken(ctx, session.ID, newSession)
+	if e.Is(err, errors.ErrNotFound) {
+		return nil, errors.ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout revoke access token and refresh token issued with it.
+func (a *authService) Logout(ctx context.Context, tokenID string) error {
+	return a.tokenStorage.RevokeSession(ctx, tokenID, time.Now().UTC().Add(a.cfg.AccessTTL))
+}
+
+// JWKS public keys for tokens verification by other services.
+func (a *authService) JWKS() *jwtkeys.JWKS {
+	return a.keys.JWKS()
+}
+
+// ParseToken check token for auth. Both JWT and personal access tokens are accepted.
+func (a *authService) ParseToken(ctx context.Context, accessToken string) (*dictionary.TokenClaims, error) {
+	if strings.HasPrefix(accessToken, dictionary.AccessTokenPrefix) {
+		return a.parseAccessToken(ctx, accessToken)
+	}
+
+	token, err := jwt.ParseWithClaims(accessToken, &dictionary.TokenClaims{}, a.keys.Keyfunc)
+	if err != nil {
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*dictionary.TokenClaims)
 	if !ok {
-		return "", errors.ErrClaimsType
+		return nil, errors.ErrClaimsType
 	}
 
-	return claims.UserID, nil
+	revoked, err := a.tokenStorage.IsTokenRevoked(ctx, claims.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, errors.ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// parseAccessToken check personal access token by its hash.
+func (a *authService) parseAccessToken(ctx context.Context, accessToken string) (*dictionary.TokenClaims, error) {
+	token, err := a.accessTokenStorage.GetAccessTokenByHash(ctx, hashToken(accessToken))
+	if e.Is(err, errors.ErrNotFound) {
+		return nil, errors.ErrInvalidAccessToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ExpiresAt != nil && time.Now().UTC().After(*token.ExpiresAt) {
+		return nil, errors.ErrInvalidAccessToken
+	}
+
+	return &dictionary.TokenClaims{
+		UserID:   token.UserID,
+		Personal: true,
+		Scopes:   strings.Split(token.Scopes, ","),
+	}, nil
+}
+
+// createSession make tokens pair for user and save refresh token.
+func (a *authService) createSession(ctx context.Context, userID, role string) (*dto.TokensResp, error) {
+	tokens, session, err := a.newSession(userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.tokenStorage.CreateRefreshToken(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// newChallenge make short-lived challenge for the second login step.
+func (a *authService) newChallenge(ctx context.Context, userID string) (*dto.TwoFactorChallengeResp, error) {
+	raw := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := a.twoFactorStorage.CreateChallenge(ctx, &model.TwoFactorChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().UTC().Add(a.twoFactorCfg.ChallengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorChallengeResp{
+		TwoFactorRequired:  true,
+		Challenge:          token,
+		ChallengeExpiresIn: int64(a.twoFactorCfg.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// newSession make access token and refresh token for user.
+func (a *authService) newSession(userID, role string) (*dto.TokensResp, *model.RefreshToken, error) {
+	now := time.Now().UTC()
+	session := &model.RefreshToken{
+		UserID:          userID,
+		AccessJTI:       uuid.New().String(),
+		AccessExpiresAt: now.Add(a.cfg.AccessTTL),
+		ExpiresAt:       now.Add(a.cfg.RefreshTTL),
+	}
+
+	accessToken, err := a.keys.Sign(dictionary.TokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        session.AccessJTI,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: session.AccessExpiresAt.Unix(),
+		},
+		UserID: userID,
+		Role:   role,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+	session.TokenHash = hashToken(refreshToken)
+
+	return &dto.TokensResp{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(a.cfg.AccessTTL.Seconds()),
+	}, session, nil
+}
+
+// rehashPassword replace outdated password hash with a hash of the current algorithm.
+// Errors are ignored: the old hash is still valid, so the next login will retry.
+func (a *authService) rehashPassword(ctx context.Context, userID, password string) {
+	hash, err := a.hasher.Hash(password)
+	if err != nil {
+		return
+	}
+
+	a.storage.UpdatePasswordHash(ctx, userID, hash) //nolint:errcheck,gosec
+}
+
+// hashToken refresh tokens, personal access tokens, challenges and recovery codes are stored only as sha256 hash.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
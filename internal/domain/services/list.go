@@ -0,0 +1,39 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"web/internal/domain/entities/dto"
+	"web/internal/utils/dictionary"
+	"web/internal/utils/functions"
+)
+
+// pageQuery copy of list query which asks one extra item, it tells whether there is a next page.
+func pageQuery(listQuery *dto.ListQuery) *dto.ListQuery {
+	query := *listQuery
+	query.Limit++
+
+	return &query
+}
+
+// nextCursor cursor of the page which starts after the item with given sort key values.
+func nextCursor(listQuery *dto.ListQuery, id, title string, createdAt, updatedAt time.Time) (string, error) {
+	itemID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	cursor := &dto.Cursor{Sort: listQuery.Sort, Desc: listQuery.Desc, ID: itemID}
+
+	switch listQuery.Sort {
+	case dictionary.SortTitle:
+		cursor.Title = &title
+	case dictionary.SortCreatedAt:
+		cursor.Time = &createdAt
+	case dictionary.SortUpdatedAt:
+		cursor.Time = &updatedAt
+	}
+
+	return functions.EncodeCursor(cursor)
+}
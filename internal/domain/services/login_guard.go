@@ -0,0 +1,160 @@
+package services
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"web/internal/config"
+	"web/internal/domain/errors"
+)
+
+// loginAttempts failed login attempts of one username or client IP.
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// loginGuardService in-memory brute-force protection of login.
+type loginGuardService struct {
+	mu        sync.Mutex
+	users     map[string]*loginAttempts
+	ips       map[string]*loginAttempts
+	lastPrune time.Time
+	cfg       config.App
+	now       func() time.Time
+}
+
+// NewLoginGuardService login guard service func builder.
+func NewLoginGuardService(cfg config.App) LoginGuardService {
+	return &loginGuardService{
+		users: make(map[string]*loginAttempts),
+		ips:   make(map[string]*loginAttempts),
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Check whether login attempt is allowed now. Returns how long to wait if not.
+// Locked username gives errors.ErrAccountLocked, backoff and locked IP give errors.ErrTooManyAttempts.
+func (l *loginGuardService) Check(userName, clientIP string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if attempts, ok := l.users[userName]; ok {
+		if now.Before(attempts.lockedUntil) {
+			return attempts.lockedUntil.Sub(now), errors.ErrAccountLocked
+		}
+
+		if wait := l.backoff(attempts, l.cfg.LoginBackoffAfter, now); wait > 0 {
+			return wait, errors.ErrTooManyAttempts
+		}
+	}
+
+	if attempts, ok := l.ips[clientIP]; ok {
+		if now.Before(attempts.lockedUntil) {
+			return attempts.lockedUntil.Sub(now), errors.ErrTooManyAttempts
+		}
+
+		if wait := l.backoff(attempts, l.cfg.LoginIPBackoffAfter, now); wait > 0 {
+			return wait, errors.ErrTooManyAttempts
+		}
+	}
+
+	return 0, nil
+}
+
+// Fail count failed attempt for username and client IP.
+func (l *loginGuardService) Fail(userName, clientIP string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	l.fail(l.users, userName, l.cfg.LoginLockoutAfter, now)
+	l.fail(l.ips, clientIP, l.cfg.LoginIPLockoutAfter, now)
+}
+
+// Reset forget failed attempts of username after successful login.
+// Client IP counter is not reset, one valid account must not unlock guessing of others.
+func (l *loginGuardService) Reset(userName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.users, userName)
+}
+
+// ClientIP address used for per-IP counters. X-Real-IP is trusted only when the app is behind proxy.
+func (l *loginGuardService) ClientIP(remoteAddr, realIP string) string {
+	if l.cfg.TrustRealIP && realIP != "" {
+		return realIP
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+
+	return host
+}
+
+// backoff time left to wait: base delay doubles with every failure after threshold.
+func (l *loginGuardService) backoff(attempts *loginAttempts, after int, now time.Time) time.Duration {
+	if attempts.failures < after {
+		return 0
+	}
+
+	delay := l.cfg.LoginBackoffBase
+	for i := after; i < attempts.failures && delay < l.cfg.LoginLockoutDuration; i++ {
+		delay *= 2
+	}
+
+	if delay > l.cfg.LoginLockoutDuration {
+		delay = l.cfg.LoginLockoutDuration
+	}
+
+	return attempts.lastFailure.Add(delay).Sub(now)
+}
+
+// fail count failure of key, lock key when threshold is reached.
+func (l *loginGuardService) fail(entries map[string]*loginAttempts, key string, lockoutAfter int, now time.Time) {
+	attempts, ok := entries[key]
+	if !ok || l.expired(attempts, now) {
+		attempts = &loginAttempts{}
+		entries[key] = attempts
+	}
+
+	attempts.failures++
+	attempts.lastFailure = now
+
+	if attempts.failures >= lockoutAfter {
+		attempts.lockedUntil = now.Add(l.cfg.LoginLockoutDuration)
+		attempts.failures = 0
+	}
+}
+
+// expired failures are forgotten after lockout duration without new ones.
+func (l *loginGuardService) expired(attempts *loginAttempts, now time.Time) bool {
+	return now.After(attempts.lockedUntil) && now.Sub(attempts.lastFailure) > l.cfg.LoginLockoutDuration
+}
+
+// prune remove expired entries not more often than once per lockout duration.
+func (l *loginGuardService) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.cfg.LoginLockoutDuration {
+		return
+	}
+
+	for _, entries := range []map[string]*loginAttempts{l.users, l.ips} {
+		for key, attempts := range entries {
+			if l.expired(attempts, now) {
+				delete(entries, key)
+			}
+		}
+	}
+
+	l.lastPrune = now
+}
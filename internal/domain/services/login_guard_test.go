@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"web/internal/config"
+	"web/internal/domain/errors"
+)
+
+func TestLoginGuardService(t *testing.T) {
+	cfg := config.App{
+		LoginBackoffAfter:    2,
+		LoginBackoffBase:     time.Second,
+		LoginLockoutAfter:    4,
+		LoginLockoutDuration: time.Minute,
+		LoginIPBackoffAfter:  10,
+		LoginIPLockoutAfter:  20,
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	guard := NewLoginGuardService(cfg).(*loginGuardService)
+	guard.now = func() time.Time { return now }
+
+	check := func(expectedWait time.Duration, expectedErr error) {
+		t.Helper()
+		wait, err := guard.Check("user", "192.0.2.1")
+		require.ErrorIs(t, err, expectedErr)
+		require.Equal(t, expectedWait, wait)
+	}
+
+	// below backoff threshold
+	guard.Fail("user", "192.0.2.1")
+	check(0, nil)
+
+	// 2 failures: wait 1s
+	guard.Fail("user", "192.0.2.1")
+	check(time.Second, errors.ErrTooManyAttempts)
+
+	// 3 failures: wait 2s
+	now = now.Add(time.Second)
+	guard.Fail("user", "192.0.2.1")
+	check(2*time.Second, errors.ErrTooManyAttempts)
+
+	now = now.Add(2 * time.Second)
+	check(0, nil)
+
+	// 4 failures: locked
+	guard.Fail("user", "192.0.2.1")
+	check(time.Minute, errors.ErrAccountLocked)
+
+	// lockout is over
+	now = now.Add(time.Minute)
+	check(0, nil)
+
+	// success resets username counter
+	guard.Fail("user", "192.0.2.1")
+	guard.Fail("user", "192.0.2.1")
+	guard.Reset("user")
+	check(0, nil)
+}
+
+func TestLoginGuardService_IP(t *testing.T) {
+	cfg := config.App{
+		LoginBackoffAfter:    10,
+		LoginBackoffBase:     time.Second,
+		LoginLockoutAfter:    20,
+		LoginLockoutDuration: time.Minute,
+		LoginIPBackoffAfter:  2,
+		LoginIPLockoutAfter:  3,
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	guard := NewLoginGuardService(cfg).(*loginGuardService)
+	guard.now = func() time.Time { return now }
+
+	// failures for different usernames from one IP
+	guard.Fail("user1", "192.0.2.1")
+	guard.Fail("user2", "192.0.2.1")
+	guard.Fail("user3", "192.0.2.1")
+
+	wait, err := guard.Check("user4", "192.0.2.1")
+	require.ErrorIs(t, err, errors.ErrTooManyAttempts)
+	require.Equal(t, time.Minute, wait)
+
+	wait, err = guard.Check("user4", "192.0.2.2")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), wait)
+}
+
+func TestLoginGuardService_ClientIP(t *testing.T) {
+	testTable := []struct {
+		trustRealIP bool
+		remoteAddr  string
+		realIP      string
+		expected    string
+		testName    string
+	}{
+		{
+			remoteAddr: "192.0.2.1:1234",
+			realIP:     "198.51.100.1",
+			expected:   "192.0.2.1",
+			testName:   "Test-1-Not trusted header",
+		},
+		{
+			trustRealIP: true,
+			remoteAddr:  "192.0.2.1:1234",
+			realIP:      "198.51.100.1",
+			expected:    "198.51.100.1",
+			testName:    "Test-2-Trusted header",
+		},
+		{
+			trustRealIP: true,
+			remoteAddr:  "192.0.2.1:1234",
+			expected:    "192.0.2.1",
+			testName:    "Test-3-No header",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			guard := NewLoginGuardService(config.App{TrustRealIP: testCase.trustRealIP})
+			require.Equal(t, testCase.expected, guard.ClientIP(testCase.remoteAddr, testCase.realIP))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"web/internal/adapters/storage"
+	"web/internal/config"
+	"web/pkg/mailer"
+)
+
+// mailService mail outbox dispatcher struct.
+type mailService struct {
+	storage storage.MailStorage
+	sender  mailer.Sender
+	cfg     config.Mail
+}
+
+// NewMailService mail outbox dispatcher func builder.
+func NewMailService(mailStorage storage.MailStorage, sender mailer.Sender, cfg config.Mail) MailService {
+	return &mailService{
+		storage: mailStorage,
+		sender:  sender,
+		cfg:     cfg,
+	}
+}
+
+// DispatchOutbox send batch of pending mails from outbox. Failed mails are retried on next dispatch
+// until attempts limit is reached. Batch is claimed for ClaimTimeout, mails of a dispatcher which stopped
+// before sending them are sent by others after it. Returns count of sent mails.
+func (m *mailService) DispatchOutbox(ctx context.Context) (int, error) {
+	claimedUntil := time.Now().UTC().Add(m.cfg.ClaimTimeout)
+
+	mails, err := m.storage.ClaimPendingMails(ctx, m.cfg.BatchSize, m.cfg.MaxAttempts, claimedUntil)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, mail := range mails {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		msg := mailer.Message{To: mail.Recipient, Subject: mail.Subject, Body: mail.Body}
+		if err := m.sender.Send(ctx, msg); err != nil {
+			if err := m.storage.MarkMailFailed(ctx, mail.ID, err.Error()); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if err := m.storage.MarkMailSent(ctx, mail.ID); err != nil {
+			return sent, err
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
@@ -5,9 +5,13 @@
 package mock_services
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 	dto "web/internal/domain/entities/dto"
 	model "web/internal/domain/entities/model"
+	dictionary "web/internal/utils/dictionary"
+	jwtkeys "web/pkg/jwtkeys"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,49 +39,122 @@ func (m *MockUserAuthService) EXPECT() *MockUserAuthServiceMockRecorder {
 	return m.recorder
 }
 
-// GenerateToken mocks base method.
-func (m *MockUserAuthService) GenerateToken(userName, password string) (string, error) {
+// ChallengeUsername mocks base method.
+func (m *MockUserAuthService) ChallengeUsername(ctx context.Context, challenge string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", userName, password)
+	ret := m.ctrl.Call(m, "ChallengeUsername", ctx, challenge)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ChallengeUsername indicates an expected call of ChallengeUsername.
+func (mr *MockUserAuthServiceMockRecorder) ChallengeUsername(ctx, challenge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeUsername", reflect.TypeOf((*MockUserAuthService)(nil).ChallengeUsername), ctx, challenge)
+}
+
+// GenerateToken mocks base method.
+func (m *MockUserAuthService) GenerateToken(ctx context.Context, userName, password string) (*dto.LoginResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateToken", ctx, userName, password)
+	ret0, _ := ret[0].(*dto.LoginResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockUserAuthServiceMockRecorder) GenerateToken(userName, password interface{}) *gomock.Call {
+func (mr *MockUserAuthServiceMockRecorder) GenerateToken(ctx, userName, password interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockUserAuthService)(nil).GenerateToken), userName, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockUserAuthService)(nil).GenerateToken), ctx, userName, password)
+}
+
+// JWKS mocks base method.
+func (m *MockUserAuthService) JWKS() *jwtkeys.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(*jwtkeys.JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockUserAuthServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockUserAuthService)(nil).JWKS))
+}
+
+// Logout mocks base method.
+func (m *MockUserAuthService) Logout(ctx context.Context, tokenID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserAuthServiceMockRecorder) Logout(ctx, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserAuthService)(nil).Logout), ctx, tokenID)
 }
 
 // ParseToken mocks base method.
-func (m *MockUserAuthService) ParseToken(token string) (string, error) {
+func (m *MockUserAuthService) ParseToken(ctx context.Context, token string) (*dictionary.TokenClaims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseToken", token)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ParseToken", ctx, token)
+	ret0, _ := ret[0].(*dictionary.TokenClaims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
-func (mr *MockUserAuthServiceMockRecorder) ParseToken(token interface{}) *gomock.Call {
+func (mr *MockUserAuthServiceMockRecorder) ParseToken(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockUserAuthService)(nil).ParseToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockUserAuthService)(nil).ParseToken), ctx, token)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserAuthService) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokensResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(*dto.TokensResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserAuthServiceMockRecorder) RefreshToken(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserAuthService)(nil).RefreshToken), ctx, refreshToken)
 }
 
 // RegisterUser mocks base method.
-func (m *MockUserAuthService) RegisterUser(user *model.User) (*model.User, error) {
+func (m *MockUserAuthService) RegisterUser(ctx context.Context, user *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUser", user)
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, user)
 	ret0, _ := ret[0].(*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterUser indicates an expected call of RegisterUser.
-func (mr *MockUserAuthServiceMockRecorder) RegisterUser(user interface{}) *gomock.Call {
+func (mr *MockUserAuthServiceMockRecorder) RegisterUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUserAuthService)(nil).RegisterUser), ctx, user)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockUserAuthService) VerifyTwoFactor(ctx context.Context, challenge, code string) (*dto.TokensResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", ctx, challenge, code)
+	ret0, _ := ret[0].(*dto.TokensResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockUserAuthServiceMockRecorder) VerifyTwoFactor(ctx, challenge, code interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUserAuthService)(nil).RegisterUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockUserAuthService)(nil).VerifyTwoFactor), ctx, challenge, code)
 }
 
 // MockUserService is a mock of UserService interface.
@@ -104,62 +181,62 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserService) DeleteUser(id string) (int, error) {
+func (m *MockUserService) DeleteUser(ctx context.Context, id string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", id)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserServiceMockRecorder) DeleteUser(id interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, id)
 }
 
 // GetAllUsers mocks base method.
-func (m *MockUserService) GetAllUsers() ([]dto.UserResp, error) {
+func (m *MockUserService) GetAllUsers(ctx context.Context, listQuery *dto.ListQuery) ([]dto.UserResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers")
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx, listQuery)
 	ret0, _ := ret[0].([]dto.UserResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockUserServiceMockRecorder) GetAllUsers() *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetAllUsers(ctx, listQuery interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserService)(nil).GetAllUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserService)(nil).GetAllUsers), ctx, listQuery)
 }
 
 // GetUserByID mocks base method.
-func (m *MockUserService) GetUserByID(id string) (*dto.UserResp, error) {
+func (m *MockUserService) GetUserByID(ctx context.Context, id string) (*dto.UserResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", id)
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
 	ret0, _ := ret[0].(*dto.UserResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockUserServiceMockRecorder) GetUserByID(id interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetUserByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), ctx, id)
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserService) UpdateUser(newUser *dto.UserUpdate, userId string) error {
+func (m *MockUserService) UpdateUser(ctx context.Context, newUser *dto.UserUpdate, userID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", newUser, userId)
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, newUser, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserServiceMockRecorder) UpdateUser(newUser, userId interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, newUser, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), newUser, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, newUser, userID)
 }
 
 // MockNoteService is a mock of NoteService interface.
@@ -186,137 +263,273 @@ func (m *MockNoteService) EXPECT() *MockNoteServiceMockRecorder {
 }
 
 // CreateNote mocks base method.
-func (m *MockNoteService) CreateNote(note *model.Note, userID string) (*model.Note, error) {
+func (m *MockNoteService) CreateNote(ctx context.Context, note *model.Note, userID string) (*model.Note, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNote", note, userID)
+	ret := m.ctrl.Call(m, "CreateNote", ctx, note, userID)
 	ret0, _ := ret[0].(*model.Note)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateNote indicates an expected call of CreateNote.
-func (mr *MockNoteServiceMockRecorder) CreateNote(note, userID interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) CreateNote(ctx, note, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNote", reflect.TypeOf((*MockNoteService)(nil).CreateNote), note, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNote", reflect.TypeOf((*MockNoteService)(nil).CreateNote), ctx, note, userID)
 }
 
 // DeleteNote mocks base method.
-func (m *MockNoteService) DeleteNote(noteID, userID string) (int, error) {
+func (m *MockNoteService) DeleteNote(ctx context.Context, noteID, userID string, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteNote", noteID, userID)
+	ret := m.ctrl.Call(m, "DeleteNote", ctx, noteID, userID, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteNote indicates an expected call of DeleteNote.
-func (mr *MockNoteServiceMockRecorder) DeleteNote(noteID, userID interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) DeleteNote(ctx, noteID, userID, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNote", reflect.TypeOf((*MockNoteService)(nil).DeleteNote), noteID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNote", reflect.TypeOf((*MockNoteService)(nil).DeleteNote), ctx, noteID, userID, version)
+}
+
+// DiffNoteRevision mocks base method.
+func (m *MockNoteService) DiffNoteRevision(ctx context.Context, noteID, rev, to, userID string) (*dto.NoteRevisionDiffResp, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffNoteRevision", ctx, noteID, rev, to, userID)
+	ret0, _ := ret[0].(*dto.NoteRevisionDiffResp)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DiffNoteRevision indicates an expected call of DiffNoteRevision.
+func (mr *MockNoteServiceMockRecorder) DiffNoteRevision(ctx, noteID, rev, to, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffNoteRevision", reflect.TypeOf((*MockNoteService)(nil).DiffNoteRevision), ctx, noteID, rev, to, userID)
 }
 
 // GetAllNotesByUser mocks base method.
-func (m *MockNoteService) GetAllNotesByUser(userID string) ([]dto.NotesResp, error) {
+func (m *MockNoteService) GetAllNotesByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.NotesResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllNotesByUser", userID)
+	ret := m.ctrl.Call(m, "GetAllNotesByUser", ctx, userID, listQuery)
 	ret0, _ := ret[0].([]dto.NotesResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllNotesByUser indicates an expected call of GetAllNotesByUser.
-func (mr *MockNoteServiceMockRecorder) GetAllNotesByUser(userID interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) GetAllNotesByUser(ctx, userID, listQuery interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotesByUser", reflect.TypeOf((*MockNoteService)(nil).GetAllNotesByUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotesByUser", reflect.TypeOf((*MockNoteService)(nil).GetAllNotesByUser), ctx, userID, listQuery)
 }
 
 // GetAllNotesWithTags mocks base method.
-func (m *MockNoteService) GetAllNotesWithTags(userID string, notes []dto.NotesResp) ([]dto.NoteWithTagsResp, error) {
+func (m *MockNoteService) GetAllNotesWithTags(ctx context.Context, userID string, listQuery *dto.ListQuery, includeUntagged bool, fn func(*dto.NoteWithTagsResp) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllNotesWithTags", userID, notes)
-	ret0, _ := ret[0].([]dto.NoteWithTagsResp)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "GetAllNotesWithTags", ctx, userID, listQuery, includeUntagged, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // GetAllNotesWithTags indicates an expected call of GetAllNotesWithTags.
-func (mr *MockNoteServiceMockRecorder) GetAllNotesWithTags(userID, notes interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) GetAllNotesWithTags(ctx, userID, listQuery, includeUntagged, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotesWithTags", reflect.TypeOf((*MockNoteService)(nil).GetAllNotesWithTags), userID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotesWithTags", reflect.TypeOf((*MockNoteService)(nil).GetAllNotesWithTags), ctx, userID, listQuery, includeUntagged, fn)
 }
 
 // GetNoteByID mocks base method.
-func (m *MockNoteService) GetNoteByID(noteID, userID string) (*dto.NoteResp, error) {
+func (m *MockNoteService) GetNoteByID(ctx context.Context, noteID, userID string) (*dto.NoteResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNoteByID", noteID, userID)
+	ret := m.ctrl.Call(m, "GetNoteByID", ctx, noteID, userID)
 	ret0, _ := ret[0].(*dto.NoteResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNoteByID indicates an expected call of GetNoteByID.
-func (mr *MockNoteServiceMockRecorder) GetNoteByID(noteID, userID interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) GetNoteByID(ctx, noteID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteByID", reflect.TypeOf((*MockNoteService)(nil).GetNoteByID), noteID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteByID", reflect.TypeOf((*MockNoteService)(nil).GetNoteByID), ctx, noteID, userID)
+}
+
+// GetNoteRevision mocks base method.
+func (m *MockNoteService) GetNoteRevision(ctx context.Context, noteID, rev, userID string) (*dto.NoteRevisionResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNoteRevision", ctx, noteID, rev, userID)
+	ret0, _ := ret[0].(*dto.NoteRevisionResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNoteRevision indicates an expected call of GetNoteRevision.
+func (mr *MockNoteServiceMockRecorder) GetNoteRevision(ctx, noteID, rev, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteRevision", reflect.TypeOf((*MockNoteService)(nil).GetNoteRevision), ctx, noteID, rev, userID)
+}
+
+// GetNoteRevisions mocks base method.
+func (m *MockNoteService) GetNoteRevisions(ctx context.Context, noteID, userID string) ([]dto.NoteRevisionsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNoteRevisions", ctx, noteID, userID)
+	ret0, _ := ret[0].([]dto.NoteRevisionsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNoteRevisions indicates an expected call of GetNoteRevisions.
+func (mr *MockNoteServiceMockRecorder) GetNoteRevisions(ctx, noteID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteRevisions", reflect.TypeOf((*MockNoteService)(nil).GetNoteRevisions), ctx, noteID, userID)
 }
 
 // GetNoteWithAllTags mocks base method.
-func (m *MockNoteService) GetNoteWithAllTags(userID, NoteID string, note *dto.NoteResp) (dto.NoteWithTagsResp, error) {
+func (m *MockNoteService) GetNoteWithAllTags(ctx context.Context, userID, NoteID string) (dto.NoteWithTagsResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNoteWithAllTags", userID, NoteID, note)
+	ret := m.ctrl.Call(m, "GetNoteWithAllTags", ctx, userID, NoteID)
 	ret0, _ := ret[0].(dto.NoteWithTagsResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNoteWithAllTags indicates an expected call of GetNoteWithAllTags.
-func (mr *MockNoteServiceMockRecorder) GetNoteWithAllTags(userID, NoteID, note interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) GetNoteWithAllTags(ctx, userID, NoteID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteWithAllTags", reflect.TypeOf((*MockNoteService)(nil).GetNoteWithAllTags), userID, NoteID, note)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoteWithAllTags", reflect.TypeOf((*MockNoteService)(nil).GetNoteWithAllTags), ctx, userID, NoteID)
 }
 
-// RemoveTags mocks base method.
-func (m *MockNoteService) RemoveTags(noteID string, tags map[string]string) (string, error) {
+// GetNotesPage mocks base method.
+func (m *MockNoteService) GetNotesPage(ctx context.Context, userID string, listQuery *dto.ListQuery) (*dto.NotesPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveTags", noteID, tags)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetNotesPage", ctx, userID, listQuery)
+	ret0, _ := ret[0].(*dto.NotesPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RemoveTags indicates an expected call of RemoveTags.
-func (mr *MockNoteServiceMockRecorder) RemoveTags(noteID, tags interface{}) *gomock.Call {
+// GetNotesPage indicates an expected call of GetNotesPage.
+func (mr *MockNoteServiceMockRecorder) GetNotesPage(ctx, userID, listQuery interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTags", reflect.TypeOf((*MockNoteService)(nil).RemoveTags), noteID, tags)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotesPage", reflect.TypeOf((*MockNoteService)(nil).GetNotesPage), ctx, userID, listQuery)
 }
 
-// SetTags mocks base method.
-func (m *MockNoteService) SetTags(noteID string, tags map[string]string) (string, error) {
+// GetTrash mocks base method.
+func (m *MockNoteService) GetTrash(ctx context.Context, userID string) ([]dto.TrashNoteResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetTags", noteID, tags)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetTrash", ctx, userID)
+	ret0, _ := ret[0].([]dto.TrashNoteResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SetTags indicates an expected call of SetTags.
-func (mr *MockNoteServiceMockRecorder) SetTags(noteID, tags interface{}) *gomock.Call {
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockNoteServiceMockRecorder) GetTrash(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockNoteService)(nil).SetTags), noteID, tags)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockNoteService)(nil).GetTrash), ctx, userID)
 }
 
-// UpdateNote mocks base method.
-func (m *MockNoteService) UpdateNote(newNote *dto.NoteUpdate, noteID string) error {
+// PurgeNote mocks base method.
+func (m *MockNoteService) PurgeNote(ctx context.Context, noteID, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeNote", ctx, noteID, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeNote indicates an expected call of PurgeNote.
+func (mr *MockNoteServiceMockRecorder) PurgeNote(ctx, noteID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeNote", reflect.TypeOf((*MockNoteService)(nil).PurgeNote), ctx, noteID, userID)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockNoteService) PurgeTrash(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockNoteServiceMockRecorder) PurgeTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockNoteService)(nil).PurgeTrash), ctx)
+}
+
+// RestoreNote mocks base method.
+func (m *MockNoteService) RestoreNote(ctx context.Context, noteID, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreNote", ctx, noteID, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreNote indicates an expected call of RestoreNote.
+func (mr *MockNoteServiceMockRecorder) RestoreNote(ctx, noteID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreNote", reflect.TypeOf((*MockNoteService)(nil).RestoreNote), ctx, noteID, userID)
+}
+
+// RestoreNoteRevision mocks base method.
+func (m *MockNoteService) RestoreNoteRevision(ctx context.Context, noteID, rev, userID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNote", newNote, noteID)
+	ret := m.ctrl.Call(m, "RestoreNoteRevision", ctx, noteID, rev, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RestoreNoteRevision indicates an expected call of RestoreNoteRevision.
+func (mr *MockNoteServiceMockRecorder) RestoreNoteRevision(ctx, noteID, rev, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreNoteRevision", reflect.TypeOf((*MockNoteService)(nil).RestoreNoteRevision), ctx, noteID, rev, userID)
+}
+
+// SearchNotes mocks base method.
+func (m *MockNoteService) SearchNotes(ctx context.Context, userID string, searchQuery *dto.NoteSearchQuery) ([]dto.NoteSearchResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchNotes", ctx, userID, searchQuery)
+	ret0, _ := ret[0].([]dto.NoteSearchResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchNotes indicates an expected call of SearchNotes.
+func (mr *MockNoteServiceMockRecorder) SearchNotes(ctx, userID, searchQuery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchNotes", reflect.TypeOf((*MockNoteService)(nil).SearchNotes), ctx, userID, searchQuery)
+}
+
+// UpdateNote mocks base method.
+func (m *MockNoteService) UpdateNote(ctx context.Context, newNote *dto.NoteUpdate, noteID, userID string, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNote", ctx, newNote, noteID, userID, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateNote indicates an expected call of UpdateNote.
-func (mr *MockNoteServiceMockRecorder) UpdateNote(newNote, noteID interface{}) *gomock.Call {
+func (mr *MockNoteServiceMockRecorder) UpdateNote(ctx, newNote, noteID, userID, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNote", reflect.TypeOf((*MockNoteService)(nil).UpdateNote), newNote, noteID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNote", reflect.TypeOf((*MockNoteService)(nil).UpdateNote), ctx, newNote, noteID, userID, version)
+}
+
+// UpdateNoteTags mocks base method.
+func (m *MockNoteService) UpdateNoteTags(ctx context.Context, noteID, userID string, update *dto.NoteTagsUpdate) (*dto.NoteTagsResp, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNoteTags", ctx, noteID, userID, update)
+	ret0, _ := ret[0].(*dto.NoteTagsResp)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateNoteTags indicates an expected call of UpdateNoteTags.
+func (mr *MockNoteServiceMockRecorder) UpdateNoteTags(ctx, noteID, userID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNoteTags", reflect.TypeOf((*MockNoteService)(nil).UpdateNoteTags), ctx, noteID, userID, update)
 }
 
 // MockTagService is a mock of TagService interface.
@@ -343,75 +556,620 @@ func (m *MockTagService) EXPECT() *MockTagServiceMockRecorder {
 }
 
 // CreateTag mocks base method.
-func (m *MockTagService) CreateTag(tag *model.Tag, userID string) (*model.Tag, error) {
+func (m *MockTagService) CreateTag(ctx context.Context, tag *model.Tag, userID string) (*model.Tag, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTag", tag, userID)
+	ret := m.ctrl.Call(m, "CreateTag", ctx, tag, userID)
 	ret0, _ := ret[0].(*model.Tag)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTag indicates an expected call of CreateTag.
-func (mr *MockTagServiceMockRecorder) CreateTag(tag, userID interface{}) *gomock.Call {
+func (mr *MockTagServiceMockRecorder) CreateTag(ctx, tag, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTagService)(nil).CreateTag), tag, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTagService)(nil).CreateTag), ctx, tag, userID)
 }
 
 // DeleteTag mocks base method.
-func (m *MockTagService) DeleteTag(tagID, userID string) (int, error) {
+func (m *MockTagService) DeleteTag(ctx context.Context, tagID, userID string, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTag", tagID, userID)
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, tagID, userID, version)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteTag indicates an expected call of DeleteTag.
-func (mr *MockTagServiceMockRecorder) DeleteTag(tagID, userID interface{}) *gomock.Call {
+func (mr *MockTagServiceMockRecorder) DeleteTag(ctx, tagID, userID, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagService)(nil).DeleteTag), tagID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagService)(nil).DeleteTag), ctx, tagID, userID, version)
 }
 
 // GetAllTagsByUser mocks base method.
-func (m *MockTagService) GetAllTagsByUser(userID string) ([]dto.TagsResp, error) {
+func (m *MockTagService) GetAllTagsByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.TagsResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTagsByUser", userID)
+	ret := m.ctrl.Call(m, "GetAllTagsByUser", ctx, userID, listQuery)
 	ret0, _ := ret[0].([]dto.TagsResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllTagsByUser indicates an expected call of GetAllTagsByUser.
-func (mr *MockTagServiceMockRecorder) GetAllTagsByUser(userID interface{}) *gomock.Call {
+func (mr *MockTagServiceMockRecorder) GetAllTagsByUser(ctx, userID, listQuery interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTagsByUser", reflect.TypeOf((*MockTagService)(nil).GetAllTagsByUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTagsByUser", reflect.TypeOf((*MockTagService)(nil).GetAllTagsByUser), ctx, userID, listQuery)
 }
 
 // GetTagByID mocks base method.
-func (m *MockTagService) GetTagByID(tagID, userID string) (*dto.TagResp, error) {
+func (m *MockTagService) GetTagByID(ctx context.Context, tagID, userID string) (*dto.TagResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTagByID", tagID, userID)
+	ret := m.ctrl.Call(m, "GetTagByID", ctx, tagID, userID)
 	ret0, _ := ret[0].(*dto.TagResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTagByID indicates an expected call of GetTagByID.
-func (mr *MockTagServiceMockRecorder) GetTagByID(tagID, userID interface{}) *gomock.Call {
+func (mr *MockTagServiceMockRecorder) GetTagByID(ctx, tagID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByID", reflect.TypeOf((*MockTagService)(nil).GetTagByID), tagID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByID", reflect.TypeOf((*MockTagService)(nil).GetTagByID), ctx, tagID, userID)
+}
+
+// GetTagDuplicates mocks base method.
+func (m *MockTagService) GetTagDuplicates(ctx context.Context, userID string) ([]dto.TagDuplicatesResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagDuplicates", ctx, userID)
+	ret0, _ := ret[0].([]dto.TagDuplicatesResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagDuplicates indicates an expected call of GetTagDuplicates.
+func (mr *MockTagServiceMockRecorder) GetTagDuplicates(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagDuplicates", reflect.TypeOf((*MockTagService)(nil).GetTagDuplicates), ctx, userID)
+}
+
+// GetTagStats mocks base method.
+func (m *MockTagService) GetTagStats(ctx context.Context, userID string) ([]dto.TagStatsResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagStats", ctx, userID)
+	ret0, _ := ret[0].([]dto.TagStatsResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagStats indicates an expected call of GetTagStats.
+func (mr *MockTagServiceMockRecorder) GetTagStats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagStats", reflect.TypeOf((*MockTagService)(nil).GetTagStats), ctx, userID)
+}
+
+// GetTagTree mocks base method.
+func (m *MockTagService) GetTagTree(ctx context.Context, userID string) ([]dto.TagTreeResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagTree", ctx, userID)
+	ret0, _ := ret[0].([]dto.TagTreeResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagTree indicates an expected call of GetTagTree.
+func (mr *MockTagServiceMockRecorder) GetTagTree(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagTree", reflect.TypeOf((*MockTagService)(nil).GetTagTree), ctx, userID)
+}
+
+// GetTagsPage mocks base method.
+func (m *MockTagService) GetTagsPage(ctx context.Context, userID string, listQuery *dto.ListQuery) (*dto.TagsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsPage", ctx, userID, listQuery)
+	ret0, _ := ret[0].(*dto.TagsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsPage indicates an expected call of GetTagsPage.
+func (mr *MockTagServiceMockRecorder) GetTagsPage(ctx, userID, listQuery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsPage", reflect.TypeOf((*MockTagService)(nil).GetTagsPage), ctx, userID, listQuery)
+}
+
+// MergeTags mocks base method.
+func (m *MockTagService) MergeTags(ctx context.Context, userID string, merge *dto.TagMerge) (*dto.TagMergeResp, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTags", ctx, userID, merge)
+	ret0, _ := ret[0].(*dto.TagMergeResp)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MergeTags indicates an expected call of MergeTags.
+func (mr *MockTagServiceMockRecorder) MergeTags(ctx, userID, merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTags", reflect.TypeOf((*MockTagService)(nil).MergeTags), ctx, userID, merge)
 }
 
 // UpdateTag mocks base method.
-func (m *MockTagService) UpdateTag(tag *dto.TagUpdate, tagID string) error {
+func (m *MockTagService) UpdateTag(ctx context.Context, tag *dto.TagUpdate, tagID string, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, tag, tagID, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockTagServiceMockRecorder) UpdateTag(ctx, tag, tagID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTagService)(nil).UpdateTag), ctx, tag, tagID, version)
+}
+
+// MockNotebookService is a mock of NotebookService interface.
+type MockNotebookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotebookServiceMockRecorder
+}
+
+// MockNotebookServiceMockRecorder is the mock recorder for MockNotebookService.
+type MockNotebookServiceMockRecorder struct {
+	mock *MockNotebookService
+}
+
+// NewMockNotebookService creates a new mock instance.
+func NewMockNotebookService(ctrl *gomock.Controller) *MockNotebookService {
+	mock := &MockNotebookService{ctrl: ctrl}
+	mock.recorder = &MockNotebookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotebookService) EXPECT() *MockNotebookServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateNotebook mocks base method.
+func (m *MockNotebookService) CreateNotebook(ctx context.Context, notebook *model.Notebook, userID string) (*model.Notebook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotebook", ctx, notebook, userID)
+	ret0, _ := ret[0].(*model.Notebook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotebook indicates an expected call of CreateNotebook.
+func (mr *MockNotebookServiceMockRecorder) CreateNotebook(ctx, notebook, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotebook", reflect.TypeOf((*MockNotebookService)(nil).CreateNotebook), ctx, notebook, userID)
+}
+
+// DeleteNotebook mocks base method.
+func (m *MockNotebookService) DeleteNotebook(ctx context.Context, notebookID, userID string, recursive bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotebook", ctx, notebookID, userID, recursive)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNotebook indicates an expected call of DeleteNotebook.
+func (mr *MockNotebookServiceMockRecorder) DeleteNotebook(ctx, notebookID, userID, recursive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotebook", reflect.TypeOf((*MockNotebookService)(nil).DeleteNotebook), ctx, notebookID, userID, recursive)
+}
+
+// GetNotebookByID mocks base method.
+func (m *MockNotebookService) GetNotebookByID(ctx context.Context, notebookID, userID string) (*dto.NotebookResp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTag", tag, tagID)
+	ret := m.ctrl.Call(m, "GetNotebookByID", ctx, notebookID, userID)
+	ret0, _ := ret[0].(*dto.NotebookResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotebookByID indicates an expected call of GetNotebookByID.
+func (mr *MockNotebookServiceMockRecorder) GetNotebookByID(ctx, notebookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotebookByID", reflect.TypeOf((*MockNotebookService)(nil).GetNotebookByID), ctx, notebookID, userID)
+}
+
+// GetNotebookNotes mocks base method.
+func (m *MockNotebookService) GetNotebookNotes(ctx context.Context, notebookID, userID string) ([]dto.NotesResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotebookNotes", ctx, notebookID, userID)
+	ret0, _ := ret[0].([]dto.NotesResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotebookNotes indicates an expected call of GetNotebookNotes.
+func (mr *MockNotebookServiceMockRecorder) GetNotebookNotes(ctx, notebookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotebookNotes", reflect.TypeOf((*MockNotebookService)(nil).GetNotebookNotes), ctx, notebookID, userID)
+}
+
+// GetNotebookTree mocks base method.
+func (m *MockNotebookService) GetNotebookTree(ctx context.Context, userID string) ([]dto.NotebookTreeResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotebookTree", ctx, userID)
+	ret0, _ := ret[0].([]dto.NotebookTreeResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotebookTree indicates an expected call of GetNotebookTree.
+func (mr *MockNotebookServiceMockRecorder) GetNotebookTree(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotebookTree", reflect.TypeOf((*MockNotebookService)(nil).GetNotebookTree), ctx, userID)
+}
+
+// MoveNotebook mocks base method.
+func (m *MockNotebookService) MoveNotebook(ctx context.Context, notebookID, userID string, parentID *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveNotebook", ctx, notebookID, userID, parentID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTag indicates an expected call of UpdateTag.
-func (mr *MockTagServiceMockRecorder) UpdateTag(tag, tagID interface{}) *gomock.Call {
+// MoveNotebook indicates an expected call of MoveNotebook.
+func (mr *MockNotebookServiceMockRecorder) MoveNotebook(ctx, notebookID, userID, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveNotebook", reflect.TypeOf((*MockNotebookService)(nil).MoveNotebook), ctx, notebookID, userID, parentID)
+}
+
+// RenameNotebook mocks base method.
+func (m *MockNotebookService) RenameNotebook(ctx context.Context, notebookID, userID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameNotebook", ctx, notebookID, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameNotebook indicates an expected call of RenameNotebook.
+func (mr *MockNotebookServiceMockRecorder) RenameNotebook(ctx, notebookID, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameNotebook", reflect.TypeOf((*MockNotebookService)(nil).RenameNotebook), ctx, notebookID, userID, name)
+}
+
+// SetNoteNotebook mocks base method.
+func (m *MockNotebookService) SetNoteNotebook(ctx context.Context, noteID, userID string, notebookID *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNoteNotebook", ctx, noteID, userID, notebookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNoteNotebook indicates an expected call of SetNoteNotebook.
+func (mr *MockNotebookServiceMockRecorder) SetNoteNotebook(ctx, noteID, userID, notebookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNoteNotebook", reflect.TypeOf((*MockNotebookService)(nil).SetNoteNotebook), ctx, noteID, userID, notebookID)
+}
+
+// MockAccessTokenService is a mock of AccessTokenService interface.
+type MockAccessTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenServiceMockRecorder
+}
+
+// MockAccessTokenServiceMockRecorder is the mock recorder for MockAccessTokenService.
+type MockAccessTokenServiceMockRecorder struct {
+	mock *MockAccessTokenService
+}
+
+// NewMockAccessTokenService creates a new mock instance.
+func NewMockAccessTokenService(ctrl *gomock.Controller) *MockAccessTokenService {
+	mock := &MockAccessTokenService{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenService) EXPECT() *MockAccessTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAccessToken mocks base method.
+func (m *MockAccessTokenService) CreateAccessToken(ctx context.Context, userID string, req *dto.AccessTokenReq) (*dto.AccessTokenCreatedResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessToken", ctx, userID, req)
+	ret0, _ := ret[0].(*dto.AccessTokenCreatedResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessToken indicates an expected call of CreateAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) CreateAccessToken(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).CreateAccessToken), ctx, userID, req)
+}
+
+// DeleteAccessToken mocks base method.
+func (m *MockAccessTokenService) DeleteAccessToken(ctx context.Context, tokenID, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessToken", ctx, tokenID, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessToken indicates an expected call of DeleteAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) DeleteAccessToken(ctx, tokenID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).DeleteAccessToken), ctx, tokenID, userID)
+}
+
+// GetAllAccessTokens mocks base method.
+func (m *MockAccessTokenService) GetAllAccessTokens(ctx context.Context, userID string) ([]dto.AccessTokenResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAccessTokens", ctx, userID)
+	ret0, _ := ret[0].([]dto.AccessTokenResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAccessTokens indicates an expected call of GetAllAccessTokens.
+func (mr *MockAccessTokenServiceMockRecorder) GetAllAccessTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAccessTokens", reflect.TypeOf((*MockAccessTokenService)(nil).GetAllAccessTokens), ctx, userID)
+}
+
+// MockTwoFactorService is a mock of TwoFactorService interface.
+type MockTwoFactorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorServiceMockRecorder
+}
+
+// MockTwoFactorServiceMockRecorder is the mock recorder for MockTwoFactorService.
+type MockTwoFactorServiceMockRecorder struct {
+	mock *MockTwoFactorService
+}
+
+// NewMockTwoFactorService creates a new mock instance.
+func NewMockTwoFactorService(ctrl *gomock.Controller) *MockTwoFactorService {
+	mock := &MockTwoFactorService{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorService) EXPECT() *MockTwoFactorServiceMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTwoFactorService) Confirm(ctx context.Context, userID, code string) (*dto.RecoveryCodesResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, code)
+	ret0, _ := ret[0].(*dto.RecoveryCodesResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorServiceMockRecorder) Confirm(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorService)(nil).Confirm), ctx, userID, code)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorServiceMockRecorder) Disable(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorService)(nil).Disable), ctx, userID, code)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactorService) Enroll(ctx context.Context, userID string) (*dto.TwoFactorEnrollResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(*dto.TwoFactorEnrollResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorServiceMockRecorder) Enroll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorService)(nil).Enroll), ctx, userID)
+}
+
+// MockLoginGuardService is a mock of LoginGuardService interface.
+type MockLoginGuardService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardServiceMockRecorder
+}
+
+// MockLoginGuardServiceMockRecorder is the mock recorder for MockLoginGuardService.
+type MockLoginGuardServiceMockRecorder struct {
+	mock *MockLoginGuardService
+}
+
+// NewMockLoginGuardService creates a new mock instance.
+func NewMockLoginGuardService(ctrl *gomock.Controller) *MockLoginGuardService {
+	mock := &MockLoginGuardService{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuardService) EXPECT() *MockLoginGuardServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginGuardService) Check(userName, clientIP string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", userName, clientIP)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginGuardServiceMockRecorder) Check(userName, clientIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginGuardService)(nil).Check), userName, clientIP)
+}
+
+// ClientIP mocks base method.
+func (m *MockLoginGuardService) ClientIP(remoteAddr, realIP string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientIP", remoteAddr, realIP)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientIP indicates an expected call of ClientIP.
+func (mr *MockLoginGuardServiceMockRecorder) ClientIP(remoteAddr, realIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientIP", reflect.TypeOf((*MockLoginGuardService)(nil).ClientIP), remoteAddr, realIP)
+}
+
+// Fail mocks base method.
+func (m *MockLoginGuardService) Fail(userName, clientIP string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Fail", userName, clientIP)
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginGuardServiceMockRecorder) Fail(userName, clientIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginGuardService)(nil).Fail), userName, clientIP)
+}
+
+// Reset mocks base method.
+func (m *MockLoginGuardService) Reset(userName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset", userName)
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginGuardServiceMockRecorder) Reset(userName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginGuardService)(nil).Reset), userName)
+}
+
+// MockAccountService is a mock of AccountService interface.
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+}
+
+// MockAccountServiceMockRecorder is the mock recorder for MockAccountService.
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+// NewMockAccountService creates a new mock instance.
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAccountService) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAccountServiceMockRecorder) ForgotPassword(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAccountService)(nil).ForgotPassword), ctx, email)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAccountService) ResendVerification(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAccountServiceMockRecorder) ResendVerification(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAccountService)(nil).ResendVerification), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAccountService) ResetPassword(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAccountServiceMockRecorder) ResetPassword(ctx, token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAccountService)(nil).ResetPassword), ctx, token, password)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAccountService) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAccountServiceMockRecorder) VerifyEmail(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAccountService)(nil).VerifyEmail), ctx, token)
+}
+
+// MockMailService is a mock of MailService interface.
+type MockMailService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailServiceMockRecorder
+}
+
+// MockMailServiceMockRecorder is the mock recorder for MockMailService.
+type MockMailServiceMockRecorder struct {
+	mock *MockMailService
+}
+
+// NewMockMailService creates a new mock instance.
+func NewMockMailService(ctrl *gomock.Controller) *MockMailService {
+	mock := &MockMailService{ctrl: ctrl}
+	mock.recorder = &MockMailServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailService) EXPECT() *MockMailServiceMockRecorder {
+	return m.recorder
+}
+
+// DispatchOutbox mocks base method.
+func (m *MockMailService) DispatchOutbox(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchOutbox", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchOutbox indicates an expected call of DispatchOutbox.
+func (mr *MockMailServiceMockRecorder) DispatchOutbox(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTagService)(nil).UpdateTag), tag, tagID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchOutbox", reflect.TypeOf((*MockMailService)(nil).DispatchOutbox), ctx)
 }
@@ -1,62 +1,254 @@
 package services
 
 import (
+	"context"
+	e "errors"
+	"strconv"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+
 	"web/internal/adapters/storage"
+	"web/internal/config"
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 )
 
+// currentRevision name of current note in revision diffs.
+const currentRevision = "current"
+
+// diffContext count of unchanged lines around changes in revision diffs.
+const diffContext = 3
+
 // noteService note service struct.
 type noteService struct {
 	storage storage.NoteStorage
+	cfg     config.Notes
 }
 
 // NewNoteService note service func builder.
-func NewNoteService(noteStorage storage.NoteStorage) NoteService {
-	return &noteService{storage: noteStorage}
+func NewNoteService(noteStorage storage.NoteStorage, cfg config.Notes) NoteService {
+	return &noteService{
+		storage: noteStorage,
+		cfg:     cfg,
+	}
 }
 
 // CreateNote create user note.
-func (n *noteService) CreateNote(note *model.Note, userID string) (*model.Note, error) {
-	return n.storage.CreateNote(note, userID)
+func (n *noteService) CreateNote(ctx context.Context, note *model.Note, userID string) (*model.Note, error) {
+	return n.storage.CreateNote(ctx, note, userID)
 }
 
 // GetNoteByID get note by ID.
-func (n *noteService) GetNoteByID(noteID, userID string) (*dto.NoteResp, error) {
-	return n.storage.GetNoteByID(noteID, userID)
+func (n *noteService) GetNoteByID(ctx context.Context, noteID, userID string) (*dto.NoteResp, error) {
+	return n.storage.GetNoteByID(ctx, noteID, userID)
+}
+
+// GetAllNotesByUser get all notes by user and list query filters.
+func (n *noteService) GetAllNotesByUser(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+) ([]dto.NotesResp, error) {
+	return n.storage.GetAllNotesByUser(ctx, userID, listQuery)
+}
+
+// GetNotesPage get page of notes by user, list query limit is page size.
+func (n *noteService) GetNotesPage(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+) (*dto.NotesPage, error) {
+	notes, err := n.storage.GetAllNotesByUser(ctx, userID, pageQuery(listQuery))
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := n.storage.CountNotesByUser(ctx, userID, listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.NotesPage{Notes: append(make([]dto.NotesResp, 0, len(notes)), notes...), Total: total}
+
+	if len(notes) > listQuery.Limit {
+		page.Notes = page.Notes[:listQuery.Limit]
+		last := page.Notes[len(page.Notes)-1]
+
+		page.NextCursor, err = nextCursor(listQuery, last.ID, last.Title, last.CreatedAt, last.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
 }
 
-// GetAllNotesByUser get all notes by user.
-func (n *noteService) GetAllNotesByUser(userID string) ([]dto.NotesResp, error) {
-	return n.storage.GetAllNotesByUser(userID)
+// SearchNotes full-text search of notes by user.
+func (n *noteService) SearchNotes(
+	ctx context.Context,
+	userID string,
+	searchQuery *dto.NoteSearchQuery,
+) ([]dto.NoteSearchResp, error) {
+	return n.storage.SearchNotes(ctx, userID, searchQuery)
 }
 
-// UpdateNote update note by ID.
-func (n *noteService) UpdateNote(newNote *dto.NoteUpdate, noteID string) error {
-	return n.storage.UpdateNote(newNote, noteID)
+// UpdateNote update note by ID, previous title and info of note are kept as its revision authored by the user
+// if the update changes them.
+// Note must have the version if it is not 0. New version of note is returned.
+func (n *noteService) UpdateNote(
+	ctx context.Context,
+	newNote *dto.NoteUpdate,
+	noteID, userID string,
+	version int,
+) (int, error) {
+	return n.storage.UpdateNote(ctx, newNote, noteID, userID, version, n.cfg.MaxRevisions)
 }
 
-// DeleteNote delete note by ID.
-func (n *noteService) DeleteNote(noteID, userID string) (int, error) {
-	return n.storage.DeleteNote(noteID, userID)
+// GetNoteRevisions get revisions of note by ID.
+func (n *noteService) GetNoteRevisions(ctx context.Context, noteID, userID string) ([]dto.NoteRevisionsResp, error) {
+	return n.storage.GetNoteRevisions(ctx, noteID, userID)
 }
 
-// SetTags set tags to note.
-func (n *noteService) SetTags(noteID string, tags map[string]string) (string, error) {
-	return n.storage.SetTags(noteID, tags)
+// GetNoteRevision get revision of note by ID and revision number.
+func (n *noteService) GetNoteRevision(ctx context.Context, noteID, rev, userID string) (*dto.NoteRevisionResp, error) {
+	return n.storage.GetNoteRevision(ctx, noteID, rev, userID)
 }
 
-// RemoveTags remove tags from note.
-func (n *noteService) RemoveTags(noteID string, tags map[string]string) (string, error) {
-	return n.storage.RemoveTags(noteID, tags)
+// DiffNoteRevision unified diff of note revision and revision to, without to the revision is compared with note.
+// If some revision does not exist its number is returned with errors.ErrNotFound.
+func (n *noteService) DiffNoteRevision(
+	ctx context.Context,
+	noteID, rev, to, userID string,
+) (*dto.NoteRevisionDiffResp, string, error) {
+	from, err := n.storage.GetNoteRevision(ctx, noteID, rev, userID)
+	if err != nil {
+		return nil, rev, err
+	}
+
+	resp := &dto.NoteRevisionDiffResp{From: rev, To: to}
+	toTitle, toInfo := "", ""
+
+	if to == "" {
+		note, err := n.storage.GetNoteByID(ctx, noteID, userID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		resp.To, toTitle, toInfo = currentRevision, note.Title, note.Info
+	} else {
+		revision, err := n.storage.GetNoteRevision(ctx, noteID, to, userID)
+		if err != nil {
+			return nil, to, err
+		}
+
+		toTitle, toInfo = revision.Title, revision.Info
+	}
+
+	resp.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(from.Title, from.Info)),
+		B:        difflib.SplitLines(revisionText(toTitle, toInfo)),
+		FromFile: revisionName(resp.From),
+		ToFile:   revisionName(resp.To),
+		Context:  diffContext,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp, "", nil
+}
+
+// RestoreNoteRevision set title and info of note by ID from its revision, it is an update which adds revision too.
+// If user has another note with the revision title errors.ErrNoteTitleTaken is returned.
+func (n *noteService) RestoreNoteRevision(ctx context.Context, noteID, rev, userID string) error {
+	revision, err := n.storage.GetNoteRevision(ctx, noteID, rev, userID)
+	if err != nil {
+		return err
+	}
+
+	newNote := &dto.NoteUpdate{Title: &revision.Title, Info: &revision.Info}
+
+	_, err = n.storage.UpdateNote(ctx, newNote, noteID, userID, 0, n.cfg.MaxRevisions)
+	if e.Is(err, errors.ErrConflict) {
+		return errors.ErrNoteTitleTaken.Wrap(err)
+	}
+
+	return err
+}
+
+// revisionText text of note in diffs, title is the first line.
+func revisionText(title, info string) string {
+	return title + "\n\n" + info
+}
+
+// revisionName name of revision in diff header.
+func revisionName(rev string) string {
+	if rev == currentRevision {
+		return rev
+	}
+
+	return "rev " + rev
+}
+
+// DeleteNote move note by ID to trash, note must have the version if it is not 0.
+func (n *noteService) DeleteNote(ctx context.Context, noteID, userID string, version int) (int, error) {
+	return n.storage.DeleteNote(ctx, noteID, userID, version)
+}
+
+// GetTrash get trashed notes by user.
+func (n *noteService) GetTrash(ctx context.Context, userID string) ([]dto.TrashNoteResp, error) {
+	return n.storage.GetTrash(ctx, userID)
+}
+
+// RestoreNote restore note by ID from trash.
+func (n *noteService) RestoreNote(ctx context.Context, noteID, userID string) (int, error) {
+	return n.storage.RestoreNote(ctx, noteID, userID)
+}
+
+// PurgeNote delete trashed note by ID permanently.
+func (n *noteService) PurgeNote(ctx context.Context, noteID, userID string) (int, error) {
+	return n.storage.PurgeNote(ctx, noteID, userID)
+}
+
+// PurgeTrash delete notes which are in trash longer than retention period permanently. Returns count of them.
+func (n *noteService) PurgeTrash(ctx context.Context) (int, error) {
+	return n.storage.PurgeTrash(ctx, time.Now().Add(-n.cfg.TrashRetention))
+}
+
+// UpdateNoteTags replace tags of note or add and remove them, a tag can not be both added and removed.
+func (n *noteService) UpdateNoteTags(
+	ctx context.Context,
+	noteID, userID string,
+	update *dto.NoteTagsUpdate,
+) (*dto.NoteTagsResp, string, error) {
+	removed := make(map[int64]bool, len(update.Remove))
+	for _, id := range update.Remove {
+		removed[id] = true
+	}
+
+	for _, id := range update.Add {
+		if removed[id] {
+			return nil, strconv.FormatInt(id, 10), errors.ErrTagsAddRemove
+		}
+	}
+
+	return n.storage.UpdateNoteTags(ctx, noteID, userID, update)
 }
 
-// GetAllNotesWithTags get all notes with tags by user.
-func (n *noteService) GetAllNotesWithTags(userID string, notes []dto.NotesResp) ([]dto.NoteWithTagsResp, error) {
-	return n.storage.GetAllNotesWithTags(userID, notes)
+// GetAllNotesWithTags stream notes with tags by user and list query filters to fn.
+func (n *noteService) GetAllNotesWithTags(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+	includeUntagged bool,
+	fn func(note *dto.NoteWithTagsResp) error,
+) error {
+	return n.storage.GetAllNotesWithTags(ctx, userID, listQuery, includeUntagged, fn)
 }
 
 // GetNoteWithAllTags get note by id with all tags by user.
-func (n *noteService) GetNoteWithAllTags(userID, noteID string, note *dto.NoteResp) (dto.NoteWithTagsResp, error) {
-	return n.storage.GetNoteWithAllTags(userID, noteID, note)
+func (n *noteService) GetNoteWithAllTags(ctx context.Context, userID, noteID string) (dto.NoteWithTagsResp, error) {
+	return n.storage.GetNoteWithAllTags(ctx, userID, noteID)
 }
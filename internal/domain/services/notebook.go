@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+
+	"web/internal/adapters/storage"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+)
+
+// notebookService notebook service struct.
+type notebookService struct {
+	storage storage.NotebookStorage
+}
+
+// NewNotebookService notebook service func builder.
+func NewNotebookService(notebookStorage storage.NotebookStorage) NotebookService {
+	return &notebookService{storage: notebookStorage}
+}
+
+// CreateNotebook create notebook.
+func (n *notebookService) CreateNotebook(
+	ctx context.Context,
+	notebook *model.Notebook,
+	userID string,
+) (*model.Notebook, error) {
+	return n.storage.CreateNotebook(ctx, notebook, userID)
+}
+
+// GetNotebookByID get notebook by ID.
+func (n *notebookService) GetNotebookByID(ctx context.Context, notebookID, userID string) (*dto.NotebookResp, error) {
+	return n.storage.GetNotebookByID(ctx, notebookID, userID)
+}
+
+// GetNotebookTree get tree of user notebooks, root notebooks and children of every notebook are ordered by name.
+func (n *notebookService) GetNotebookTree(ctx context.Context, userID string) ([]dto.NotebookTreeResp, error) {
+	notebooks, err := n.storage.GetAllNotebooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]dto.NotebookResp, len(notebooks))
+	for _, notebook := range notebooks {
+		parentID := ""
+		if notebook.ParentID != nil {
+			parentID = *notebook.ParentID
+		}
+
+		children[parentID] = append(children[parentID], notebook)
+	}
+
+	return notebookTree(children, ""), nil
+}
+
+// notebookTree tree of child notebooks of parent by children of every notebook, root parent is "".
+func notebookTree(children map[string][]dto.NotebookResp, parentID string) []dto.NotebookTreeResp {
+	tree := make([]dto.NotebookTreeResp, 0, len(children[parentID]))
+	for _, notebook := range children[parentID] {
+		tree = append(tree, dto.NotebookTreeResp{
+			ID:        notebook.ID,
+			Name:      notebook.Name,
+			NoteCount: notebook.NoteCount,
+			Children:  notebookTree(children, notebook.ID),
+		})
+	}
+
+	return tree
+}
+
+// GetNotebookNotes get notes right in the notebook.
+func (n *notebookService) GetNotebookNotes(ctx context.Context, notebookID, userID string) ([]dto.NotesResp, error) {
+	return n.storage.GetNotebookNotes(ctx, notebookID, userID)
+}
+
+// RenameNotebook rename notebook by ID.
+func (n *notebookService) RenameNotebook(ctx context.Context, notebookID, userID, name string) error {
+	return n.storage.RenameNotebook(ctx, notebookID, userID, name)
+}
+
+// MoveNotebook move notebook by ID to parent notebook, nil parent is root.
+func (n *notebookService) MoveNotebook(ctx context.Context, notebookID, userID string, parentID *int64) error {
+	return n.storage.MoveNotebook(ctx, notebookID, userID, parentID)
+}
+
+// DeleteNotebook delete notebook by ID, its contents are deleted if recursive, otherwise moved to its parent.
+func (n *notebookService) DeleteNotebook(ctx context.Context, notebookID, userID string, recursive bool) (int, error) {
+	return n.storage.DeleteNotebook(ctx, notebookID, userID, recursive)
+}
+
+// SetNoteNotebook move note by ID to notebook, nil notebook removes note from its notebook.
+func (n *notebookService) SetNoteNotebook(ctx context.Context, noteID, userID string, notebookID *int64) error {
+	return n.storage.SetNoteNotebook(ctx, noteID, userID, notebookID)
+}
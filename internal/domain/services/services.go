@@ -2,64 +2,158 @@
 package services
 
 import (
+	"context"
+	"time"
+
 	"web/internal/adapters/storage"
+	"web/internal/config"
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/utils/dictionary"
+	"web/pkg/hasher"
+	"web/pkg/jwtkeys"
+	"web/pkg/mailer"
 )
 
 //go:generate mockgen -source=services.go -destination=mocks/mock.go
 
 // UserAuthService Auth interface.
 type UserAuthService interface {
-	RegisterUser(user *model.User) (*model.User, error)
-	GenerateToken(userName, password string) (string, error)
-	ParseToken(token string) (string, error)
+	RegisterUser(ctx context.Context, user *model.User) (*model.User, error)
+	GenerateToken(ctx context.Context, userName, password string) (*dto.LoginResp, error)
+	ChallengeUsername(ctx context.Context, challenge string) (string, error)
+	VerifyTwoFactor(ctx context.Context, challenge, code string) (*dto.TokensResp, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokensResp, error)
+	Logout(ctx context.Context, tokenID string) error
+	ParseToken(ctx context.Context, token string) (*dictionary.TokenClaims, error)
+	JWKS() *jwtkeys.JWKS
 }
 
 // UserService User interface.
 type UserService interface {
-	GetUserByID(id string) (*dto.UserResp, error)
-	GetAllUsers() ([]dto.UserResp, error)
-	UpdateUser(newUser *dto.UserUpdate, userID string) error
-	DeleteUser(id string) (int, error)
+	GetUserByID(ctx context.Context, id string) (*dto.UserResp, error)
+	GetAllUsers(ctx context.Context, listQuery *dto.ListQuery) ([]dto.UserResp, error)
+	UpdateUser(ctx context.Context, newUser *dto.UserUpdate, userID string) error
+	DeleteUser(ctx context.Context, id string) (int, error)
 }
 
 // NoteService Note interface.
 type NoteService interface {
-	CreateNote(note *model.Note, userID string) (*model.Note, error)
-	GetNoteByID(noteID, userID string) (*dto.NoteResp, error)
-	GetAllNotesByUser(userID string) ([]dto.NotesResp, error)
-	UpdateNote(newNote *dto.NoteUpdate, noteID string) error
-	DeleteNote(noteID, userID string) (int, error)
-	SetTags(noteID string, tags map[string]string) (string, error)
-	RemoveTags(noteID string, tags map[string]string) (string, error)
-	GetAllNotesWithTags(userID string, notes []dto.NotesResp) ([]dto.NoteWithTagsResp, error)
-	GetNoteWithAllTags(userID, NoteID string, note *dto.NoteResp) (dto.NoteWithTagsResp, error)
+	CreateNote(ctx context.Context, note *model.Note, userID string) (*model.Note, error)
+	GetNoteByID(ctx context.Context, noteID, userID string) (*dto.NoteResp, error)
+	GetAllNotesByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.NotesResp, error)
+	GetNotesPage(ctx context.Context, userID string, listQuery *dto.ListQuery) (*dto.NotesPage, error)
+	SearchNotes(ctx context.Context, userID string, searchQuery *dto.NoteSearchQuery) ([]dto.NoteSearchResp, error)
+	UpdateNote(ctx context.Context, newNote *dto.NoteUpdate, noteID, userID string, version int) (int, error)
+	GetNoteRevisions(ctx context.Context, noteID, userID string) ([]dto.NoteRevisionsResp, error)
+	GetNoteRevision(ctx context.Context, noteID, rev, userID string) (*dto.NoteRevisionResp, error)
+	DiffNoteRevision(ctx context.Context, noteID, rev, to, userID string) (*dto.NoteRevisionDiffResp, string, error)
+	RestoreNoteRevision(ctx context.Context, noteID, rev, userID string) error
+	DeleteNote(ctx context.Context, noteID, userID string, version int) (int, error)
+	GetTrash(ctx context.Context, userID string) ([]dto.TrashNoteResp, error)
+	RestoreNote(ctx context.Context, noteID, userID string) (int, error)
+	PurgeNote(ctx context.Context, noteID, userID string) (int, error)
+	PurgeTrash(ctx context.Context) (int, error)
+	UpdateNoteTags(ctx context.Context, noteID, userID string,
+		update *dto.NoteTagsUpdate) (*dto.NoteTagsResp, string, error)
+	GetAllNotesWithTags(ctx context.Context, userID string, listQuery *dto.ListQuery, includeUntagged bool,
+		fn func(note *dto.NoteWithTagsResp) error) error
+	GetNoteWithAllTags(ctx context.Context, userID, NoteID string) (dto.NoteWithTagsResp, error)
 }
 
 // TagService Tag interface.
 type TagService interface {
-	CreateTag(tag *model.Tag, userID string) (*model.Tag, error)
-	GetTagByID(tagID, userID string) (*dto.TagResp, error)
-	GetAllTagsByUser(userID string) ([]dto.TagsResp, error)
-	UpdateTag(tag *dto.TagUpdate, tagID string) error
-	DeleteTag(tagID, userID string) (int, error)
+	CreateTag(ctx context.Context, tag *model.Tag, userID string) (*model.Tag, error)
+	GetTagByID(ctx context.Context, tagID, userID string) (*dto.TagResp, error)
+	GetAllTagsByUser(ctx context.Context, userID string, listQuery *dto.ListQuery) ([]dto.TagsResp, error)
+	GetTagsPage(ctx context.Context, userID string, listQuery *dto.ListQuery) (*dto.TagsPage, error)
+	GetTagTree(ctx context.Context, userID string) ([]dto.TagTreeResp, error)
+	GetTagStats(ctx context.Context, userID string) ([]dto.TagStatsResp, error)
+	GetTagDuplicates(ctx context.Context, userID string) ([]dto.TagDuplicatesResp, error)
+	MergeTags(ctx context.Context, userID string, merge *dto.TagMerge) (*dto.TagMergeResp, string, error)
+	UpdateTag(ctx context.Context, tag *dto.TagUpdate, tagID string, version int) (int, error)
+	DeleteTag(ctx context.Context, tagID, userID string, version int) (int, error)
+}
+
+// NotebookService Notebook interface.
+type NotebookService interface {
+	CreateNotebook(ctx context.Context, notebook *model.Notebook, userID string) (*model.Notebook, error)
+	GetNotebookByID(ctx context.Context, notebookID, userID string) (*dto.NotebookResp, error)
+	GetNotebookTree(ctx context.Context, userID string) ([]dto.NotebookTreeResp, error)
+	GetNotebookNotes(ctx context.Context, notebookID, userID string) ([]dto.NotesResp, error)
+	RenameNotebook(ctx context.Context, notebookID, userID, name string) error
+	MoveNotebook(ctx context.Context, notebookID, userID string, parentID *int64) error
+	DeleteNotebook(ctx context.Context, notebookID, userID string, recursive bool) (int, error)
+	SetNoteNotebook(ctx context.Context, noteID, userID string, notebookID *int64) error
+}
+
+// AccessTokenService AccessToken interface.
+type AccessTokenService interface {
+	CreateAccessToken(ctx context.Context, userID string, req *dto.AccessTokenReq) (*dto.AccessTokenCreatedResp, error)
+	GetAllAccessTokens(ctx context.Context, userID string) ([]dto.AccessTokenResp, error)
+	DeleteAccessToken(ctx context.Context, tokenID, userID string) (int, error)
+}
+
+// TwoFactorService TwoFactor interface.
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userID string) (*dto.TwoFactorEnrollResp, error)
+	Confirm(ctx context.Context, userID, code string) (*dto.RecoveryCodesResp, error)
+	Disable(ctx context.Context, userID, code string) error
+}
+
+// LoginGuardService LoginGuard interface.
+type LoginGuardService interface {
+	Check(userName, clientIP string) (time.Duration, error)
+	Fail(userName, clientIP string)
+	Reset(userName string)
+	ClientIP(remoteAddr, realIP string) string
+}
+
+// AccountService Account interface.
+type AccountService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID string) error
+}
+
+// MailService Mail outbox interface.
+type MailService interface {
+	DispatchOutbox(ctx context.Context) (int, error)
 }
 
 // Services struct of services interfaces.
 type Services struct {
-	Auth UserAuthService
-	User UserService
-	Note NoteService
-	Tag  TagService
+	Auth        UserAuthService
+	User        UserService
+	Note        NoteService
+	Tag         TagService
+	Notebook    NotebookService
+	AccessToken AccessTokenService
+	TwoFactor   TwoFactorService
+	LoginGuard  LoginGuardService
+	Account     AccountService
+	Mail        MailService
 }
 
 // NewServices services func builder.
-func NewServices(storages *storage.Storages) *Services {
+func NewServices(
+	storages *storage.Storages,
+	passwordHasher *hasher.Manager,
+	keys *jwtkeys.KeySet,
+	sender mailer.Sender,
+	cfg *config.Config,
+) *Services {
 	return &Services{
-		Auth: NewAuthService(storages.Auth),
-		User: NewUserService(storages.User),
-		Note: NewNoteService(storages.Note),
-		Tag:  NewTagService(storages.Tag),
+		Auth:        NewAuthService(storages, passwordHasher, keys, cfg),
+		User:        NewUserService(storages.User, storages.Token, passwordHasher),
+		Note:        NewNoteService(storages.Note, cfg.Notes),
+		Tag:         NewTagService(storages.Tag),
+		Notebook:    NewNotebookService(storages.Notebook),
+		AccessToken: NewAccessTokenService(storages.AccessToken),
+		TwoFactor:   NewTwoFactorService(storages.TwoFactor, cfg.TwoFactor),
+		LoginGuard:  NewLoginGuardService(cfg.App),
+		Account:     NewAccountService(storages.Account, storages.Token, passwordHasher, cfg.Mail),
+		Mail:        NewMailService(storages.Mail, sender, cfg.Mail),
 	}
 }
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
 	"web/internal/adapters/storage"
 	"web/internal/domain/entities/dto"
 	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
 )
 
 // tagService tag service struct.
@@ -17,26 +23,134 @@ func NewTagService(tagStorage storage.TagStorage) TagService {
 }
 
 // CreateTag create tag.
-func (t *tagService) CreateTag(tag *model.Tag, userID string) (*model.Tag, error) {
-	return t.storage.CreateTag(tag, userID)
+func (t *tagService) CreateTag(ctx context.Context, tag *model.Tag, userID string) (*model.Tag, error) {
+	return t.storage.CreateTag(ctx, tag, userID)
 }
 
 // GetTagByID get tag by ID.
-func (t *tagService) GetTagByID(tagID, userID string) (*dto.TagResp, error) {
-	return t.storage.GetTagByID(tagID, userID)
+func (t *tagService) GetTagByID(ctx context.Context, tagID, userID string) (*dto.TagResp, error) {
+	return t.storage.GetTagByID(ctx, tagID, userID)
+}
+
+// GetAllTagsByUser get tag by user and list query filters.
+func (t *tagService) GetAllTagsByUser(
+	ctx context.Context,
+	userID string,
+	listQuery *dto.ListQuery,
+) ([]dto.TagsResp, error) {
+	return t.storage.GetAllTagsByUser(ctx, userID, listQuery)
+}
+
+// GetTagsPage get page of tags by user, list query limit is page size.
+func (t *tagService) GetTagsPage(ctx context.Context, userID string, listQuery *dto.ListQuery) (*dto.TagsPage, error) {
+	tags, err := t.storage.GetAllTagsByUser(ctx, userID, pageQuery(listQuery))
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := t.storage.CountTagsByUser(ctx, userID, listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.TagsPage{Tags: append(make([]dto.TagsResp, 0, len(tags)), tags...), Total: total}
+
+	if len(tags) > listQuery.Limit {
+		page.Tags = page.Tags[:listQuery.Limit]
+		last := page.Tags[len(page.Tags)-1]
+
+		page.NextCursor, err = nextCursor(listQuery, last.ID, last.TagName, last.CreatedAt, last.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// GetTagTree get tree of user tags with note counts, root tags and children of every tag are ordered by name.
+func (t *tagService) GetTagTree(ctx context.Context, userID string) ([]dto.TagTreeResp, error) {
+	tags, err := t.storage.GetTagTree(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]dto.TagTreeResp, len(tags))
+	for _, tag := range tags {
+		parentID := ""
+		if tag.ParentID != nil {
+			parentID = *tag.ParentID
+		}
+
+		children[parentID] = append(children[parentID], tag)
+	}
+
+	return tagTree(children, ""), nil
+}
+
+// tagTree tree of child tags of parent by children of every tag, root parent is "".
+func tagTree(children map[string][]dto.TagTreeResp, parentID string) []dto.TagTreeResp {
+	tree := make([]dto.TagTreeResp, 0, len(children[parentID]))
+	for _, tag := range children[parentID] {
+		tag.Children = tagTree(children, tag.ID)
+		tree = append(tree, tag)
+	}
+
+	return tree
+}
+
+// GetTagStats get user tags with note counts and last use time ordered by name.
+func (t *tagService) GetTagStats(ctx context.Context, userID string) ([]dto.TagStatsResp, error) {
+	return t.storage.GetTagStats(ctx, userID)
+}
+
+// GetTagDuplicates get groups of user tags with names equal ignoring case, groups are ordered by name.
+func (t *tagService) GetTagDuplicates(ctx context.Context, userID string) ([]dto.TagDuplicatesResp, error) {
+	tags, err := t.storage.GetTagStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]dto.TagStatsResp, len(tags))
+	for _, tag := range tags {
+		name := strings.ToLower(tag.TagName)
+		groups[name] = append(groups[name], tag)
+	}
+
+	duplicates := make([]dto.TagDuplicatesResp, 0)
+
+	for name, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, dto.TagDuplicatesResp{TagName: name, Tags: group})
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].TagName < duplicates[j].TagName })
+
+	return duplicates, nil
 }
 
-// GetAllTagsByUser get tag by user.
-func (t *tagService) GetAllTagsByUser(userID string) ([]dto.TagsResp, error) {
-	return t.storage.GetAllTagsByUser(userID)
+// MergeTags merge source tags into target tag, target tag can not be one of source tags.
+func (t *tagService) MergeTags(
+	ctx context.Context,
+	userID string,
+	merge *dto.TagMerge,
+) (*dto.TagMergeResp, string, error) {
+	for _, id := range merge.SourceIDs {
+		if id == merge.TargetID {
+			return nil, strconv.FormatInt(id, 10), errors.ErrTagMergeTarget
+		}
+	}
+
+	return t.storage.MergeTags(ctx, userID, merge)
 }
 
-// UpdateTag update tag by ID.
-func (t *tagService) UpdateTag(tag *dto.TagUpdate, tagID string) error {
-	return t.storage.UpdateTag(tag, tagID)
+// UpdateTag update tag by ID, tag must have the version if it is not 0. New version of tag is returned.
+func (t *tagService) UpdateTag(ctx context.Context, tag *dto.TagUpdate, tagID string, version int) (int, error) {
+	return t.storage.UpdateTag(ctx, tag, tagID, version)
 }
 
-// DeleteTag delete tag by ID.
-func (t *tagService) DeleteTag(tagID, userID string) (int, error) {
-	return t.storage.DeleteTag(tagID, userID)
+// DeleteTag delete tag by ID, tag must have the version if it is not 0.
+func (t *tagService) DeleteTag(ctx context.Context, tagID, userID string, version int) (int, error) {
+	return t.storage.DeleteTag(ctx, tagID, userID, version)
 }
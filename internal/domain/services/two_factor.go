@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"web/internal/adapters/storage"
+	"web/internal/config"
+	"web/internal/domain/entities/dto"
+	"web/internal/domain/entities/model"
+	"web/internal/domain/errors"
+	"web/pkg/totp"
+)
+
+// recoveryCodeLen recovery code random bytes length, 10 base32 chars.
+const recoveryCodeLen = 6
+
+// twoFactorService two-factor auth service struct.
+type twoFactorService struct {
+	storage storage.TwoFactorStorage
+	cfg     config.TwoFactor
+}
+
+// NewTwoFactorService two-factor auth service func builder.
+func NewTwoFactorService(twoFactorStorage storage.TwoFactorStorage, cfg config.TwoFactor) TwoFactorService {
+	return &twoFactorService{
+		storage: twoFactorStorage,
+		cfg:     cfg,
+	}
+}
+
+// Enroll make new TOTP secret for user. It is not used for login until confirmed with a code.
+func (t *twoFactorService) Enroll(ctx context.Context, userID string) (*dto.TwoFactorEnrollResp, error) {
+	twoFactor, err := t.storage.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor.Enabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.storage.SetSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollResp{
+		Secret:          secret,
+		ProvisioningURI: totp.URI(t.cfg.Issuer, twoFactor.Username, secret),
+	}, nil
+}
+
+// Confirm enable two-factor auth with the first code from authenticator app and make recovery codes.
+func (t *twoFactorService) Confirm(ctx context.Context, userID, code string) (*dto.RecoveryCodesResp, error) {
+	twoFactor, err := t.storage.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor.Enabled {
+		return nil, errors.ErrTwoFactorEnabled
+	}
+
+	if !twoFactor.Secret.Valid {
+		return nil, errors.ErrTwoFactorNotEnabled
+	}
+
+	step, ok, err := totp.Validate(twoFactor.Secret.String, code, time.Now(), t.cfg.Skew)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errors.ErrInvalidTwoFactor
+	}
+
+	codes := make([]string, 0, t.cfg.RecoveryCodes)
+	hashes := make([]string, 0, t.cfg.RecoveryCodes)
+
+	for i := 0; i < t.cfg.RecoveryCodes; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := t.storage.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// Disable turn two-factor auth off, TOTP or recovery code is required.
+func (t *twoFactorService) Disable(ctx context.Context, userID, code string) error {
+	twoFactor, err := t.storage.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !twoFactor.Enabled {
+		return errors.ErrTwoFactorNotEnabled
+	}
+
+	ok, err := verifySecondFactor(ctx, t.storage, t.cfg, twoFactor, code)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.ErrInvalidTwoFactor
+	}
+
+	return t.storage.Disable(ctx, userID)
+}
+
+// verifySecondFactor check TOTP code or recovery code of user.
+// Every TOTP code and recovery code can be used only once.
+func verifySecondFactor(
+	ctx context.Context,
+	twoFactorStorage storage.TwoFactorStorage,
+	cfg config.TwoFactor,
+	twoFactor *model.TwoFactor,
+	code string,
+) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		step, ok, err := totp.Validate(twoFactor.Secret.String, code, time.Now(), cfg.Skew)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		return twoFactorStorage.UseStep(ctx, twoFactor.UserID, step)
+	}
+
+	return twoFactorStorage.UseRecoveryCode(ctx, twoFactor.UserID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// newRecoveryCode make random recovery code like "abcde-fghij".
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeLen)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))[:10]
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode recovery codes are accepted in any case and with or without dash.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -1,36 +1,67 @@
 package services
 
 import (
+	"context"
+
 	"web/internal/adapters/storage"
 	"web/internal/domain/entities/dto"
+	"web/pkg/hasher"
 )
 
 // userService user service struct.
 type userService struct {
-	storage storage.UserStorage
+	storage      storage.UserStorage
+	tokenStorage storage.TokenStorage
+	hasher       *hasher.Manager
 }
 
 // NewUserService user service func builder.
-func NewUserService(userStorage storage.UserStorage) UserService {
-	return &userService{storage: userStorage}
+func NewUserService(
+	userStorage storage.UserStorage,
+	tokenStorage storage.TokenStorage,
+	passwordHasher *hasher.Manager,
+) UserService {
+	return &userService{
+		storage:      userStorage,
+		tokenStorage: tokenStorage,
+		hasher:       passwordHasher,
+	}
 }
 
 // GetUserByID get user by ID.
-func (u *userService) GetUserByID(id string) (*dto.UserResp, error) {
-	return u.storage.GetUserByID(id)
+func (u *userService) GetUserByID(ctx context.Context, id string) (*dto.UserResp, error) {
+	return u.storage.GetUserByID(ctx, id)
 }
 
-// GetAllUsers get all users.
-func (u *userService) GetAllUsers() ([]dto.UserResp, error) {
-	return u.storage.GetAllUsers()
+// GetAllUsers get all users by list query filters.
+func (u *userService) GetAllUsers(ctx context.Context, listQuery *dto.ListQuery) ([]dto.UserResp, error) {
+	return u.storage.GetAllUsers(ctx, listQuery)
 }
 
-// UpdateUser update user by ID.
-func (u *userService) UpdateUser(newUser *dto.UserUpdate, userID string) error {
-	return u.storage.UpdateUser(newUser, userID)
+// UpdateUser update user by ID. Password or role change closes all user sessions.
+func (u *userService) UpdateUser(ctx context.Context, newUser *dto.UserUpdate, userID string) error {
+	if newUser.Password == nil && newUser.Role == nil {
+		return u.storage.UpdateUser(ctx, newUser, userID)
+	}
+
+	update := *newUser
+
+	if newUser.Password != nil {
+		hash, err := u.hasher.Hash(*newUser.Password)
+		if err != nil {
+			return err
+		}
+		update.Password = &hash
+	}
+
+	if err := u.storage.UpdateUser(ctx, &update, userID); err != nil {
+		return err
+	}
+
+	return u.tokenStorage.RevokeAllSessions(ctx, userID)
 }
 
 // DeleteUser delete user by ID.
-func (u *userService) DeleteUser(id string) (int, error) {
-	return u.storage.DeleteUser(id)
+func (u *userService) DeleteUser(ctx context.Context, id string) (int, error) {
+	return u.storage.DeleteUser(ctx, id)
 }
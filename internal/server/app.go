@@ -18,14 +18,19 @@ import (
 
 	"web/api"
 	"web/internal/adapters/router/handlers/note"
+	"web/internal/adapters/router/handlers/notebook"
 	"web/internal/adapters/router/handlers/tag"
 	"web/internal/adapters/router/handlers/user"
 	"web/internal/adapters/router/swagger"
 	s "web/internal/adapters/storage"
 	"web/internal/config"
 	"web/internal/domain/services"
+	"web/internal/utils/dictionary"
 	"web/pkg/database"
+	"web/pkg/hasher"
+	"web/pkg/jwtkeys"
 	l "web/pkg/logger"
+	"web/pkg/mailer"
 )
 
 // Execute main service func.
@@ -45,15 +50,31 @@ func Execute() {
 	// router create
 	router := httprouter.New()
 	// storage create
-	storage := s.NewStorages(db)
+	storage := s.NewStorages(db, cfg.Postgres.QueryTimeout)
+	// password hasher create
+	passwordHasher, err := hasher.NewHasher(cfg, dictionary.Salt)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to init password hasher: %s", err.Error()))
+	}
+	// jwt signing keys create
+	keys, err := jwtkeys.NewKeySetFromConfig(cfg)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to init jwt keys: %s", err.Error()))
+	}
+	// mail sender create
+	sender, err := mailer.NewSender(cfg, logger)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to init mail sender: %s", err.Error()))
+	}
 	// services (usecases) create
-	service := services.NewServices(storage)
+	service := services.NewServices(storage, passwordHasher, keys, sender, cfg)
 	// swagger handler register
 	swagger.Register(router)
 	// service handlers register
 	user.Register(router, service, loggingMiddleware)
 	note.Register(router, service, loggingMiddleware)
 	tag.Register(router, service, loggingMiddleware)
+	notebook.Register(router, service, loggingMiddleware)
 	// server create
 	srv := NewServer(cfg, router)
 	// server start
@@ -63,6 +84,24 @@ func Execute() {
 		}
 	}()
 
+	// mail outbox dispatcher start
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	outboxDone := make(chan struct{})
+
+	go func() {
+		defer close(outboxDone)
+		runOutbox(outboxCtx, service.Mail, cfg.Mail.PollInterval, logger)
+	}()
+
+	// notes trash purger start
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+
+	go func() {
+		defer close(purgeDone)
+		runTrashPurge(purgeCtx, service.Note, cfg.Notes.PurgeInterval, logger)
+	}()
+
 	logger.Info("Starting server...")
 
 	// Graceful Shutdown
@@ -80,6 +119,12 @@ func Execute() {
 	if err := srv.Stop(ctx); err != nil {
 		logger.Error(fmt.Sprintf("error occurred on srv shutting down: %s\n", err.Error()))
 	}
+	// mail outbox dispatcher stop, the current batch is finished before db is closed
+	stopOutbox()
+	<-outboxDone
+	// notes trash purger stop, the current purge is finished before db is closed
+	stopPurge()
+	<-purgeDone
 	// close connection with DB
 	if err := db.Close(); err != nil {
 		logger.Error(fmt.Sprintf("error occurred on db connection close: %s\n", err.Error()))
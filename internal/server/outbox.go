@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"web/internal/domain/services"
+)
+
+// runOutbox dispatch mail outbox every interval until ctx is canceled.
+func runOutbox(ctx context.Context, mail services.MailService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := mail.DispatchOutbox(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("failed to dispatch mail outbox", zap.Error(err))
+			}
+
+			if sent > 0 {
+				logger.Info("mail outbox dispatched", zap.Int("sent", sent))
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"time"
 
@@ -13,18 +14,23 @@ import (
 // Server struct.
 type Server struct {
 	httpServer *http.Server
+	cancel     context.CancelFunc
 }
 
 // NewServer server func builder.
 func NewServer(cfg *config.Config, router *httprouter.Router) *Server {
+	baseCtx, cancel := context.WithCancel(context.Background())
+
 	return &Server{
 		httpServer: &http.Server{
 			Addr:           ":" + cfg.App.Port,
 			Handler:        router,
+			BaseContext:    func(net.Listener) context.Context { return baseCtx },
 			MaxHeaderBytes: 1 << cfg.App.MaxHeaderBytes, // 1 MB
 			WriteTimeout:   time.Second * time.Duration(cfg.WriteTimeout),
 			ReadTimeout:    time.Second * time.Duration(cfg.ReadTimeout),
 		},
+		cancel: cancel,
 	}
 }
 
@@ -33,7 +39,9 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
-// Stop stops server.
+// Stop stops server. Contexts of requests which are not finished until ctx is done are canceled,
+// so their DB queries are aborted.
 func (s *Server) Stop(ctx context.Context) error {
+	defer s.cancel()
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"web/internal/domain/services"
+)
+
+// runTrashPurge purge notes which are in trash longer than retention period every interval until ctx is canceled.
+// Not positive interval disables purger.
+func runTrashPurge(ctx context.Context, note services.NoteService, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		logger.Info("notes trash purger is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := note.PurgeTrash(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("failed to purge notes trash", zap.Error(err))
+			}
+
+			if purged > 0 {
+				logger.Info("notes trash purged", zap.Int("purged", purged))
+			}
+		}
+	}
+}
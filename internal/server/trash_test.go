@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"web/internal/domain/services/mocks"
+)
+
+func TestRunTrashPurge(t *testing.T) {
+	testTable := []struct {
+		interval time.Duration
+		purges   int
+		testName string
+	}{
+		{
+			interval: 0,
+			testName: "Test-1-Zero interval disables purger",
+		},
+		{
+			interval: -time.Second,
+			testName: "Test-2-Negative interval disables purger",
+		},
+		{
+			interval: time.Millisecond,
+			purges:   1,
+			testName: "Test-3-Purge every interval",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			note := mock_services.NewMockNoteService(c)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if testCase.purges > 0 {
+				note.EXPECT().PurgeTrash(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+					cancel()
+					return 0, nil
+				}).Times(testCase.purges)
+			}
+
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				runTrashPurge(ctx, note, testCase.interval, zap.NewNop())
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("purger is not stopped")
+			}
+		})
+	}
+}
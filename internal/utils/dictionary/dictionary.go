@@ -2,47 +2,130 @@
 package dictionary
 
 import (
-	"time"
-
-	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt"
 	"github.com/julienschmidt/httprouter"
 )
 
 // users URLs.
 const (
-	UsersURL = "/users"
-	UserURL  = "/users/:id"
-	Register = "/register"
-	Login    = "/login"
+	UsersURL     = "/users"
+	UserURL      = "/users/:id"
+	Register     = "/register"
+	Login        = "/login"
+	RefreshToken = "/token/refresh"
+	Logout       = "/logout"
+	JWKS         = "/.well-known/jwks.json"
+
+	AccessTokensURL = "/tokens"
+	AccessTokenURL  = "/tokens/:id"
+
+	LoginTwoFactor   = "/login/2fa"
+	TwoFactorEnroll  = "/2fa/enroll"
+	TwoFactorConfirm = "/2fa/confirm"
+	TwoFactorDisable = "/2fa/disable"
+
+	PasswordForgot    = "/password/forgot"
+	PasswordReset     = "/password/reset"
+	EmailVerify       = "/email/verify"
+	EmailVerifyResend = "/email/verify/resend"
 )
 
 // notes URLs.
 const (
-	NotesURL       = "/notes"
-	NoteURL        = "/notes/:id"
-	AllTagsByNotes = "/allnotes/tags"
-	AllTagsByNote  = "/notes/:id/tags"
+	NotesURL           = "/notes"
+	NoteURL            = "/notes/:id"
+	AllTagsByNotes     = "/allnotes/tags"
+	AllTagsByNote      = "/notes/:id/tags"
+	NotesSearchURL     = "/notes/search"
+	NoteRestoreURL     = "/notes/:id/restore"
+	RevisionsURL       = "/notes/:id/revisions"
+	RevisionURL        = "/notes/:id/revisions/:rev"
+	RevisionDiffURL    = "/notes/:id/revisions/:rev/diff"
+	RevisionRestoreURL = "/notes/:id/revisions/:rev/restore"
+	TrashURL           = "/trash"
+	TrashNoteURL       = "/trash/:id"
+)
+
+// RevisionToParam revision which note revision is compared with in revision diff, current note without it.
+const RevisionToParam = "to"
+
+// SearchQueryParam notes search text query parameter.
+const SearchQueryParam = "q"
+
+// SearchConfig text search configuration of notes search column, see migrations.
+const SearchConfig = "english"
+
+// list endpoints query parameters.
+const (
+	CreatedAfterParam = "created_after"
+	UpdatedSinceParam = "updated_since"
+	TitlePrefixParam  = "title_prefix"
+	SortParam         = "sort"
+	LimitParam        = "limit"
+	CursorParam       = "cursor"
+
+	// TagsParam is tag expression in notes lists and comma-separated tag names in notes search.
+	TagsParam = "tags"
+
+	// IncludeUntaggedParam lists notes without tags along with tagged ones in notes with tags list.
+	IncludeUntaggedParam = "include_untagged"
+
+	// IncludeDescendantsParam tags of tags filter match their child tags too.
+	IncludeDescendantsParam = "include_descendants"
+)
+
+// list endpoints sort keys, descending order is requested with "-" prefix, e.g. "-updated_at".
+const (
+	SortID        = "id"
+	SortTitle     = "title"
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+)
+
+// list endpoints page size.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// notebooks URLs.
+const (
+	NotebooksURL     = "/notebooks"
+	NotebookURL      = "/notebooks/:id"
+	NotebookTreeURL  = "/notebooks/tree"
+	NotebookNotesURL = "/notebooks/:id/notes"
+	NotebookMoveURL  = "/notebooks/:id/move"
+	NoteNotebookURL  = "/notes/:id/notebook"
 )
 
+// RecursiveParam notebook delete deletes child notebooks too, otherwise they are moved to parent.
+const RecursiveParam = "recursive"
+
 // tags URLs.
 const (
-	TagsURL = "/tags"
-	TagURL  = "/tags/:id"
+	TagsURL      = "/tags"
+	TagURL       = "/tags/:id"
+	TagsMergeURL = "/tags/merge"
+	TagsStatsURL = "/tags/stats"
 )
 
+// TagPathSeparator separator of tag names in tag path, e.g. "work/projects/alpha" is a child of "work/projects".
+const TagPathSeparator = "/"
+
+// TreeParam tags list returns all tags as a tree with note counts.
+const TreeParam = "tree"
+
+// DuplicatesParam tags stats returns groups of tags with names equal ignoring case.
+const DuplicatesParam = "duplicates"
+
 // TagSet URLs.
 const (
 	TagsSet    = "/notes/:id/tags/set"
 	TagsRemove = "/notes/:id/tags/remove"
 )
 
-// secret info.
-const (
-	Salt        = "abc"
-	SigningKey  = "secret"
-	ExpDuration = time.Hour * 12
-)
+// Salt is used only to verify legacy SHA-1 password hashes.
+const Salt = "abc"
 
 // tables names for queries in storage.
 const (
@@ -50,6 +133,24 @@ const (
 	NotesTable     = "notes"
 	TagsTable      = "tags"
 	NotesTagsTable = "notes_tags"
+	RevisionsTable = "note_revisions"
+	NotebooksTable = "notebooks"
+
+	RefreshTokensTable = "refresh_tokens"
+	RevokedTokensTable = "revoked_tokens"
+	AccessTokensTable  = "access_tokens"
+
+	RecoveryCodesTable       = "recovery_codes"
+	TwoFactorChallengesTable = "two_factor_challenges"
+
+	UserTokensTable = "user_tokens"
+	MailOutboxTable = "mail_outbox"
+)
+
+// single-use user tokens purposes.
+const (
+	PurposeResetPassword = "password_reset"
+	PurposeVerifyEmail   = "email_verification"
 )
 
 // for err.
@@ -57,6 +158,11 @@ const (
 	User = "user"
 	Note = "note"
 	Tag  = "tag"
+
+	Notebook    = "notebook"
+	AccessToken = "access token"
+	TrashedNote = "trashed note"
+	Revision    = "note revision"
 )
 
 // LenHeaderParts len of arr.
@@ -64,14 +170,32 @@ const (
 	LenHeaderParts = 2
 )
 
-// TokenClaims - additional token field to userID.
+// users roles.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// personal access tokens scopes.
+const (
+	ScopeNotesRead  = "notes:read"
+	ScopeNotesWrite = "notes:write"
+	ScopeTagsRead   = "tags:read"
+	ScopeTagsWrite  = "tags:write"
+)
+
+// AccessTokenPrefix prefix of personal access tokens, it tells them apart from JWT.
+const AccessTokenPrefix = "nst_"
+
+// TokenClaims - additional token field to userID and role. StandardClaims.Id is token id (jti).
+// Personal and Scopes are filled only for personal access tokens and never get into JWT.
 type TokenClaims struct {
 	jwt.StandardClaims
-	UserID string `json:"user_id"`
+	UserID   string   `json:"user_id"`
+	Role     string   `json:"role"`
+	Personal bool     `json:"-"`
+	Scopes   []string `json:"-"`
 }
 
-// Validate validate obj.
-var Validate = validator.New()
-
 // LogMiddleware custom type of logging middleware.
 type LogMiddleware func(next httprouter.Handle) httprouter.Handle
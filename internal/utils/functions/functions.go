@@ -3,37 +3,226 @@ package functions
 
 import (
 	"context"
-	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
 	"encoding/json"
+	e "errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"go.uber.org/zap"
+
+	"web/internal/domain/entities/dto"
 	"web/internal/domain/errors"
 	"web/internal/utils/dictionary"
 	"web/pkg/logger"
+	"web/pkg/tagquery"
 )
 
-// GeneratePasswordHash user password hash generator.
-func GeneratePasswordHash(password string) string {
-	hash := sha1.New() //nolint:gosec
-	hash.Write([]byte(password))
-	return fmt.Sprintf("%x", hash.Sum([]byte(dictionary.Salt)))
+// ParseListQuery parse list endpoints filters from URL query, times are in RFC 3339 format.
+func ParseListQuery(values url.Values) (*dto.ListQuery, error) {
+	listQuery := &dto.ListQuery{}
+
+	timeParams := []struct {
+		name string
+		dest **time.Time
+	}{
+		{name: dictionary.CreatedAfterParam, dest: &listQuery.CreatedAfter},
+		{name: dictionary.UpdatedSinceParam, dest: &listQuery.UpdatedSince},
+	}
+
+	for _, param := range timeParams {
+		value := values.Get(param.name)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w '%s': time must be in RFC 3339 format", errors.ErrInvalidQueryParam, param.name)
+		}
+
+		*param.dest = &t
+	}
+
+	return listQuery, nil
+}
+
+// ParsePageQuery parse list filters, title prefix, sort, limit and cursor of paginated list endpoints from URL query.
+func ParsePageQuery(values url.Values) (*dto.ListQuery, error) {
+	listQuery, err := ParseListQuery(values)
+	if err != nil {
+		return nil, err
+	}
+
+	listQuery.TitlePrefix = values.Get(dictionary.TitlePrefixParam)
+
+	sort := values.Get(dictionary.SortParam)
+	listQuery.Desc = strings.HasPrefix(sort, "-")
+	listQuery.Sort = strings.TrimPrefix(sort, "-")
+
+	switch listQuery.Sort {
+	case "":
+		listQuery.Sort = dictionary.SortID
+	case dictionary.SortID, dictionary.SortTitle, dictionary.SortCreatedAt, dictionary.SortUpdatedAt:
+	default:
+		return nil, fmt.Errorf("%w '%s': sort must be one of id, title, created_at, updated_at",
+			errors.ErrInvalidQueryParam, dictionary.SortParam)
+	}
+
+	if listQuery.Limit, err = parseLimit(values); err != nil {
+		return nil, err
+	}
+
+	if value := values.Get(dictionary.CursorParam); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil || cursor.Sort != listQuery.Sort || cursor.Desc != listQuery.Desc {
+			return nil, fmt.Errorf("%w '%s': cursor is malformed or was issued for another sort",
+				errors.ErrInvalidQueryParam, dictionary.CursorParam)
+		}
+
+		listQuery.Cursor = cursor
+	}
+
+	return listQuery, nil
+}
+
+// ParseIncludeUntagged parse option to list notes without tags along with tagged ones, false without parameter.
+func ParseIncludeUntagged(values url.Values) (bool, error) {
+	return parseBoolParam(values, dictionary.IncludeUntaggedParam)
+}
+
+// ParseRecursive parse option to delete notebook with its contents, false without parameter.
+func ParseRecursive(values url.Values) (bool, error) {
+	return parseBoolParam(values, dictionary.RecursiveParam)
+}
+
+// ParseIncludeDescendants parse option to match child tags by tags of tags filter, false without parameter.
+func ParseIncludeDescendants(values url.Values) (bool, error) {
+	return parseBoolParam(values, dictionary.IncludeDescendantsParam)
+}
+
+// ParseTree parse option to get all tags as a tree, false without parameter.
+func ParseTree(values url.Values) (bool, error) {
+	return parseBoolParam(values, dictionary.TreeParam)
+}
+
+// ParseDuplicates parse option to get groups of duplicate tags, false without parameter.
+func ParseDuplicates(values url.Values) (bool, error) {
+	return parseBoolParam(values, dictionary.DuplicatesParam)
+}
+
+// parseBoolParam parse boolean query parameter, false without parameter.
+func parseBoolParam(values url.Values, param string) (bool, error) {
+	value := values.Get(param)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w '%s': must be true or false", errors.ErrInvalidQueryParam, param)
+	}
+
+	return parsed, nil
+}
+
+// ParseTagsExpr parse tag expression of notes lists from URL query, nil expression without tags parameter.
+func ParseTagsExpr(values url.Values) (tagquery.Expr, error) {
+	value := values.Get(dictionary.TagsParam)
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	expr, err := tagquery.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s': %s", errors.ErrInvalidQueryParam, dictionary.TagsParam, err.Error())
+	}
+
+	return expr, nil
+}
+
+// ParseNoteSearchQuery parse notes search text query, tags filter with its descendants option and limit from URL query.
+func ParseNoteSearchQuery(values url.Values) (*dto.NoteSearchQuery, error) {
+	searchQuery := &dto.NoteSearchQuery{Query: strings.TrimSpace(values.Get(dictionary.SearchQueryParam))}
+	if searchQuery.Query == "" {
+		return nil, fmt.Errorf("%w '%s': search query must not be empty",
+			errors.ErrInvalidQueryParam, dictionary.SearchQueryParam)
+	}
+
+	for _, tag := range strings.Split(values.Get(dictionary.TagsParam), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			searchQuery.Tags = append(searchQuery.Tags, tag)
+		}
+	}
+
+	descendants, err := ParseIncludeDescendants(values)
+	if err != nil {
+		return nil, err
+	}
+
+	searchQuery.TagDescendants = descendants
+
+	limit, err := parseLimit(values)
+	if err != nil {
+		return nil, err
+	}
+
+	searchQuery.Limit = limit
+
+	return searchQuery, nil
 }
 
-// ErrDBCheck check BD err.
-func ErrDBCheck(dbErr, name, instance string) error {
+// parseLimit parse page size, default size is used without limit parameter.
+func parseLimit(values url.Values) (int, error) {
+	value := values.Get(dictionary.LimitParam)
+	if value == "" {
+		return dictionary.DefaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > dictionary.MaxPageLimit {
+		return 0, fmt.Errorf("%w '%s': limit must be an integer from 1 to %d",
+			errors.ErrInvalidQueryParam, dictionary.LimitParam, dictionary.MaxPageLimit)
+	}
+
+	return limit, nil
+}
+
+// EncodeCursor encode page cursor to opaque URL safe string.
+func EncodeCursor(cursor *dto.Cursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decode page cursor made by EncodeCursor, sort key value must match cursor sort.
+func DecodeCursor(str string) (*dto.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &dto.Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, err
+	}
+
 	switch {
-	case strings.Contains(dbErr, errors.ErrDBDuplicate):
-		return fmt.Errorf(fmt.Sprintf("%s '%s' is already exists", name, instance))
-	case strings.Contains(dbErr, errors.ErrDBNotExists) && CheckID(instance):
-		return fmt.Errorf(fmt.Sprintf("No %s with id '%s'", name, instance))
-	case strings.Contains(dbErr, errors.ErrDBNotExists):
-		return fmt.Errorf(fmt.Sprintf("No %s with name '%s'", name, instance))
+	case cursor.Sort == dictionary.SortID:
+	case cursor.Sort == dictionary.SortTitle && cursor.Title != nil:
+	case (cursor.Sort == dictionary.SortCreatedAt || cursor.Sort == dictionary.SortUpdatedAt) && cursor.Time != nil:
+	default:
+		return nil, errors.ErrInvalidCursor
 	}
 
-	return nil
+	return cursor, nil
 }
 
 // CheckID check try to int conversion.
@@ -42,6 +231,44 @@ func CheckID(str string) bool {
 	return err == nil
 }
 
+// ETag entity tag of resource version.
+func ETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// CheckIfMatch check If-Match header against current version of resource, strong comparison is used.
+// Version which resource must still have on change is returned, 0 without header or with "*".
+// false is returned if no entity tag of header matches the version.
+func CheckIfMatch(header string, version int) (int, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == ETag(version) {
+			return version, true
+		}
+	}
+
+	return 0, false
+}
+
+// CheckIfNoneMatch true if If-None-Match header has entity tag of resource version or "*", weak comparison is used.
+func CheckIfNoneMatch(header string, version int) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == ETag(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MakeJSONResponse make http response to UI.
 func MakeJSONResponse(w http.ResponseWriter, httpStatus int, resp interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -49,42 +276,138 @@ func MakeJSONResponse(w http.ResponseWriter, httpStatus int, resp interface{}) {
 	json.NewEncoder(w).Encode(resp) //nolint:errcheck,gosec
 }
 
-// Abort func.
-func Abort(ctx context.Context, w http.ResponseWriter, httpStatus int, err, errDesc error, name, instance string) {
+// JSONArrayWriter stream JSON array to http response item by item.
+// Status and headers are written with the first item, so the response can still be an error until then.
+type JSONArrayWriter struct {
+	w          http.ResponseWriter
+	httpStatus int
+	count      int
+}
+
+// NewJSONArrayWriter JSONArrayWriter builder.
+func NewJSONArrayWriter(w http.ResponseWriter, httpStatus int) *JSONArrayWriter {
+	return &JSONArrayWriter{w: w, httpStatus: httpStatus}
+}
+
+// Write write array item.
+func (a *JSONArrayWriter) Write(item interface{}) error {
+	data, err := json.Marshal(item)
 	if err != nil {
-		if instanceErr := ErrDBCheck(err.Error(), name, instance); instanceErr != nil {
-			ErrCheck(ctx, w, httpStatus, nil, instanceErr)
-			return
-		}
-		ErrCheck(ctx, w, httpStatus, err, errDesc)
-		return
+		return err
 	}
-	ErrCheck(ctx, w, httpStatus, err, errDesc)
+
+	separator := ","
+	if a.count == 0 {
+		a.w.Header().Set("Content-Type", "application/json")
+		a.w.WriteHeader(a.httpStatus)
+		separator = "["
+	}
+
+	a.count++
+
+	_, err = a.w.Write(append([]byte(separator), data...))
+
+	return err
 }
 
-// ErrCheck - make resp to UI.
-func ErrCheck(ctx context.Context, w http.ResponseWriter, httpStatus int, err, errDesc error) {
-	//nolint:errcheck,gosec
-	json.NewEncoder(SetErrRespHeaders(w, httpStatus)).Encode(MapErrCreate(ctx, err, errDesc))
+// Count count of written items.
+func (a *JSONArrayWriter) Count() int {
+	return a.count
 }
 
-// SetErrRespHeaders set err in headers.
-func SetErrRespHeaders(w http.ResponseWriter, httpStatus int) http.ResponseWriter {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(httpStatus)
-	return w
+// Close close array, nothing is written without items.
+func (a *JSONArrayWriter) Close() error {
+	if a.count == 0 {
+		return nil
+	}
+
+	_, err := a.w.Write([]byte("]\n"))
+
+	return err
+}
+
+// kindStatuses HTTP statuses of domain error kinds.
+var kindStatuses = map[errors.Kind]int{
+	errors.KindInternal:         http.StatusInternalServerError,
+	errors.KindNotFound:         http.StatusNotFound,
+	errors.KindConflict:         http.StatusConflict,
+	errors.KindValidation:       http.StatusBadRequest,
+	errors.KindUnauthorized:     http.StatusUnauthorized,
+	errors.KindForbidden:        http.StatusForbidden,
+	errors.KindPrecondition:     http.StatusPreconditionFailed,
+	errors.KindUnsupportedMedia: http.StatusUnsupportedMediaType,
+	errors.KindTooManyRequests:  http.StatusTooManyRequests,
+	errors.KindLocked:           http.StatusLocked,
 }
 
-// MapErrCreate create map with err.
-func MapErrCreate(ctx context.Context, err, errDesc error) map[string]string {
-	errMap := make(map[string]string)
-	if err == nil {
-		errMap["error"] = errDesc.Error()
-		logger.LogFromContext(ctx).Error(errDesc.Error())
+// Abort make error response in problem details format and log it.
+// err is the cause of errDesc, typed domain error of them sets status and code, httpStatus is used for untyped errDesc.
+// name and instance describe requested entity in details of not found and conflict errors.
+func Abort(ctx context.Context, w http.ResponseWriter, httpStatus int, err, errDesc error, name, instance string) {
+	problem := NewProblem(ctx, httpStatus, err, errDesc, name, instance)
+
+	if err != nil {
+		logger.LogFromContext(ctx).Error(problem.Detail, zap.Error(err))
 	} else {
-		errMap["error"] = errDesc.Error()
-		errMap["desc"] = err.Error()
-		logger.LogFromContext(ctx).Error(errDesc.Error())
+		logger.LogFromContext(ctx).Error(problem.Detail)
 	}
-	return errMap
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem) //nolint:errcheck,gosec
+}
+
+// NewProblem make problem details of error, see Abort.
+func NewProblem(ctx context.Context, httpStatus int, err, errDesc error, name, instance string) *dto.Problem {
+	problem := &dto.Problem{
+		Type:      "about:blank",
+		Status:    httpStatus,
+		Detail:    errDesc.Error(),
+		RequestID: logger.RequestIDFromContext(ctx),
+	}
+
+	domainErr, fromErr := errors.As(err), err != nil
+	if domainErr == nil {
+		domainErr, fromErr = errors.As(errDesc), false
+	}
+
+	switch {
+	case domainErr != nil:
+		problem.Status = kindStatuses[domainErr.Kind]
+		problem.Code = domainErr.Code
+		problem.Errors = domainErr.Fields
+
+		if fromErr {
+			problem.Detail = instanceDetail(domainErr, err, name, instance)
+		}
+	case e.Is(err, context.DeadlineExceeded):
+		problem.Status = http.StatusServiceUnavailable
+		problem.Code = "timeout"
+		problem.Detail = "request timed out"
+	case err != nil:
+		problem.Status = http.StatusInternalServerError
+		problem.Code = errors.CodeInternal
+	default:
+		problem.Code = strings.ReplaceAll(strings.ToLower(http.StatusText(httpStatus)), " ", "_")
+	}
+
+	problem.Title = http.StatusText(problem.Status)
+
+	return problem
+}
+
+// instanceDetail detail of typed error about requested entity, error message without entity name.
+func instanceDetail(domainErr *errors.Error, err error, name, instance string) string {
+	switch {
+	case name == "":
+		return err.Error()
+	case domainErr.Kind == errors.KindNotFound && CheckID(instance):
+		return fmt.Sprintf("No %s with id '%s'", name, instance)
+	case domainErr.Kind == errors.KindNotFound:
+		return fmt.Sprintf("No %s with name '%s'", name, instance)
+	case domainErr.Code == errors.CodeConflict:
+		return fmt.Sprintf("%s '%s' is already exists", name, instance)
+	}
+
+	return err.Error()
 }
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id                serial PRIMARY KEY,
+    user_id           integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    token_hash        varchar(255) UNIQUE                             NOT NULL,
+    access_jti        varchar(255)                                    NOT NULL,
+    access_expires_at timestamp                                       NOT NULL,
+    expires_at        timestamp                                       NOT NULL,
+    revoked           boolean DEFAULT false                           NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_access_jti_idx ON refresh_tokens (access_jti);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        varchar(255) PRIMARY KEY,
+    expires_at timestamp NOT NULL
+);
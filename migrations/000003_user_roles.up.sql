@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN role varchar(32) DEFAULT 'user' NOT NULL;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS access_tokens
+(
+    id         serial PRIMARY KEY,
+    user_id    integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    name       varchar(255)                                    NOT NULL,
+    token_hash varchar(255) UNIQUE                             NOT NULL,
+    scopes     varchar(255)                                    NOT NULL,
+    expires_at timestamp,
+    created_at timestamp                                       NOT NULL,
+    UNIQUE (user_id, name)
+);
@@ -0,0 +1,22 @@
+ALTER TABLE users ADD COLUMN totp_secret varchar(64);
+ALTER TABLE users ADD COLUMN totp_enabled boolean DEFAULT false NOT NULL;
+ALTER TABLE users ADD COLUMN totp_last_step bigint DEFAULT 0 NOT NULL;
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id        serial PRIMARY KEY,
+    user_id   integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    code_hash varchar(255)                                    NOT NULL,
+    used      boolean DEFAULT false                           NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS two_factor_challenges
+(
+    id         serial PRIMARY KEY,
+    user_id    integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    token_hash varchar(255) UNIQUE                             NOT NULL,
+    attempts   integer DEFAULT 0                               NOT NULL,
+    expires_at timestamp                                       NOT NULL
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS mail_outbox;
+DROP TABLE IF EXISTS user_tokens;
+
+DROP INDEX IF EXISTS users_email_key;
+
+ALTER TABLE users DROP COLUMN email_verified;
+ALTER TABLE users DROP COLUMN email;
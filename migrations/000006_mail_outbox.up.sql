@@ -0,0 +1,30 @@
+ALTER TABLE users ADD COLUMN email varchar(255);
+ALTER TABLE users ADD COLUMN email_verified boolean DEFAULT false NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email);
+
+CREATE TABLE IF NOT EXISTS user_tokens
+(
+    id         serial PRIMARY KEY,
+    user_id    integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    token_hash varchar(255) UNIQUE                             NOT NULL,
+    purpose    varchar(32)                                     NOT NULL,
+    expires_at timestamp                                       NOT NULL,
+    used_at    timestamp
+);
+
+CREATE INDEX IF NOT EXISTS user_tokens_user_id_idx ON user_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS mail_outbox
+(
+    id         serial PRIMARY KEY,
+    recipient  varchar(255)      NOT NULL,
+    subject    varchar(255)      NOT NULL,
+    body       text              NOT NULL,
+    created_at timestamp         NOT NULL,
+    sent_at    timestamp,
+    attempts   integer DEFAULT 0 NOT NULL,
+    last_error text
+);
+
+CREATE INDEX IF NOT EXISTS mail_outbox_sent_at_idx ON mail_outbox (sent_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tags_user_id_tagname_key;
+DROP INDEX IF EXISTS notes_user_id_title_key;
+
+ALTER TABLE notes ADD CONSTRAINT notes_title_key UNIQUE (title);
@@ -0,0 +1,20 @@
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_title_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS notes_user_id_title_key ON notes (user_id, title);
+
+-- links of duplicate tags are moved to the oldest tag with the same name, then duplicates are removed
+INSERT INTO notes_tags (note_id, tag_id)
+SELECT nt.note_id, k.id
+FROM notes_tags nt
+         JOIN tags t ON t.id = nt.tag_id
+         JOIN (SELECT user_id, tagname, MIN(id) AS id FROM tags GROUP BY user_id, tagname) k
+              ON k.user_id = t.user_id AND k.tagname = t.tagname AND k.id <> t.id
+ON CONFLICT DO NOTHING;
+
+DELETE
+FROM tags t USING tags k
+WHERE k.user_id = t.user_id
+  AND k.tagname = t.tagname
+  AND k.id < t.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tags_user_id_tagname_key ON tags (user_id, tagname);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS tags_user_id_updated_at_idx;
+DROP INDEX IF EXISTS notes_user_id_updated_at_idx;
+
+ALTER TABLE tags DROP COLUMN updated_at;
+ALTER TABLE tags DROP COLUMN created_at;
+
+ALTER TABLE notes DROP COLUMN updated_at;
+ALTER TABLE notes DROP COLUMN created_at;
+
+ALTER TABLE users DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at;
@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN created_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+ALTER TABLE users ADD COLUMN updated_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+
+ALTER TABLE notes ADD COLUMN created_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+ALTER TABLE notes ADD COLUMN updated_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+
+ALTER TABLE tags ADD COLUMN created_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+ALTER TABLE tags ADD COLUMN updated_at timestamp DEFAULT (now() AT TIME ZONE 'utc') NOT NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_updated_at_idx ON notes (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS tags_user_id_updated_at_idx ON tags (user_id, updated_at);
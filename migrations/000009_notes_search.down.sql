@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notes_search_idx;
+
+ALTER TABLE notes DROP COLUMN search;
@@ -0,0 +1,10 @@
+DELETE
+FROM notes
+WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS notes_user_id_title_key;
+DROP INDEX IF EXISTS notes_deleted_at_idx;
+
+ALTER TABLE notes DROP COLUMN deleted_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS notes_user_id_title_key ON notes (user_id, title);
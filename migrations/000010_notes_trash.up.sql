@@ -0,0 +1,8 @@
+ALTER TABLE notes ADD COLUMN deleted_at timestamp;
+
+CREATE INDEX IF NOT EXISTS notes_deleted_at_idx ON notes (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- title of trashed note is free for new notes, restore of the trashed note then is a conflict
+DROP INDEX IF EXISTS notes_user_id_title_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS notes_user_id_title_key ON notes (user_id, title) WHERE deleted_at IS NULL;
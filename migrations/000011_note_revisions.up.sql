@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS note_revisions
+(
+    id         serial PRIMARY KEY,
+    note_id    integer REFERENCES notes (id) ON DELETE CASCADE NOT NULL,
+    rev        integer                                         NOT NULL,
+    title      varchar(255)                                    NOT NULL,
+    info       text,
+    author_id  integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    created_at timestamp                                       NOT NULL,
+
+    CONSTRAINT note_revisions_note_id_rev_key UNIQUE (note_id, rev)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE notes ADD COLUMN version integer DEFAULT 1 NOT NULL;
+ALTER TABLE tags ADD COLUMN version integer DEFAULT 1 NOT NULL;
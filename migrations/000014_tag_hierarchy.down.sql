@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tags_parent_id_idx;
+
+ALTER TABLE tags DROP COLUMN parent_id;
@@ -0,0 +1,13 @@
+-- tag names are paths like work/projects/alpha, parent of a tag is the tag of its path without the last name
+ALTER TABLE tags ADD COLUMN parent_id integer REFERENCES tags (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS tags_parent_id_idx ON tags (parent_id);
+
+-- existing tags with '/' in names get the nearest existing tag of their path as parent
+UPDATE tags
+SET parent_id = (SELECT p.id
+                 FROM tags p
+                 WHERE p.user_id = tags.user_id
+                   AND substr(tags.tagname, 1, length(p.tagname) + 1) = p.tagname || '/'
+                 ORDER BY length(p.tagname) DESC
+                 LIMIT 1);
@@ -0,0 +1 @@
+ALTER TABLE notes_tags DROP COLUMN created_at;
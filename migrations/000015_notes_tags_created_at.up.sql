@@ -0,0 +1,6 @@
+-- time of linking tag to note, tag was last used at the latest link time
+ALTER TABLE notes_tags ADD COLUMN created_at timestamp;
+
+-- link time of existing links is unknown, last update of the note is the closest to it
+UPDATE notes_tags
+SET created_at = (SELECT n.updated_at FROM notes n WHERE n.id = notes_tags.note_id);
@@ -0,0 +1,2 @@
+-- dispatcher claims pending mails until this time, other dispatchers skip them meanwhile
+ALTER TABLE mail_outbox ADD COLUMN claimed_until timestamp;
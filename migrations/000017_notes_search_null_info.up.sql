@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS notes_search_idx;
+
+ALTER TABLE notes DROP COLUMN search;
+
+ALTER TABLE notes ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', coalesce(info, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS notes_search_idx ON notes USING GIN (search);
@@ -0,0 +1,4 @@
+-- global title uniqueness is not restored: test data may already have per-user duplicates,
+-- and notes table is dropped by init down migration anyway
+DROP INDEX IF EXISTS tags_user_id_tagname_key;
+DROP INDEX IF EXISTS notes_user_id_title_key;
@@ -0,0 +1,18 @@
+-- sqlite can not drop inline UNIQUE constraint, so notes table is rebuilt
+CREATE TABLE notes_new
+(
+    id      serial PRIMARY KEY,
+    title   varchar(255)                                    NOT NULL,
+    info    text,
+    user_id integer REFERENCES users (id) ON DELETE CASCADE NOT NULL
+);
+
+INSERT INTO notes_new (id, title, info, user_id)
+SELECT id, title, info, user_id
+FROM notes;
+
+DROP TABLE notes;
+ALTER TABLE notes_new RENAME TO notes;
+
+CREATE UNIQUE INDEX IF NOT EXISTS notes_user_id_title_key ON notes (user_id, title);
+CREATE UNIQUE INDEX IF NOT EXISTS tags_user_id_tagname_key ON tags (user_id, tagname);
@@ -0,0 +1,12 @@
+-- sqlite can not add column with non-constant default, storage sets timestamps explicitly
+ALTER TABLE users ADD COLUMN created_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+ALTER TABLE users ADD COLUMN updated_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+
+ALTER TABLE notes ADD COLUMN created_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+ALTER TABLE notes ADD COLUMN updated_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+
+ALTER TABLE tags ADD COLUMN created_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+ALTER TABLE tags ADD COLUMN updated_at timestamp DEFAULT '1970-01-01 00:00:00' NOT NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_updated_at_idx ON notes (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS tags_user_id_updated_at_idx ON tags (user_id, updated_at);
@@ -0,0 +1,18 @@
+-- sqlite can not drop column with foreign key, notes.notebook_id has no REFERENCES here
+CREATE TABLE IF NOT EXISTS notebooks
+(
+    id         serial PRIMARY KEY,
+    name       varchar(255)                                    NOT NULL,
+    parent_id  integer REFERENCES notebooks (id) ON DELETE CASCADE,
+    user_id    integer REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    created_at timestamp                                       NOT NULL,
+    updated_at timestamp                                       NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notebooks_user_id_parent_id_name_key
+    ON notebooks (user_id, COALESCE(parent_id, 0), name);
+CREATE INDEX IF NOT EXISTS notebooks_parent_id_idx ON notebooks (parent_id);
+
+ALTER TABLE notes ADD COLUMN notebook_id integer;
+
+CREATE INDEX IF NOT EXISTS notes_notebook_id_idx ON notes (notebook_id);
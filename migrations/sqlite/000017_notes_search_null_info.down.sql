@@ -0,0 +1 @@
+-- sqlite up migration adds nothing
@@ -0,0 +1 @@
+-- sqlite has no tsvector, notes search falls back to LIKE matching of title and info
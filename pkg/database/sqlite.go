@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -15,6 +17,9 @@ import (
 	"web/internal/utils/dictionary"
 )
 
+// migrationsDir - path to migrations from tested packages.
+const migrationsDir = "../../../migrations"
+
 // TestDBClient - db Client for unit testing.
 type TestDBClient struct {
 	Client *sqlx.DB
@@ -34,14 +39,17 @@ func NewTestDBClient() *TestDBClient {
 
 // SetUp - create a new migrates before tests.
 func (t TestDBClient) SetUp() {
-	schema := fileOpen("../../../migrations/000001_init.up.sql")
-	t.Client.MustExec(strings.ReplaceAll(schema, "serial", "INTEGER"))
+	for _, path := range migrationFiles("up") {
+		t.Client.MustExec(strings.ReplaceAll(fileOpen(path), "serial", "INTEGER"))
+	}
 }
 
 // TearDown - drop down db after test.
 func (t TestDBClient) TearDown() {
-	schema := fileOpen("../../../migrations/000001_init.down.sql")
-	t.Client.MustExec(schema)
+	files := migrationFiles("down")
+	for i := len(files) - 1; i >= 0; i-- {
+		t.Client.MustExec(fileOpen(files[i]))
+	}
 }
 
 // Close - close db connect in unit tests.
@@ -79,6 +87,26 @@ func (t TestDBClient) InsertTestTag(tag *model.Tag, userID string) error {
 	return nil
 }
 
+// migrationFiles - sorted list of .sql migration files by direction (up/down).
+// Migrations with postgres-only DDL are replaced by files with the same name from migrations/sqlite.
+func migrationFiles(direction string) []string {
+	files, err := filepath.Glob(fmt.Sprintf("%s/*.%s.sql", migrationsDir, direction))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	sort.Strings(files)
+
+	for i, path := range files {
+		override := filepath.Join(migrationsDir, "sqlite", filepath.Base(path))
+		if _, err := os.Stat(override); err == nil {
+			files[i] = override
+		}
+	}
+
+	return files
+}
+
 // fileOpen - open file .sql with migrations.
 func fileOpen(path string) string {
 	schema, err := os.ReadFile(path) //nolint:gosec
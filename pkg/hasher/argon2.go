@@ -0,0 +1,115 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id params.
+const (
+	argon2SaltLen  = 16
+	argon2KeyLen   = 32
+	argon2Prefix   = "$argon2id$"
+	argon2HashPart = 6
+)
+
+// argon2idHasher argon2id hasher struct.
+type argon2idHasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// NewArgon2id argon2id hasher func builder.
+func NewArgon2id(time, memory, threads int) Hasher {
+	return &argon2idHasher{
+		time:    uint32(time),
+		memory:  uint32(memory),
+		threads: uint8(threads),
+	}
+}
+
+// Name returns algorithm name.
+func (a *argon2idHasher) Name() string {
+	return Argon2id
+}
+
+// Hash makes argon2id hash in PHC format: $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+func (a *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.time, a.memory, a.threads, argon2KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix,
+		argon2.Version,
+		a.memory,
+		a.time,
+		a.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compares password with argon2id hash.
+func (a *argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := a.decode(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// Identify checks argon2id prefix.
+func (a *argon2idHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2Prefix)
+}
+
+// NeedsRehash checks hash params.
+func (a *argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := a.decode(encoded)
+	return err != nil || *params != *a
+}
+
+// decode parse PHC string.
+func (a *argon2idHasher) decode(encoded string) (*argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != argon2HashPart {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	// argon2.IDKey panics on zero time or threads
+	params := &argon2idHasher{}
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads)
+	if err != nil || params.time == 0 || params.threads == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,115 @@
+// Package hasher Package hasher
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"web/internal/config"
+)
+
+// algorithms names.
+const (
+	Bcrypt   = "bcrypt"
+	Argon2id = "argon2id"
+	SHA1     = "sha1"
+)
+
+// hasher errors.
+var (
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+	ErrInvalidHash      = errors.New("invalid password hash format")
+)
+
+// Hasher password hasher interface.
+// Every encoded hash carries the identifier of the algorithm which made it.
+type Hasher interface {
+	// Name returns algorithm name.
+	Name() string
+	// Hash makes encoded hash from password.
+	Hash(password string) (string, error)
+	// Verify compares password with encoded hash.
+	Verify(password, encoded string) (bool, error)
+	// Identify reports whether encoded hash was made by this algorithm.
+	Identify(encoded string) bool
+	// NeedsRehash reports whether encoded hash was made with outdated params.
+	NeedsRehash(encoded string) bool
+}
+
+// fakePassword password of the hash which passwords of unknown users are verified against.
+const fakePassword = "fake password of unknown user"
+
+// Manager hashes passwords with the current algorithm and verifies them with any known one.
+type Manager struct {
+	current Hasher
+	known   []Hasher
+
+	fakeOnce sync.Once
+	fakeHash string
+}
+
+// NewManager manager func builder.
+func NewManager(current Hasher, legacy ...Hasher) *Manager {
+	return &Manager{
+		current: current,
+		known:   append([]Hasher{current}, legacy...),
+	}
+}
+
+// NewHasher manager builder from config.
+// The algorithm from config becomes current, the other ones are kept for verification only.
+func NewHasher(cfg *config.Config, legacySalt string) (*Manager, error) {
+	hashers := []Hasher{
+		NewBcrypt(cfg.Hasher.BcryptCost),
+		NewArgon2id(cfg.Hasher.Argon2Time, cfg.Hasher.Argon2Memory, cfg.Hasher.Argon2Threads),
+		NewSHA1(legacySalt),
+	}
+
+	for i, h := range hashers {
+		if h.Name() == cfg.Hasher.Algorithm && h.Name() != SHA1 {
+			legacy := append(append([]Hasher{}, hashers[:i]...), hashers[i+1:]...)
+			return NewManager(h, legacy...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrUnknownAlgorithm, cfg.Hasher.Algorithm)
+}
+
+// Hash makes encoded hash with the current algorithm.
+func (m *Manager) Hash(password string) (string, error) {
+	return m.current.Hash(password)
+}
+
+// Verify compares password with encoded hash.
+// rehash is true when the hash must be replaced by a hash of the current algorithm.
+func (m *Manager) Verify(password, encoded string) (ok, rehash bool, err error) {
+	for _, h := range m.known {
+		if !h.Identify(encoded) {
+			continue
+		}
+
+		ok, err = h.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+
+		if h != m.current {
+			return true, true, nil
+		}
+
+		return true, h.NeedsRehash(encoded), nil
+	}
+
+	return false, false, ErrUnknownAlgorithm
+}
+
+// VerifyFake verifies password against a hash made with the current algorithm, the result is dropped.
+// It takes as long as Verify, so callers without a stored hash do not reveal it by response time.
+func (m *Manager) VerifyFake(password string) {
+	m.fakeOnce.Do(func() {
+		m.fakeHash, _ = m.current.Hash(fakePassword) //nolint:errcheck
+	})
+
+	_, _ = m.current.Verify(password, m.fakeHash) //nolint:errcheck
+}
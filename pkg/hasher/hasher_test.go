@@ -0,0 +1,108 @@
+package hasher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Verify(t *testing.T) {
+	argon := NewArgon2id(1, 1024, 1)
+	bcrypt := NewBcrypt(4)
+	legacy := NewSHA1("abc")
+
+	legacyHash, _ := legacy.Hash("test_password")
+	bcryptHash, _ := bcrypt.Hash("test_password")
+	argonHash, _ := argon.Hash("test_password")
+	outdatedArgonHash, _ := NewArgon2id(2, 1024, 1).Hash("test_password")
+
+	manager := NewManager(argon, bcrypt, legacy)
+
+	testTable := []struct {
+		password       string
+		encoded        string
+		expectedOK     bool
+		expectedRehash bool
+		err            error
+		testName       string
+	}{
+		{
+			password:   "test_password",
+			encoded:    argonHash,
+			expectedOK: true,
+			testName:   "Test-1-Current algorithm",
+		},
+		{
+			password:       "test_password",
+			encoded:        outdatedArgonHash,
+			expectedOK:     true,
+			expectedRehash: true,
+			testName:       "Test-2-Current algorithm outdated params",
+		},
+		{
+			password:       "test_password",
+			encoded:        bcryptHash,
+			expectedOK:     true,
+			expectedRehash: true,
+			testName:       "Test-3-Bcrypt",
+		},
+		{
+			password:       "test_password",
+			encoded:        legacyHash,
+			expectedOK:     true,
+			expectedRehash: true,
+			testName:       "Test-4-Legacy SHA-1",
+		},
+		{
+			password: "wrong_password",
+			encoded:  argonHash,
+			testName: "Test-5-Wrong password",
+		},
+		{
+			password: "wrong_password",
+			encoded:  legacyHash,
+			testName: "Test-6-Wrong password legacy",
+		},
+		{
+			password: "test_password",
+			encoded:  "$unknown$hash",
+			err:      ErrUnknownAlgorithm,
+			testName: "Test-7-Unknown algorithm",
+		},
+		{
+			password: "test_password",
+			encoded:  "$argon2id$v=19$m=1024,t=1,p=0$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			err:      ErrInvalidHash,
+			testName: "Test-8-Argon2id zero threads",
+		},
+		{
+			password: "test_password",
+			encoded:  "$argon2id$v=19$m=1024,t=0,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			err:      ErrInvalidHash,
+			testName: "Test-9-Argon2id zero time",
+		},
+		{
+			password: "test_password",
+			encoded:  "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+			err:      ErrInvalidHash,
+			testName: "Test-10-Argon2id empty key",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			ok, rehash, err := manager.Verify(testCase.password, testCase.encoded)
+
+			require.Equal(t, testCase.expectedOK, ok)
+			require.Equal(t, testCase.expectedRehash, rehash)
+			require.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func TestManager_VerifyFake(t *testing.T) {
+	manager := NewManager(NewArgon2id(1, 1024, 1))
+
+	require.NotPanics(t, func() { manager.VerifyFake("test_password") })
+	require.True(t, manager.current.Identify(manager.fakeHash))
+}
@@ -0,0 +1,47 @@
+package hasher
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// sha1Hasher legacy salted SHA-1 hasher struct.
+// Hashes have no algorithm prefix, it is used only to verify and rehash old passwords.
+type sha1Hasher struct {
+	salt string
+}
+
+// NewSHA1 legacy SHA-1 hasher func builder.
+func NewSHA1(salt string) Hasher {
+	return &sha1Hasher{salt: salt}
+}
+
+// Name returns algorithm name.
+func (s *sha1Hasher) Name() string {
+	return SHA1
+}
+
+// Hash makes legacy SHA-1 hash.
+func (s *sha1Hasher) Hash(password string) (string, error) {
+	hash := sha1.New() //nolint:gosec
+	hash.Write([]byte(password))
+	return fmt.Sprintf("%x", hash.Sum([]byte(s.salt))), nil
+}
+
+// Verify compares password with legacy SHA-1 hash.
+func (s *sha1Hasher) Verify(password, encoded string) (bool, error) {
+	hash, _ := s.Hash(password) //nolint:errcheck
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(encoded)) == 1, nil
+}
+
+// Identify legacy hashes have no "$" prefix.
+func (s *sha1Hasher) Identify(encoded string) bool {
+	return encoded != "" && !strings.HasPrefix(encoded, "$")
+}
+
+// NeedsRehash legacy hash always needs rehash.
+func (s *sha1Hasher) NeedsRehash(_ string) bool {
+	return true
+}
@@ -0,0 +1,400 @@
+// Package jsonpatch Package jsonpatch
+//
+// Patches of JSON documents: JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902).
+// Paths of JSON Patch operations are JSON Pointers (RFC 6901), e.g. /tags/0 or /a~1b for key "a/b".
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// patch errors, returned errors wrap them.
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrTestFailed   = errors.New("test operation failed")
+)
+
+// patchError error of patch which wraps one of patch errors, message is the reason only.
+type patchError struct {
+	err     error
+	message string
+}
+
+// Error message of error.
+func (e *patchError) Error() string {
+	return e.message
+}
+
+// Unwrap patch error.
+func (e *patchError) Unwrap() error {
+	return e.err
+}
+
+// patchErr error of patch with formatted message.
+func patchErr(err error, format string, args ...interface{}) error {
+	return &patchError{err: err, message: fmt.Sprintf(format, args...)}
+}
+
+// JSON Patch operations.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation operation of JSON Patch. Value is raw JSON, it is empty if operation has no value.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MergePatch apply JSON Merge Patch to doc: patch members replace members of doc, null members remove them.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, patchValue interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, patchErr(ErrInvalidPatch, "%s", err)
+	}
+
+	return json.Marshal(mergePatch(target, patchValue))
+}
+
+// mergePatch merge patch value into target, target objects are changed in place.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{}, len(patchObject))
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+
+	return targetObject
+}
+
+// Apply apply JSON Patch to doc. Operations are applied in order, patch fails as a whole if one of them fails.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var operations []Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, patchErr(ErrInvalidPatch, "%s", err)
+	}
+
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+
+	for i, operation := range operations {
+		var err error
+		if target, err = apply(target, operation); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// apply apply one operation to doc, doc with the change is returned.
+func apply(doc interface{}, operation Operation) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case OpAdd, OpReplace, OpTest:
+		value, err := operationValue(operation)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == OpAdd {
+			return add(doc, path, value)
+		}
+
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == OpTest {
+			if !reflect.DeepEqual(current, value) {
+				return nil, patchErr(ErrTestFailed, "value of %s is not equal to the test value", operation.Path)
+			}
+
+			return doc, nil
+		}
+
+		if len(path) == 0 {
+			return value, nil
+		}
+
+		if doc, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	case OpRemove:
+		return remove(doc, path)
+	case OpMove, OpCopy:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == OpCopy {
+			if value, err = deepCopy(value); err != nil {
+				return nil, err
+			}
+
+			return add(doc, path, value)
+		}
+
+		if strings.HasPrefix(operation.Path+"/", operation.From+"/") && operation.Path != operation.From {
+			return nil, patchErr(ErrInvalidPatch, "%s can not be moved into its child %s",
+				operation.From, operation.Path)
+		}
+
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	}
+
+	return nil, patchErr(ErrInvalidPatch, "unknown op %q", operation.Op)
+}
+
+// operationValue decoded value of operation.
+func operationValue(operation Operation) (interface{}, error) {
+	if len(operation.Value) == 0 {
+		return nil, patchErr(ErrInvalidPatch, "%s operation has no value", operation.Op)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(operation.Value, &value); err != nil {
+		return nil, patchErr(ErrInvalidPatch, "%s", err)
+	}
+
+	return value, nil
+}
+
+// deepCopy copy of decoded JSON value.
+func deepCopy(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied interface{}
+	err = json.Unmarshal(raw, &copied)
+
+	return copied, err
+}
+
+// parsePointer reference tokens of JSON Pointer, empty pointer is the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, patchErr(ErrInvalidPatch, "path %q does not start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// get value of doc at path.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for i, token := range path {
+		var err error
+		if doc, err = child(doc, token, path[:i+1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// add add value to doc at path: member of object is set, value is inserted into array, "-" is the end of array.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return change(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			parent[token] = value
+			return parent, nil
+		case []interface{}:
+			if token == "-" {
+				return append(parent, value), nil
+			}
+
+			i, err := index(token, len(parent)+1, path)
+			if err != nil {
+				return nil, err
+			}
+
+			parent = append(parent, nil)
+			copy(parent[i+1:], parent[i:])
+			parent[i] = value
+
+			return parent, nil
+		}
+
+		return nil, notContainer(path)
+	})
+}
+
+// remove remove value of doc at path, it must exist.
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, patchErr(ErrInvalidPatch, "document can not be removed")
+	}
+
+	return change(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := parent[token]; !ok {
+				return nil, notFound(path)
+			}
+
+			delete(parent, token)
+
+			return parent, nil
+		case []interface{}:
+			i, err := index(token, len(parent), path)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(parent[:i], parent[i+1:]...), nil
+		}
+
+		return nil, notContainer(path)
+	})
+}
+
+// change change parent of the last path token by fn, changed parent replaces the old one in doc.
+func change(
+	doc interface{},
+	path []string,
+	fn func(parent interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	node, err := child(doc, path[0], path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	if node, err = change(node, path[1:], fn); err != nil {
+		return nil, err
+	}
+
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		doc[path[0]] = node
+	case []interface{}:
+		i, _ := strconv.Atoi(path[0])
+		doc[i] = node
+	}
+
+	return doc, nil
+}
+
+// child member of object or item of array by token, path is pointer to the child for errors.
+func child(doc interface{}, token string, path []string) (interface{}, error) {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		value, ok := doc[token]
+		if !ok {
+			return nil, notFound(path)
+		}
+
+		return value, nil
+	case []interface{}:
+		i, err := index(token, len(doc), path)
+		if err != nil {
+			return nil, err
+		}
+
+		return doc[i], nil
+	}
+
+	return nil, notFound(path)
+}
+
+// index array index by token, it must be less than size.
+func index(token string, size int, path []string) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, patchErr(ErrInvalidPatch, "%s is not a valid array index", pointer(path))
+	}
+
+	if i >= size {
+		return 0, notFound(path)
+	}
+
+	return i, nil
+}
+
+// notFound error of missing path.
+func notFound(path []string) error {
+	return patchErr(ErrInvalidPatch, "path %s does not exist", pointer(path))
+}
+
+// notContainer error of path which parent is neither object nor array.
+func notContainer(path []string) error {
+	return patchErr(ErrInvalidPatch, "parent of path %s is not an object or array", pointer(path))
+}
+
+// pointer JSON Pointer of reference tokens.
+func pointer(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+
+	return b.String()
+}
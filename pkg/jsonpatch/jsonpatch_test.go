@@ -0,0 +1,151 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	testTable := []struct {
+		doc      string
+		patch    string
+		expected string
+		testName string
+	}{
+		{
+			doc:      `{"title":"a","info":"b"}`,
+			patch:    `{"title":"c"}`,
+			expected: `{"info":"b","title":"c"}`,
+			testName: "Test-1-Replace member",
+		},
+		{
+			doc:      `{"title":"a","info":"b"}`,
+			patch:    `{"info":null}`,
+			expected: `{"title":"a"}`,
+			testName: "Test-2-Remove member",
+		},
+		{
+			doc:      `{"a":{"b":1,"c":2}}`,
+			patch:    `{"a":{"c":null,"d":3},"e":[1]}`,
+			expected: `{"a":{"b":1,"d":3},"e":[1]}`,
+			testName: "Test-3-Nested objects",
+		},
+		{
+			doc:      `{"a":1}`,
+			patch:    `["b"]`,
+			expected: `["b"]`,
+			testName: "Test-4-Patch is not an object",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actual, err := MergePatch([]byte(testCase.doc), []byte(testCase.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.expected, string(actual))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	testTable := []struct {
+		doc      string
+		patch    string
+		expected string
+		testName string
+	}{
+		{
+			doc:      `{"title":"a","info":"b"}`,
+			patch:    `[{"op":"replace","path":"/title","value":"c"},{"op":"remove","path":"/info"}]`,
+			expected: `{"title":"c"}`,
+			testName: "Test-1-Replace and remove",
+		},
+		{
+			doc:      `{"list":[1,3]}`,
+			patch:    `[{"op":"add","path":"/list/1","value":2},{"op":"add","path":"/list/-","value":4}]`,
+			expected: `{"list":[1,2,3,4]}`,
+			testName: "Test-2-Add to array",
+		},
+		{
+			doc:      `{"a":{"b":"x"},"c":{}}`,
+			patch:    `[{"op":"move","from":"/a/b","path":"/c/b"},{"op":"copy","from":"/c","path":"/d"}]`,
+			expected: `{"a":{},"c":{"b":"x"},"d":{"b":"x"}}`,
+			testName: "Test-3-Move and copy",
+		},
+		{
+			doc:      `{"a/b":{"~c":1}}`,
+			patch:    `[{"op":"test","path":"/a~1b/~0c","value":1.0},{"op":"replace","path":"/a~1b/~0c","value":null}]`,
+			expected: `{"a/b":{"~c":null}}`,
+			testName: "Test-4-Escaped pointer and test",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actual, err := Apply([]byte(testCase.doc), []byte(testCase.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.expected, string(actual))
+		})
+	}
+}
+
+func TestApplyError(t *testing.T) {
+	testTable := []struct {
+		patch       string
+		expected    error
+		expectedMsg string
+		testName    string
+	}{
+		{
+			patch:       `{"op":"add"}`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: "json: cannot unmarshal object into Go value of type []jsonpatch.Operation",
+			testName:    "Test-1-Patch is not an array",
+		},
+		{
+			patch:       `[{"op":"replace","path":"/title","value":"c"},{"op":"remove","path":"/missing"}]`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: "operation 1: path /missing does not exist",
+			testName:    "Test-2-Missing path",
+		},
+		{
+			patch:       `[{"op":"add","path":"/title"}]`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: "operation 0: add operation has no value",
+			testName:    "Test-3-No value",
+		},
+		{
+			patch:       `[{"op":"test","path":"/title","value":"b"}]`,
+			expected:    ErrTestFailed,
+			expectedMsg: "operation 0: value of /title is not equal to the test value",
+			testName:    "Test-4-Test failed",
+		},
+		{
+			patch:       `[{"op":"add","path":"/list/01","value":1}]`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: "operation 0: /list/01 is not a valid array index",
+			testName:    "Test-5-Bad array index",
+		},
+		{
+			patch:       `[{"op":"move","from":"/list","path":"/list/0"}]`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: "operation 0: /list can not be moved into its child /list/0",
+			testName:    "Test-6-Move into child",
+		},
+		{
+			patch:       `[{"op":"merge","path":"/title"}]`,
+			expected:    ErrInvalidPatch,
+			expectedMsg: `operation 0: unknown op "merge"`,
+			testName:    "Test-7-Unknown op",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			_, err := Apply([]byte(`{"title":"a","list":[0]}`), []byte(testCase.patch))
+			require.ErrorIs(t, err, testCase.expected)
+			require.EqualError(t, err, testCase.expectedMsg)
+		})
+	}
+}
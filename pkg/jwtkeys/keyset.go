@@ -0,0 +1,202 @@
+// Package jwtkeys Package jwtkeys
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt"
+
+	"web/internal/config"
+)
+
+// supported algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// keyset errors.
+var (
+	ErrSigningMethod   = errors.New("invalid signing method")
+	ErrUnknownKeyID    = errors.New("unknown signing key id")
+	ErrEmptySigningKey = errors.New("jwt signing key is not set")
+	ErrUnknownKeyType  = errors.New("unsupported key type")
+)
+
+// Key signing or verification key.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	signKey interface{}
+	Public  interface{}
+}
+
+// KeySet one signing key and all keys active for verification (kid -> key).
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet key set func builder.
+func NewKeySet(signing *Key, verification ...*Key) *KeySet {
+	keys := make(map[string]*Key, len(verification)+1)
+	for _, key := range verification {
+		keys[key.ID] = key
+	}
+	keys[signing.ID] = signing
+
+	return &KeySet{
+		signing: signing,
+		keys:    keys,
+	}
+}
+
+// NewKeySetFromConfig key set builder from config. Keys are taken from files or from env values.
+func NewKeySetFromConfig(cfg *config.Config) (*KeySet, error) {
+	material, err := readKey(cfg.JWT.SigningKey, cfg.JWT.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signing, err := NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, material)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := make([]*Key, 0, len(cfg.JWT.VerificationKeys)+len(cfg.JWT.VerificationKeyFiles))
+
+	for kid, value := range cfg.JWT.VerificationKeys {
+		key, err := NewVerificationKey(kid, []byte(value))
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	for kid, path := range cfg.JWT.VerificationKeyFiles {
+		material, err := readKey("", path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := NewVerificationKey(kid, material)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	return NewKeySet(signing, verification...), nil
+}
+
+// NewSigningKey signing key builder. Material is HMAC secret or PEM private key.
+func NewSigningKey(kid, algorithm string, material []byte) (*Key, error) {
+	switch algorithm {
+	case HS256:
+		return &Key{ID: kid, Method: jwt.SigningMethodHS256, signKey: material, Public: material}, nil
+	case RS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, signKey: private, Public: &private.PublicKey}, nil
+	case EdDSA:
+		private, err := jwt.ParseEdPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, err
+		}
+		edKey, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return nil, ErrUnknownKeyType
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: edKey, Public: edKey.Public()}, nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrSigningMethod, algorithm)
+}
+
+// NewVerificationKey verification key builder from PEM public key, algorithm is taken from key type.
+func NewVerificationKey(kid string, material []byte) (*Key, error) {
+	block, _ := pem.Decode(material)
+	if block == nil {
+		return nil, fmt.Errorf("key '%s': %w", kid, jwt.ErrKeyMustBePEMEncoded)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key '%s': %w", kid, err)
+	}
+
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Public: key}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Public: key}, nil
+	}
+
+	return nil, fmt.Errorf("key '%s': %w", kid, ErrUnknownKeyType)
+}
+
+// Sign make signed token with signing key, key id is put in "kid" header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.Method, claims)
+	token.Header["kid"] = k.signing.ID
+
+	return token.SignedString(k.signing.signKey)
+}
+
+// Keyfunc choose verification key by token "kid" header for jwt.Parse.
+// Tokens without "kid" are verified with the signing key.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	key := k.signing
+
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok = k.keys[kid]; !ok {
+			return nil, ErrUnknownKeyID
+		}
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrSigningMethod
+	}
+
+	return key.Public, nil
+}
+
+// JWKS public keys of key set in JSON Web Key Set format. HMAC secrets are never published.
+func (k *KeySet) JWKS() *JWKS {
+	jwks := &JWKS{Keys: make([]JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		if jwk, ok := newJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
+
+// readKey key from env value or from file.
+func readKey(value, path string) ([]byte, error) {
+	if value != "" {
+		return []byte(value), nil
+	}
+
+	if path == "" {
+		return nil, ErrEmptySigningKey
+	}
+
+	return os.ReadFile(path) //nolint:gosec
+}
@@ -0,0 +1,113 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"log"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet_Keyfunc(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	rsaSigning := &Key{ID: "new", Method: jwt.SigningMethodRS256, signKey: rsaKey, Public: &rsaKey.PublicKey}
+	edSigning := &Key{ID: "old", Method: jwt.SigningMethodEdDSA, signKey: edPrivate, Public: edPublic}
+	hmacSigning := &Key{ID: "hmac", Method: jwt.SigningMethodHS256, signKey: []byte("secret"), Public: []byte("secret")}
+
+	edPEM, err := x509.MarshalPKIXPublicKey(edPublic)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	edVerification, err := NewVerificationKey("old", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: edPEM}))
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	keySet := NewKeySet(rsaSigning, edVerification)
+
+	testTable := []struct {
+		issuer   *KeySet
+		err      error
+		testName string
+	}{
+		{
+			issuer:   keySet,
+			testName: "Test-1-Signing key",
+		},
+		{
+			issuer:   NewKeySet(edSigning),
+			testName: "Test-2-Rotated verification key",
+		},
+		{
+			issuer:   NewKeySet(hmacSigning),
+			err:      ErrUnknownKeyID,
+			testName: "Test-3-Unknown kid",
+		},
+		{
+			issuer:   NewKeySet(&Key{ID: "new", Method: jwt.SigningMethodHS256, signKey: []byte("secret")}),
+			err:      ErrSigningMethod,
+			testName: "Test-4-Algorithm confusion",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			token, err := testCase.issuer.Sign(jwt.StandardClaims{Subject: "1"})
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+
+			_, err = jwt.Parse(token, keySet.Keyfunc)
+			if testCase.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			validationErr, ok := err.(*jwt.ValidationError)
+			require.True(t, ok)
+			require.Equal(t, testCase.err, validationErr.Inner)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	keySet := NewKeySet(
+		&Key{ID: "ed", Method: jwt.SigningMethodEdDSA, signKey: edPrivate, Public: edPublic},
+		&Key{ID: "hmac", Method: jwt.SigningMethodHS256, Public: []byte("secret")},
+	)
+
+	expected := &JWKS{
+		Keys: []JWK{
+			{
+				Kty: "OKP",
+				Kid: "ed",
+				Use: "sig",
+				Alg: "EdDSA",
+				Crv: "Ed25519",
+				X:   jwt.EncodeSegment(edPublic),
+			},
+		},
+	}
+
+	require.Equal(t, expected, keySet.JWKS())
+}
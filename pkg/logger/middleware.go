@@ -11,6 +11,9 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// requestIDKey context key of request id.
+type requestIDKey struct{}
+
 // LogMiddleware for app.
 type LogMiddleware struct {
 	*zap.Logger
@@ -36,6 +39,9 @@ func (l LogMiddleware) Handler(next httprouter.Handle) httprouter.Handle {
 
 		userLog := l.With(requestID)
 		ctx := context.WithValue(r.Context(), "logger", userLog) //nolint:revive,staticcheck
+		ctx = context.WithValue(ctx, requestIDKey{}, reqID)
+
+		w.Header().Set("X-Request-ID", reqID)
 
 		rw := newResponseWriter(w)
 
@@ -47,7 +53,7 @@ func (l LogMiddleware) Handler(next httprouter.Handle) httprouter.Handle {
 			zap.Int("status_code", rw.status),
 		}
 
-		if rw.status == 500 || rw.status == 400 || rw.status == 401 {
+		if rw.status == 500 || rw.status == 400 || rw.status == 401 || rw.status == 423 || rw.status == 429 {
 			l.Error("failed", labels...)
 			return
 		}
@@ -86,3 +92,9 @@ func (r *responseWriterDelegator) Write(b []byte) (int, error) {
 func LogFromContext(ctx context.Context) *zap.Logger {
 	return ctx.Value("logger").(*zap.Logger)
 }
+
+// RequestIDFromContext returns request id, empty outside of logger middleware.
+func RequestIDFromContext(ctx context.Context) string {
+	reqID, _ := ctx.Value(requestIDKey{}).(string)
+	return reqID
+}
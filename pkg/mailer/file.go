@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// file permissions of mail directory and files.
+const (
+	dirPerm  = 0o750
+	filePerm = 0o600
+)
+
+// fileSender writes mails as .eml files, for local development.
+type fileSender struct {
+	dir   string
+	from  string
+	count uint64
+}
+
+// NewFileSender file sender func builder.
+func NewFileSender(dir, from string) Sender {
+	return &fileSender{dir: dir, from: from}
+}
+
+// Send write message to a new file in sender directory.
+func (f *fileSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.dir, dirPerm); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%d.eml", time.Now().UnixNano(), atomic.AddUint64(&f.count, 1))
+
+	return os.WriteFile(filepath.Join(f.dir, name), build(f.from, msg), filePerm)
+}
@@ -0,0 +1,68 @@
+// Package mailer Package mailer
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"time"
+
+	"go.uber.org/zap"
+
+	"web/internal/config"
+)
+
+// senders names.
+const (
+	SMTP = "smtp"
+	File = "file"
+	Log  = "log"
+)
+
+// ErrUnknownSender unknown sender name in config.
+var ErrUnknownSender = errors.New("unknown mail sender")
+
+// Message plain text mail.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender mail delivery interface.
+type Sender interface {
+	// Send delivers message to recipient.
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender sender func builder by config.
+func NewSender(cfg *config.Config, logger *zap.Logger) (Sender, error) {
+	switch cfg.Mail.Sender {
+	case SMTP:
+		return NewSMTPSender(cfg.Mail), nil
+	case File:
+		return NewFileSender(cfg.Mail.FileDir, cfg.Mail.From), nil
+	case Log:
+		return NewLogSender(logger, cfg.Mail.From), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSender, cfg.Mail.Sender)
+	}
+}
+
+// build makes RFC 5322 message with headers.
+func build(from string, msg Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+
+	return buf.Bytes()
+}
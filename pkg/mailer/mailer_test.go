@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender_Send(t *testing.T) {
+	dir := t.TempDir()
+	sender := NewFileSender(dir, "no-reply@localhost")
+
+	msg := Message{To: "test@example.com", Subject: "Reset password", Body: "token"}
+	require.NoError(t, sender.Send(context.Background(), msg))
+	require.NoError(t, sender.Send(context.Background(), msg))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	content := string(data)
+	require.Contains(t, content, "From: no-reply@localhost\r\n")
+	require.Contains(t, content, "To: test@example.com\r\n")
+	require.Contains(t, content, "Subject: Reset password\r\n")
+	require.True(t, strings.HasSuffix(content, "\r\n\r\ntoken"))
+}
+
+func TestFileSender_SendCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sender := NewFileSender(t.TempDir(), "no-reply@localhost")
+	require.ErrorIs(t, sender.Send(ctx, Message{To: "test@example.com"}), context.Canceled)
+}
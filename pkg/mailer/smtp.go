@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+
+	"web/internal/config"
+)
+
+// smtpSender sends mails through SMTP server.
+type smtpSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPSender smtp sender func builder. Plain auth is used if username is set.
+func NewSMTPSender(cfg config.Mail) Sender {
+	sender := &smtpSender{
+		addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		from: cfg.From,
+	}
+
+	if cfg.SMTPUsername != "" {
+		sender.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return sender
+}
+
+// Send deliver message with STARTTLS if server supports it.
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, build(s.from, msg))
+}
@@ -0,0 +1,306 @@
+// Package tagquery Package tagquery
+//
+// Boolean tag expressions like `work AND (urgent OR blocked) AND NOT archived`.
+// NOT binds tighter than AND, AND binds tighter than OR. Keywords are case-insensitive,
+// tag names with spaces, parentheses or keyword names are written in double quotes: "to do".
+package tagquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// limits of expression, they keep compiled SQL small.
+const (
+	MaxLength = 1000
+	MaxTags   = 32
+	MaxDepth  = 16
+)
+
+// Expr node of expression AST: Tag, Not, And or Or.
+type Expr interface {
+	expr()
+}
+
+// Tag note has the tag.
+type Tag struct {
+	Name string
+}
+
+// Not operand is false.
+type Not struct {
+	Operand Expr
+}
+
+// And all operands are true.
+type And struct {
+	Operands []Expr
+}
+
+// Or any operand is true.
+type Or struct {
+	Operands []Expr
+}
+
+func (Tag) expr() {}
+func (Not) expr() {}
+func (And) expr() {}
+func (Or) expr()  {}
+
+// Error parse error, Pos is 1-based character position of the offending token in expression.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+// Error error message with position.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// token kinds.
+const (
+	tokenEOF = iota
+	tokenTag
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+// token lexeme of expression.
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// describe token for error messages.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenTag:
+		return fmt.Sprintf("tag %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// Parse parse expression to AST, parse errors are *Error.
+func Parse(expression string) (Expr, error) {
+	input := []rune(expression)
+	if len(input) > MaxLength {
+		return nil, &Error{Pos: MaxLength + 1, Msg: fmt.Sprintf("expression is longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &Error{Pos: next.pos, Msg: fmt.Sprintf("unexpected %s, expected AND, OR or end", next.describe())}
+	}
+
+	return expr, nil
+}
+
+// lex split expression to tokens, the last one is tokenEOF.
+func lex(input []rune) ([]token, error) {
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(input); {
+		r := input[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == '"':
+			name, end, err := lexQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenTag, text: name, pos: pos})
+			i = end
+		default:
+			end := i
+			for end < len(input) && !unicode.IsSpace(input[end]) && !strings.ContainsRune(`()"`, input[end]) {
+				end++
+			}
+
+			word := string(input[i:end])
+			tokens = append(tokens, token{kind: keyword(word), text: word, pos: pos})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input) + 1}), nil
+}
+
+// lexQuoted read quoted tag name starting at input[start], backslash escapes the next character.
+func lexQuoted(input []rune, start int) (string, int, error) {
+	var name strings.Builder
+
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				name.WriteRune(input[i])
+			}
+		case '"':
+			if name.Len() == 0 {
+				return "", 0, &Error{Pos: start + 1, Msg: "empty tag name"}
+			}
+
+			return name.String(), i + 1, nil
+		default:
+			name.WriteRune(input[i])
+		}
+	}
+
+	return "", 0, &Error{Pos: start + 1, Msg: "unterminated quoted tag name"}
+}
+
+// keyword kind of unquoted word.
+func keyword(word string) int {
+	switch strings.ToUpper(word) {
+	case "AND":
+		return tokenAnd
+	case "OR":
+		return tokenOr
+	case "NOT":
+		return tokenNot
+	default:
+		return tokenTag
+	}
+}
+
+// parser recursive descent parser of tokens.
+type parser struct {
+	tokens []token
+	pos    int
+	tags   int
+}
+
+// peek current token.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consume current token.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// parseOr or := and ("OR" and)*.
+func (p *parser) parseOr(depth int) (Expr, error) {
+	operands, err := p.parseOperands(depth, tokenOr, p.parseAnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+
+	return Or{Operands: operands}, nil
+}
+
+// parseAnd and := unary ("AND" unary)*.
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	operands, err := p.parseOperands(depth, tokenAnd, p.parseUnary)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+
+	return And{Operands: operands}, nil
+}
+
+// parseOperands operands of binary operator separated by operator tokens.
+func (p *parser) parseOperands(depth, operator int, parseOperand func(int) (Expr, error)) ([]Expr, error) {
+	operand, err := parseOperand(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Expr{operand}
+
+	for p.peek().kind == operator {
+		p.next()
+
+		operand, err := parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	return operands, nil
+}
+
+// parseUnary unary := "NOT" unary | "(" or ")" | tag.
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	t := p.next()
+
+	if depth > MaxDepth {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expression is nested deeper than %d levels", MaxDepth)}
+	}
+
+	switch t.kind {
+	case tokenNot:
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{Operand: operand}, nil
+	case tokenLParen:
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("unexpected %s, expected \")\"", closing.describe())}
+		}
+
+		return expr, nil
+	case tokenTag:
+		if p.tags++; p.tags > MaxTags {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expression has more than %d tags", MaxTags)}
+		}
+
+		return Tag{Name: t.text}, nil
+	default:
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s, expected tag, NOT or \"(\"", t.describe())}
+	}
+}
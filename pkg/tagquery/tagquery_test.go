@@ -0,0 +1,118 @@
+package tagquery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testTable := []struct {
+		expression string
+		expected   Expr
+		testName   string
+	}{
+		{
+			expression: "work",
+			expected:   Tag{Name: "work"},
+			testName:   "Test-1-Tag",
+		},
+		{
+			expression: "work AND (urgent OR blocked) AND NOT archived",
+			expected: And{Operands: []Expr{
+				Tag{Name: "work"},
+				Or{Operands: []Expr{Tag{Name: "urgent"}, Tag{Name: "blocked"}}},
+				Not{Operand: Tag{Name: "archived"}},
+			}},
+			testName: "Test-2-Nested expression",
+		},
+		{
+			expression: "a or b and not c",
+			expected: Or{Operands: []Expr{
+				Tag{Name: "a"},
+				And{Operands: []Expr{Tag{Name: "b"}, Not{Operand: Tag{Name: "c"}}}},
+			}},
+			testName: "Test-3-Precedence and lowercase keywords",
+		},
+		{
+			expression: `"to do" AND "and" AND "say \"hi\""`,
+			expected: And{Operands: []Expr{
+				Tag{Name: "to do"}, Tag{Name: "and"}, Tag{Name: `say "hi"`},
+			}},
+			testName: "Test-4-Quoted tags",
+		},
+		{
+			expression: "work/projects/alpha-2",
+			expected:   Tag{Name: "work/projects/alpha-2"},
+			testName:   "Test-5-Tag with punctuation",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actual, err := Parse(testCase.expression)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	testTable := []struct {
+		expression string
+		expected   string
+		testName   string
+	}{
+		{
+			expression: "",
+			expected:   "unexpected end of expression, expected tag, NOT or \"(\" at position 1",
+			testName:   "Test-1-Empty",
+		},
+		{
+			expression: "work AND (urgent OR blocked",
+			expected:   "unexpected end of expression, expected \")\" at position 28",
+			testName:   "Test-2-Unclosed parenthesis",
+		},
+		{
+			expression: "work urgent",
+			expected:   "unexpected tag \"urgent\", expected AND, OR or end at position 6",
+			testName:   "Test-3-Missing operator",
+		},
+		{
+			expression: "work AND OR urgent",
+			expected:   "unexpected \"OR\", expected tag, NOT or \"(\" at position 10",
+			testName:   "Test-4-Missing operand",
+		},
+		{
+			expression: "work)",
+			expected:   "unexpected \")\", expected AND, OR or end at position 5",
+			testName:   "Test-5-Unopened parenthesis",
+		},
+		{
+			expression: `work AND "urgent`,
+			expected:   "unterminated quoted tag name at position 10",
+			testName:   "Test-6-Unterminated quote",
+		},
+		{
+			expression: strings.Repeat("(", MaxDepth+1) + "work" + strings.Repeat(")", MaxDepth+1),
+			expected:   "expression is nested deeper than 16 levels at position 18",
+			testName:   "Test-7-Too deep",
+		},
+		{
+			expression: "t" + strings.Repeat(" OR t", MaxTags),
+			expected:   "expression has more than 32 tags at position 161",
+			testName:   "Test-8-Too many tags",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			_, err := Parse(testCase.expression)
+
+			var parseErr *Error
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, testCase.expected, err.Error())
+		})
+	}
+}
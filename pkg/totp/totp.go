@@ -0,0 +1,105 @@
+// Package totp Package totp
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RFC 6238 params, the defaults supported by all authenticator apps.
+const (
+	Digits    = 6
+	Period    = 30
+	secretLen = 20
+)
+
+// ErrInvalidSecret secret is not base32 string.
+var ErrInvalidSecret = errors.New("invalid totp secret")
+
+// encoding base32 without padding as authenticator apps expect.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret make random base32 secret.
+func GenerateSecret() (string, error) {
+	raw := make([]byte, secretLen)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(raw), nil
+}
+
+// Step time step number of t.
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Code one-time code for time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+
+	msg := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg) //nolint:errcheck,gosec
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f                                    //nolint:gomnd
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff //nolint:gomnd
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate check code for time t, skew is allowed steps before and after current one.
+// Returns the matched step to let caller reject reused codes.
+func Validate(secret, code string, t time.Time, skew int) (int64, bool, error) {
+	current := Step(t)
+
+	for i := -skew; i <= skew; i++ {
+		step := current + int64(i)
+
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// URI provisioning URI (otpauth://) for authenticator apps, usually shown as QR code.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(Period))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
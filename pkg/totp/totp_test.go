@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B SHA1 test vectors, last 6 digits.
+func TestCode(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	testTable := []struct {
+		unix     int64
+		expected string
+		testName string
+	}{
+		{unix: 59, expected: "287082", testName: "Test-1-59"},
+		{unix: 1111111109, expected: "081804", testName: "Test-2-1111111109"},
+		{unix: 1234567890, expected: "005924", testName: "Test-3-1234567890"},
+		{unix: 2000000000, expected: "279037", testName: "Test-4-2000000000"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actual, err := Code(secret, Step(time.Unix(testCase.unix, 0)))
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	previous, err := Code(secret, Step(now)-1)
+	require.NoError(t, err)
+	old, err := Code(secret, Step(now)-3)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		code     string
+		skew     int
+		expected bool
+		testName string
+	}{
+		{code: previous, skew: 1, expected: true, testName: "Test-1-Previous step"},
+		{code: previous, skew: 0, expected: false, testName: "Test-2-No skew"},
+		{code: old, skew: 1, expected: false, testName: "Test-3-Too old"},
+		{code: "abc", skew: 1, expected: false, testName: "Test-4-Bad code"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.testName, func(t *testing.T) {
+			_, actual, err := Validate(secret, testCase.code, now, testCase.skew)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+
+	_, _, err = Validate("not base32!", "123456", now, 1)
+	require.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestURI(t *testing.T) {
+	actual := URI("note service", "alice", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/note%20service:alice?algorithm=SHA1&digits=6&issuer=note+service"+
+		"&period=30&secret=JBSWY3DPEHPK3PXP", actual)
+}